@@ -0,0 +1,117 @@
+package main
+
+import (
+	"awesomeProject/ipcounter"
+	"awesomeProject/ipcounter/counters/header"
+	"awesomeProject/ipcounter/counters/prefix"
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+)
+
+// compareCommand is the subcommand comparing the unique IPs of several inputs
+const compareCommand = "compare"
+
+// runCompare counts each input separately and prints the union, intersection and differences of every pair.
+// An input is either a counter saved with -save or a file of IPs counted with the -counter type.
+func runCompare(args []string) {
+	flags := flag.NewFlagSet(compareCommand, flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s %s [flags] input1 input2 [input...]\n", os.Args[0], compareCommand)
+		flags.PrintDefaults()
+	}
+	counterType := flags.String("counter", hyperLogLogPlusType, "Type of counter filled from the raw inputs (hyperloglogplus, hyperloglog, bitmap, roaring, set, frequency or prefix)")
+	hashName := flags.String("hash", "none", "Hash function applied to the IPs of the raw inputs: none, fnv1a, fmix32 or murmur3")
+	hashSeed := flags.Uint("hash-seed", 0, "Seed of the murmur3 hash function")
+	_ = flags.Parse(args)
+
+	if flags.NArg() < 2 {
+		flags.Usage()
+		os.Exit(1)
+	}
+	hasher, err := ipcounter.ParseHasher(*hashName, uint32(*hashSeed))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	inputs := flags.Args()
+	ipMaps := make([]ipcounter.IPMap, len(inputs))
+	for i, input := range inputs {
+		ipMaps[i], err = readInput(input, *counterType, hasher)
+		if err != nil {
+			log.Fatalf("Failed to read %s: %v", input, err)
+		}
+		fmt.Printf("%s: %d unique\n", input, ipMaps[i].Count())
+	}
+
+	for i := range inputs {
+		for j := i + 1; j < len(inputs); j++ {
+			counts, err := ipcounter.CompareIPMaps(ipMaps[i], ipMaps[j])
+			if err != nil {
+				log.Fatalf("Failed to compare %s with %s: %v", inputs[i], inputs[j], err)
+			}
+			printSetCounts(inputs[i], inputs[j], counts)
+		}
+	}
+}
+
+// printSetCounts prints the comparison of two inputs
+func printSetCounts(a, b string, counts ipcounter.SetCounts) {
+	kind := "exact"
+	if !counts.Exact {
+		kind = "estimated"
+	}
+	fmt.Printf("%s vs %s (%s):\n", a, b, kind)
+	fmt.Printf("  union:                %d\n", counts.Union)
+	fmt.Printf("  intersection:         %d\n", counts.Intersection)
+	fmt.Printf("  only in %s: %d\n", a, counts.OnlyA)
+	fmt.Printf("  only in %s: %d\n", b, counts.OnlyB)
+	fmt.Printf("  symmetric difference: %d\n", counts.SymmetricDifference())
+	fmt.Printf("  jaccard similarity:   %.4f\n", counts.Jaccard())
+}
+
+// readInput restores a saved counter, or counts the IPs of a raw file with a new counter of the type
+func readInput(path, counterType string, hasher ipcounter.Hasher) (ipcounter.IPMap, error) {
+	saved, err := isSavedCounter(path)
+	if err != nil {
+		return nil, err
+	}
+	if saved {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return ipcounter.UnmarshalIPMap(data)
+	}
+
+	counter, err := createCounter(counterType, hasher, []uint8{prefix.MinLength})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := counter.CountIPFromFile(path); err != nil {
+		return nil, err
+	}
+	return counter.IPMap(), nil
+}
+
+// isSavedCounter reports whether the file starts with the magic of a serialized counter
+func isSavedCounter(path string) (bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	magic := make([]byte, len(header.Magic))
+	if _, err := io.ReadFull(file, magic); err != nil {
+		// Files shorter than the magic are raw
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return false, nil
+		}
+		return false, err
+	}
+	return bytes.Equal(magic, []byte(header.Magic)), nil
+}
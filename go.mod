@@ -1 +1,3 @@
 module awesomeProject
+
+go 1.23
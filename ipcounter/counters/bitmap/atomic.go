@@ -0,0 +1,141 @@
+package bitmap
+
+import (
+	"awesomeProject/ipcounter/counters/header"
+	"fmt"
+	"math/bits"
+	"sync/atomic"
+)
+
+// page is the words of a page of an Atomic bitmap, the last page is allocated whole
+type page [pageWords]uint64
+
+// Atomic is a fixed size set of bits that can be set concurrently without locks: a bit is set by an atomic OR
+// of its word and a page is allocated on first use by a compare-and-swap. Bits are only cleared all at once by Clear.
+// The set bits aren't counted as they are set, Count computes them by popcount.
+type Atomic struct {
+	pages       []atomic.Pointer[page]
+	cardinality uint64
+	hashFunc    header.HashFunc
+}
+
+// NewAtomic creates a new Atomic bitmap allocating its pages on first use
+func NewAtomic(size uint64) (*Atomic, error) {
+	if size < 1 || size > MaxSize {
+		return nil, fmt.Errorf("invalid size: %d, must be between 1 and %d", size, MaxSize)
+	}
+	words := int((size + 63) >> 6)
+	return &Atomic{
+		pages:       make([]atomic.Pointer[page], (words+pageWords-1)>>pageWordBits),
+		cardinality: size,
+	}, nil
+}
+
+// SetBit sets the bit, it is safe for concurrent use. It reports false if the position is past the size.
+func (a *Atomic) SetBit(position uint32) bool {
+	if uint64(position) >= a.cardinality {
+		return false
+	}
+
+	pointer := &a.pages[position>>pageBits]
+	p := pointer.Load()
+	if p == nil {
+		// Only the first worker to allocate the page stores it, the others use it
+		pointer.CompareAndSwap(nil, new(page))
+		p = pointer.Load()
+	}
+	atomic.OrUint64(&p[position>>6&(pageWords-1)], 1<<(position&63))
+	return true
+}
+
+func (a *Atomic) GetBit(position uint32) bool {
+	if uint64(position) >= a.cardinality {
+		return false
+	}
+
+	p := a.pages[position>>pageBits].Load()
+	return p != nil && atomic.LoadUint64(&p[position>>6&(pageWords-1)])&(1<<(position&63)) != 0
+}
+
+func (a *Atomic) Size() uint64 {
+	return a.cardinality
+}
+
+// Count returns the number of set bits by popcount of the allocated pages
+func (a *Atomic) Count() uint64 {
+	var count uint64
+	for i := range a.pages {
+		p := a.pages[i].Load()
+		if p == nil {
+			continue
+		}
+		for j := range p {
+			count += uint64(bits.OnesCount64(atomic.LoadUint64(&p[j])))
+		}
+	}
+	return count
+}
+
+// Clear clears all bits and keeps the allocated pages for reuse, it must not be called concurrently with SetBit
+func (a *Atomic) Clear() {
+	for i := range a.pages {
+		if p := a.pages[i].Load(); p != nil {
+			*p = page{}
+		}
+	}
+}
+
+// Merge sets the bits of the other bitmap, both bitmaps must have the same size.
+// It is safe for concurrent use with SetBit.
+func (a *Atomic) Merge(other *BitMap) error {
+	if a.cardinality != other.cardinality {
+		return fmt.Errorf("can't combine bitmaps of different sizes: %d and %d", a.cardinality, other.cardinality)
+	}
+	if a.hashFunc != other.hashFunc {
+		return fmt.Errorf("can't combine bitmaps of different hash functions: %v and %v", a.hashFunc, other.hashFunc)
+	}
+
+	for i, otherPage := range other.pages {
+		if atomic.LoadUint32(&other.pageCounts[i]) == 0 {
+			continue
+		}
+		pointer := &a.pages[i]
+		pointer.CompareAndSwap(nil, new(page))
+		p := pointer.Load()
+		for j, word := range otherPage {
+			if word != 0 {
+				atomic.OrUint64(&p[j], word)
+			}
+		}
+	}
+	return nil
+}
+
+// BitMap returns a paged copy of the bitmap for the operations of BitMap, e.g. iteration, set operations
+// or serialization. Bits set concurrently with the copy may be missing from it.
+func (a *Atomic) BitMap() *BitMap {
+	b, _ := NewPaged(a.cardinality)
+	b.hashFunc = a.hashFunc
+	for i := range a.pages {
+		p := a.pages[i].Load()
+		if p == nil {
+			continue
+		}
+		words := make([]uint64, b.pageLen(i))
+		for j := range words {
+			words[j] = atomic.LoadUint64(&p[j])
+		}
+		b.pages[i] = words
+	}
+	b.recount()
+	return b
+}
+
+// SetHashFunc records the hash function applied to the positions, it is stored when the bitmap is serialized
+func (a *Atomic) SetHashFunc(hashFunc header.HashFunc) {
+	a.hashFunc = hashFunc
+}
+
+func (a *Atomic) HashFunc() header.HashFunc {
+	return a.hashFunc
+}
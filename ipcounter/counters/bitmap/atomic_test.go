@@ -0,0 +1,105 @@
+package bitmap
+
+import (
+	"awesomeProject/ipcounter/counters/header"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestAtomic_SetBit(t *testing.T) {
+	const size = 3*PageSize*8 + 5
+	a, err := NewAtomic(size)
+	if err != nil {
+		t.Fatalf("NewAtomic() error = %v", err)
+	}
+	if _, err := NewAtomic(0); err == nil {
+		t.Errorf("Expected an error for size 0")
+	}
+
+	// The workers set overlapping bits of the same pages
+	const workers = 8
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for position := uint32(w % 3); position < size; position += 3 {
+				a.SetBit(position)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Every bit is set by 3 workers, e.g. bit 7 by workers 1, 4 and 7
+	var expected []uint32
+	for position := uint32(0); position < size; position++ {
+		expected = append(expected, position)
+	}
+	if a.Count() != uint64(len(expected)) {
+		t.Errorf("Count() = %d, want %d", a.Count(), len(expected))
+	}
+	if a.SetBit(size) || a.GetBit(size) {
+		t.Errorf("Positions past the size should be rejected")
+	}
+	if !a.GetBit(3) || !a.GetBit(size-1) {
+		t.Errorf("GetBit() should find the set bits")
+	}
+
+	var iterated []uint32
+	it := a.BitMap().Iterator()
+	for position, ok := it.Next(); ok; position, ok = it.Next() {
+		iterated = append(iterated, position)
+	}
+	if !slices.Equal(iterated, expected) {
+		t.Errorf("The copy has %d bits, want %d", len(iterated), len(expected))
+	}
+}
+
+func TestAtomic_Merge(t *testing.T) {
+	a, _ := NewAtomic(4 * PageSize * 8)
+	a.SetHashFunc(header.HashFunc{ID: header.HashFmix32})
+	a.SetBit(1)
+	other, _ := NewPaged(4 * PageSize * 8)
+	other.SetHashFunc(header.HashFunc{ID: header.HashFmix32})
+	other.SetBit(1, true)
+	other.SetBit(3*PageSize*8, true)
+
+	if err := a.Merge(other); err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+	if a.Count() != 2 || !a.GetBit(3*PageSize*8) {
+		t.Errorf("Expected 2 bits after merge, got %d", a.Count())
+	}
+	copied := a.BitMap()
+	if copied.AllocatedBytes() != 2*PageSize || copied.Count() != 2 || copied.HashFunc() != a.HashFunc() {
+		t.Errorf("Expected a copy of 2 pages and 2 bits, got %d bytes and %d bits", copied.AllocatedBytes(), copied.Count())
+	}
+
+	differentSize, _ := NewPaged(PageSize * 8)
+	differentSize.SetHashFunc(a.HashFunc())
+	if err := a.Merge(differentSize); err == nil {
+		t.Errorf("Expected an error when merging bitmaps of different sizes")
+	}
+	differentHash, _ := NewPaged(4 * PageSize * 8)
+	if err := a.Merge(differentHash); err == nil {
+		t.Errorf("Expected an error when merging bitmaps of different hash functions")
+	}
+}
+
+func TestAtomic_Clear(t *testing.T) {
+	a, _ := NewAtomic(4 * PageSize * 8)
+	a.SetBit(1)
+	a.SetBit(3 * PageSize * 8)
+	a.Clear()
+	if a.Count() != 0 || a.GetBit(1) {
+		t.Errorf("Expected no bits after Clear, got %d", a.Count())
+	}
+	if a.pages[3].Load() == nil || a.pages[1].Load() != nil {
+		t.Errorf("Clear should keep the allocated pages only")
+	}
+	a.SetBit(2)
+	if a.Count() != 1 || !a.GetBit(2) {
+		t.Errorf("Expected 1 bit after setting one again, got %d", a.Count())
+	}
+}
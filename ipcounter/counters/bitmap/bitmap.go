@@ -1,80 +1,469 @@
 package bitmap
 
 import (
+	"awesomeProject/ipcounter/counters/header"
+	"encoding/binary"
 	"fmt"
+	"math/bits"
+	"sync/atomic"
 )
 
-const MaxSize uint32 = 1<<32 - 1
+// MaxSize is the size of a bitmap of the whole address space, one bit per IP
+const MaxSize uint64 = 1 << 32
 
+const (
+	// PageSize is the number of bytes of a page, a paged bitmap allocates a page when one of its bits is set
+	PageSize     = pageWords * 8
+	pageWords    = 1 << pageWordBits
+	pageWordBits = 13
+	// pageBits is the number of position bits addressing a bit of a page
+	pageBits = pageWordBits + 6
+)
+
+// BitMap is a fixed size set of bits stored in 64-bit words, split into pages of PageSize bytes.
+// Bits in different words can be set concurrently as long as the page is allocated,
+// the count of set bits of each page is updated atomically.
 type BitMap struct {
-	data        []byte
-	cardinality uint32
-	count       uint64
+	pages       [][]uint64 // Pages of the words, nil until a bit is set in a paged bitmap
+	pageCounts  []uint32   // Number of set bits of each page
+	words       int        // Number of words
+	paged       bool       // Pages are allocated on first SetBit and released when they become empty
+	cardinality uint64
+	hashFunc    header.HashFunc
 }
 
-// New creates a new BitMap.
-func New(size uint32) (*BitMap, error) {
+// New creates a new BitMap with all pages allocated.
+func New(size uint64) (*BitMap, error) {
+	b, err := NewPaged(size)
+	if err != nil {
+		return nil, err
+	}
+	for i := range b.pages {
+		b.pages[i] = make([]uint64, b.pageLen(i))
+	}
+	b.paged = false
+	return b, nil
+}
+
+// NewPaged creates a new BitMap allocating its pages on first SetBit, the memory is proportional to the number
+// of regions of PageSize*8 bits with a set bit. Setting a bit of an allocated page stays O(1).
+// A page is allocated by the SetBit of any of its bits and released when its last bit is cleared,
+// so concurrent SetBits must not share a page.
+func NewPaged(size uint64) (*BitMap, error) {
 	if size < 1 || size > MaxSize {
 		return nil, fmt.Errorf("invalid size: %d, must be between 1 and %d", size, MaxSize)
 	}
-	arraySize := (size) >> 3
-	if size&7 > 0 {
-		arraySize++
-	}
+	words := int((size + 63) >> 6)
+	pages := (words + pageWords - 1) >> pageWordBits
 
 	return &BitMap{
-		data:        make([]byte, arraySize), // "size" >> 3 = number of bytes, "+ 7" = get extra byte
+		pages:       make([][]uint64, pages),
+		pageCounts:  make([]uint32, pages),
+		words:       words,
+		paged:       true,
 		cardinality: size,
 	}, nil
 }
 
+// pageLen returns the number of words of the page, only the last page may be shorter
+func (b *BitMap) pageLen(page int) int {
+	return min(pageWords, b.words-page<<pageWordBits)
+}
+
 func (b *BitMap) GetBit(position uint32) bool {
-	if position >= b.cardinality {
+	if uint64(position) >= b.cardinality {
 		return false
 	}
 
-	index, bit := position>>3, position&7
-	return (b.data[index]>>(bit))&0x01 != 0
+	page := b.pages[position>>pageBits]
+	return page != nil && page[position>>6&(pageWords-1)]&(1<<(position&63)) != 0
 }
 
 func (b *BitMap) SetBit(position uint32, value bool) bool {
-	if position >= b.cardinality {
+	if uint64(position) >= b.cardinality {
 		return false
 	}
 
-	index, bit := position>>3, position&7 // = position / 8 , position % 8
+	pageIndex := position >> pageBits
+	page := b.pages[pageIndex]
+	if page == nil {
+		if !value {
+			return true
+		}
+		page = make([]uint64, b.pageLen(int(pageIndex)))
+		b.pages[pageIndex] = page
+	}
 
-	oldValue := b.data[index]
-	if value {
-		b.data[index] |= 1 << bit
-	} else {
-		b.data[index] ^= 1 << bit
+	word, mask := &page[position>>6&(pageWords-1)], uint64(1)<<(position&63)
+	if value && *word&mask == 0 {
+		*word |= mask
+		atomic.AddUint32(&b.pageCounts[pageIndex], 1)
+	} else if !value && *word&mask != 0 {
+		*word &^= mask
+		if atomic.AddUint32(&b.pageCounts[pageIndex], ^uint32(0)) == 0 && b.paged {
+			b.pages[pageIndex] = nil
+		}
 	}
+	return true
+}
+
+// SetRange sets the bits from start to end excluded, it reports false if the range is past the size
+func (b *BitMap) SetRange(start, end uint64) bool {
+	return b.updateRange(start, end, true)
+}
 
-	if oldValue != b.data[index] {
-		if value {
-			b.count++
-		} else {
-			b.count--
+// ClearRange clears the bits from start to end excluded, it reports false if the range is past the size
+func (b *BitMap) ClearRange(start, end uint64) bool {
+	return b.updateRange(start, end, false)
+}
+
+// updateRange sets or clears the bits of the range a word at a time
+func (b *BitMap) updateRange(start, end uint64, value bool) bool {
+	if start > end || end > b.cardinality {
+		return false
+	}
+
+	for position := start; position < end; {
+		pageIndex := position >> pageBits
+		page := b.pages[pageIndex]
+		pageEnd := min((pageIndex+1)<<pageBits, end)
+		if page == nil {
+			if !value {
+				position = pageEnd
+				continue
+			}
+			page = make([]uint64, b.pageLen(int(pageIndex)))
+			b.pages[pageIndex] = page
+		}
+
+		count := int(b.pageCounts[pageIndex])
+		for position < pageEnd {
+			// The bits of the range in the word of the position
+			wordEnd := min(position|63+1, pageEnd)
+			mask := (^uint64(0) >> (64 - (wordEnd - position))) << (position & 63)
+			word := &page[position>>6&(pageWords-1)]
+			count -= bits.OnesCount64(*word)
+			if value {
+				*word |= mask
+			} else {
+				*word &^= mask
+			}
+			count += bits.OnesCount64(*word)
+			position = wordEnd
+		}
+		atomic.StoreUint32(&b.pageCounts[pageIndex], uint32(count))
+		if count == 0 && b.paged {
+			b.pages[pageIndex] = nil
 		}
 	}
 	return true
 }
 
-func (b *BitMap) Size() uint32 {
+func (b *BitMap) Size() uint64 {
 	return b.cardinality
 }
 
+// Count returns the number of set bits, the sum of the counts of the pages
 func (b *BitMap) Count() uint64 {
-	return b.count
+	var count uint64
+	for i := range b.pageCounts {
+		count += uint64(atomic.LoadUint32(&b.pageCounts[i]))
+	}
+	return count
+}
+
+// Rank returns the number of set bits at or before the position
+func (b *BitMap) Rank(position uint32) uint64 {
+	position = uint32(min(uint64(position), b.cardinality-1))
+	pageIndex := int(position >> pageBits)
+
+	var rank uint64
+	for i := range pageIndex {
+		rank += uint64(atomic.LoadUint32(&b.pageCounts[i]))
+	}
+	page := b.pages[pageIndex]
+	if page == nil {
+		return rank
+	}
+	wordIndex := int(position >> 6 & (pageWords - 1))
+	for _, word := range page[:wordIndex] {
+		rank += uint64(bits.OnesCount64(word))
+	}
+	// The bits up to the position included, 2<<63 wraps to 0 so that the mask of bit 63 is all ones
+	return rank + uint64(bits.OnesCount64(page[wordIndex]&(uint64(2)<<(position&63)-1)))
+}
+
+// CountRange returns the number of set bits from start to end excluded
+func (b *BitMap) CountRange(start, end uint64) uint64 {
+	if start >= end || start >= b.cardinality {
+		return 0
+	}
+	count := b.Rank(uint32(min(end, b.cardinality) - 1))
+	if start > 0 {
+		count -= b.Rank(uint32(start - 1))
+	}
+	return count
+}
+
+// Select returns the position of the set bit of rank k + 1, i.e. the k-th set bit counting from 0.
+// It reports false if less than k + 1 bits are set.
+func (b *BitMap) Select(k uint64) (uint32, bool) {
+	for pageIndex, page := range b.pages {
+		count := uint64(atomic.LoadUint32(&b.pageCounts[pageIndex]))
+		if k >= count {
+			k -= count
+			continue
+		}
+		for wordIndex, word := range page {
+			count := uint64(bits.OnesCount64(word))
+			if k >= count {
+				k -= count
+				continue
+			}
+			for ; k > 0; k-- {
+				word &= word - 1
+			}
+			return uint32(pageIndex)<<pageBits | uint32(wordIndex)<<6 | uint32(bits.TrailingZeros64(word)), true
+		}
+	}
+	return 0, false
+}
+
+// Paged reports whether the pages are allocated on first SetBit
+func (b *BitMap) Paged() bool {
+	return b.paged
+}
+
+// AllocatedBytes returns the size of the allocated pages
+func (b *BitMap) AllocatedBytes() uint64 {
+	var allocated uint64
+	for _, page := range b.pages {
+		allocated += 8 * uint64(len(page))
+	}
+	return allocated
+}
+
+// Merge adds all bits of the other bitmap (bitwise OR) and recounts the set bits.
+// Both bitmaps must have the same size.
+func (b *BitMap) Merge(other *BitMap) error {
+	return b.Or(other)
+}
+
+// And keeps the bits set in both bitmaps, both bitmaps must have the same size
+func (b *BitMap) And(other *BitMap) error {
+	return b.combine(other, and)
+}
+
+// Or sets the bits set in either bitmap, both bitmaps must have the same size
+func (b *BitMap) Or(other *BitMap) error {
+	return b.combine(other, or)
+}
+
+// AndNot clears the bits set in the other bitmap, both bitmaps must have the same size
+func (b *BitMap) AndNot(other *BitMap) error {
+	return b.combine(other, andNot)
+}
+
+// Xor keeps the bits set in exactly one of the bitmaps, both bitmaps must have the same size
+func (b *BitMap) Xor(other *BitMap) error {
+	return b.combine(other, xor)
+}
+
+// AndCount returns the number of bits set in both bitmaps without modifying them
+func (b *BitMap) AndCount(other *BitMap) (uint64, error) {
+	return b.combineCount(other, and)
+}
+
+// OrCount returns the number of bits set in either bitmap without modifying them
+func (b *BitMap) OrCount(other *BitMap) (uint64, error) {
+	return b.combineCount(other, or)
+}
+
+// AndNotCount returns the number of bits set in the bitmap but not in the other one without modifying them
+func (b *BitMap) AndNotCount(other *BitMap) (uint64, error) {
+	return b.combineCount(other, andNot)
+}
+
+// XorCount returns the number of bits set in exactly one of the bitmaps without modifying them
+func (b *BitMap) XorCount(other *BitMap) (uint64, error) {
+	return b.combineCount(other, xor)
+}
+
+func and(a, b uint64) uint64    { return a & b }
+func or(a, b uint64) uint64     { return a | b }
+func andNot(a, b uint64) uint64 { return a &^ b }
+func xor(a, b uint64) uint64    { return a ^ b }
+
+// combine replaces the words with op of both bitmaps and recounts the set bits.
+// A missing page reads as zeros, a paged bitmap releases the pages that become empty.
+func (b *BitMap) combine(other *BitMap, op func(a, b uint64) uint64) error {
+	if err := b.checkCompatible(other); err != nil {
+		return err
+	}
+
+	for i := range b.pages {
+		page, otherPage := b.pages[i], other.pages[i]
+		if page == nil && (otherPage == nil || op(0, ^uint64(0)) == 0) {
+			// The page stays empty
+			continue
+		}
+		if page == nil {
+			page = make([]uint64, b.pageLen(i))
+		}
+		if otherPage == nil {
+			otherPage = zeroPage[:len(page)]
+		}
+
+		count := 0
+		for j, word := range page {
+			page[j] = op(word, otherPage[j])
+			count += bits.OnesCount64(page[j])
+		}
+		atomic.StoreUint32(&b.pageCounts[i], uint32(count))
+		if count == 0 && b.paged {
+			page = nil
+		}
+		b.pages[i] = page
+	}
+	return nil
+}
+
+// combineCount counts the set bits of op of both bitmaps
+func (b *BitMap) combineCount(other *BitMap, op func(a, b uint64) uint64) (uint64, error) {
+	if err := b.checkCompatible(other); err != nil {
+		return 0, err
+	}
+
+	var count uint64
+	for i := range b.pages {
+		page, otherPage := b.pages[i], other.pages[i]
+		if page == nil && otherPage == nil {
+			continue
+		}
+		if page == nil {
+			page = zeroPage[:b.pageLen(i)]
+		}
+		if otherPage == nil {
+			otherPage = zeroPage[:len(page)]
+		}
+		for j, word := range page {
+			count += uint64(bits.OnesCount64(op(word, otherPage[j])))
+		}
+	}
+	return count, nil
+}
+
+// zeroPage stands for the missing pages of paged bitmaps, it is never written
+var zeroPage = make([]uint64, pageWords)
+
+func (b *BitMap) checkCompatible(other *BitMap) error {
+	if b.cardinality != other.cardinality {
+		return fmt.Errorf("can't combine bitmaps of different sizes: %d and %d", b.cardinality, other.cardinality)
+	}
+	if b.hashFunc != other.hashFunc {
+		return fmt.Errorf("can't combine bitmaps of different hash functions: %v and %v", b.hashFunc, other.hashFunc)
+	}
+	return nil
+}
+
+// recount counts the set bits of each page
+func (b *BitMap) recount() {
+	for i, page := range b.pages {
+		count := 0
+		for _, word := range page {
+			count += bits.OnesCount64(word)
+		}
+		atomic.StoreUint32(&b.pageCounts[i], uint32(count))
+	}
+}
+
+// SetHashFunc records the hash function applied to the positions, it is stored when the bitmap is serialized
+func (b *BitMap) SetHashFunc(hashFunc header.HashFunc) {
+	b.hashFunc = hashFunc
+}
+
+func (b *BitMap) HashFunc() header.HashFunc {
+	return b.hashFunc
+}
+
+// byteSize returns the number of bytes of the serialized bits
+func (b *BitMap) byteSize() int {
+	return int((b.cardinality + 7) >> 3)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, the payload is the 64-bit size followed by the raw bits
+// in little endian order. Missing pages are written as zeros.
+func (b *BitMap) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 0, header.Size+8+8*b.words)
+	data = header.Header{Type: header.TypeBitMap, HashFunc: b.hashFunc}.Append(data)
+	data = binary.LittleEndian.AppendUint64(data, b.cardinality)
+	for i, page := range b.pages {
+		if page == nil {
+			page = zeroPage[:b.pageLen(i)]
+		}
+		for _, word := range page {
+			data = binary.LittleEndian.AppendUint64(data, word)
+		}
+	}
+	// The last word may have more bytes than the size
+	return data[:header.Size+8+b.byteSize()], nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, the count is restored from the bits.
+// The bitmap is paged, only the pages with a set bit are allocated.
+func (b *BitMap) UnmarshalBinary(data []byte) error {
+	h, payload, err := header.Decode(data, header.TypeBitMap)
+	if err != nil {
+		return err
+	}
+	if len(payload) < 8 {
+		return header.ErrTruncated
+	}
+	decoded, err := NewPaged(binary.LittleEndian.Uint64(payload))
+	if err != nil {
+		return err
+	}
+	payload = payload[8:]
+	if len(payload) != decoded.byteSize() {
+		return fmt.Errorf("bitmap of size %d must have %d bytes, got %d", decoded.cardinality, decoded.byteSize(), len(payload))
+	}
+
+	// The words are decoded page by page, a page is only allocated for its first set bit
+	for i := range decoded.pages {
+		pageBytes := payload[i*PageSize : min((i+1)*PageSize, len(payload))]
+		var page []uint64
+		for j := range decoded.pageLen(i) {
+			// The last word may have less than 8 bytes
+			var word [8]byte
+			copy(word[:], pageBytes[j*8:])
+			value := binary.LittleEndian.Uint64(word[:])
+			if i<<pageWordBits+j == decoded.words-1 && decoded.cardinality&63 != 0 {
+				// Bits past the size can't be set
+				value &= 1<<(decoded.cardinality&63) - 1
+			}
+			if value == 0 {
+				continue
+			}
+			if page == nil {
+				page = make([]uint64, decoded.pageLen(i))
+			}
+			page[j] = value
+		}
+		decoded.pages[i] = page
+	}
+	decoded.recount()
+	decoded.hashFunc = h.HashFunc
+	*b = *decoded
+	return nil
 }
 
 // BitIterator is an iterator for a BitMap that allows iterating over the set bits.
-// It skips over zero bytes for efficiency.
+// It skips over missing pages and finds the set bits of a word with its trailing zeros.
 type BitIterator struct {
 	bitmap    *BitMap
-	byteIndex uint32
-	bitIndex  uint8
+	wordIndex int    // Next word to load
+	word      uint64 // Bits of the current word not returned yet
+	base      uint32 // Position of the first bit of the current word
 }
 
 func (b *BitMap) Iterator() *BitIterator {
@@ -84,32 +473,25 @@ func (b *BitMap) Iterator() *BitIterator {
 }
 
 func (it *BitIterator) HasNext() bool {
-	return it.byteIndex < uint32(len(it.bitmap.data))
+	return it.word != 0 || it.wordIndex < it.bitmap.words
 }
 
 func (it *BitIterator) Next() (uint32, bool) {
-	for it.byteIndex < uint32(len(it.bitmap.data)) {
-		if it.bitmap.data[it.byteIndex] == 0 {
-			it.byteIndex++
-			it.bitIndex = 0
-			continue
+	for it.word == 0 {
+		if it.wordIndex >= it.bitmap.words {
+			return 0, false
 		}
-
-		for it.bitIndex < 8 {
-			if (it.bitmap.data[it.byteIndex] & (1 << it.bitIndex)) != 0 {
-				position := (it.byteIndex << 3) + uint32(it.bitIndex)
-				it.bitIndex++
-				if it.bitIndex == 8 {
-					it.byteIndex++
-					it.bitIndex = 0
-				}
-				return position, true
-			}
-			it.bitIndex++
+		page := it.bitmap.pages[it.wordIndex>>pageWordBits]
+		if page == nil {
+			it.wordIndex = (it.wordIndex>>pageWordBits + 1) << pageWordBits
+			continue
 		}
-
-		it.byteIndex++
-		it.bitIndex = 0
+		it.word = page[it.wordIndex&(pageWords-1)]
+		it.base = uint32(it.wordIndex) << 6
+		it.wordIndex++
 	}
-	return 0, false
+
+	position := it.base + uint32(bits.TrailingZeros64(it.word))
+	it.word &= it.word - 1
+	return position, true
 }
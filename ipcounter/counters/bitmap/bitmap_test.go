@@ -1,20 +1,25 @@
 package bitmap
 
 import (
+	"awesomeProject/ipcounter/counters/header"
 	"fmt"
+	"math"
+	"runtime"
+	"slices"
 	"testing"
 )
 
 func TestNew(t *testing.T) {
 	tests := []struct {
 		name    string
-		size    uint32
+		size    uint64
 		wantErr bool
 	}{
 		{"Valid size", 1000, false},
 		{"Minimum size", 1, false},
 		{"Maximum size", MaxSize, false},
 		{"Size zero", 0, true},
+		{"Size past the address space", MaxSize + 1, true},
 	}
 
 	for _, tt := range tests {
@@ -84,7 +89,7 @@ func TestBitMap_GetBit(t *testing.T) {
 }
 
 func TestBitMap_Size(t *testing.T) {
-	sizes := []uint32{1, 8, 100, 1000, MaxSize}
+	sizes := []uint64{1, 8, 100, 1000, MaxSize}
 
 	for _, size := range sizes {
 		t.Run(fmt.Sprintf("Size %d", size), func(t *testing.T) {
@@ -169,3 +174,397 @@ func TestBitMap_Count(t *testing.T) {
 		t.Errorf("Final count should be 0, got %d", count)
 	}
 }
+
+func TestBitMap_Merge(t *testing.T) {
+	bm, _ := New(1000)
+	other, _ := New(1000)
+	for i := uint32(0); i < 600; i += 2 {
+		bm.SetBit(i, true)
+	}
+	for i := uint32(0); i < 1000; i += 3 {
+		other.SetBit(i, true)
+	}
+
+	if err := bm.Merge(other); err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+	var expected uint64
+	for i := uint32(0); i < 1000; i++ {
+		want := (i < 600 && i%2 == 0) || i%3 == 0
+		if bm.GetBit(i) != want {
+			t.Errorf("Bit %d should be %v after merge", i, want)
+		}
+		if want {
+			expected++
+		}
+	}
+	if count := bm.Count(); count != expected {
+		t.Errorf("Count should be %d, got %d", expected, count)
+	}
+
+	differentSize, _ := New(999)
+	if err := bm.Merge(differentSize); err == nil {
+		t.Errorf("Expected an error when merging bitmaps of different sizes")
+	}
+}
+
+func TestBitMap_SetOperations(t *testing.T) {
+	even := func(i uint32) bool { return i%2 == 0 }
+	third := func(i uint32) bool { return i%3 == 0 }
+	tests := []struct {
+		name    string
+		combine func(b, other *BitMap) error
+		count   func(b, other *BitMap) (uint64, error)
+		want    func(i uint32) bool
+	}{
+		{"and", (*BitMap).And, (*BitMap).AndCount, func(i uint32) bool { return even(i) && third(i) }},
+		{"or", (*BitMap).Or, (*BitMap).OrCount, func(i uint32) bool { return even(i) || third(i) }},
+		{"andnot", (*BitMap).AndNot, (*BitMap).AndNotCount, func(i uint32) bool { return even(i) && !third(i) }},
+		{"xor", (*BitMap).Xor, (*BitMap).XorCount, func(i uint32) bool { return even(i) != third(i) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 1003 bits cover whole words and a partial last byte
+			bm, _ := New(1003)
+			other, _ := New(1003)
+			var expected uint64
+			for i := uint32(0); i < 1003; i++ {
+				bm.SetBit(i, even(i))
+				other.SetBit(i, third(i))
+				if tt.want(i) {
+					expected++
+				}
+			}
+
+			count, err := tt.count(bm, other)
+			if err != nil {
+				t.Fatalf("count error = %v", err)
+			}
+			if count != expected {
+				t.Errorf("Count of %s should be %d, got %d", tt.name, expected, count)
+			}
+			if bm.Count() != 502 || other.Count() != 335 {
+				t.Errorf("Counting must not modify the bitmaps, got counts %d and %d", bm.Count(), other.Count())
+			}
+
+			if err := tt.combine(bm, other); err != nil {
+				t.Fatalf("combine error = %v", err)
+			}
+			for i := uint32(0); i < 1003; i++ {
+				if bm.GetBit(i) != tt.want(i) {
+					t.Errorf("Bit %d should be %v after %s", i, tt.want(i), tt.name)
+				}
+			}
+			if bm.Count() != expected {
+				t.Errorf("Count after %s should be %d, got %d", tt.name, expected, bm.Count())
+			}
+
+			differentSize, _ := New(1002)
+			if err := tt.combine(bm, differentSize); err == nil {
+				t.Errorf("Expected an error when combining bitmaps of different sizes")
+			}
+			hashed, _ := New(1003)
+			hashed.SetHashFunc(header.HashFunc{ID: header.HashFmix32})
+			if _, err := tt.count(bm, hashed); err == nil {
+				t.Errorf("Expected an error when combining bitmaps of different hash functions")
+			}
+		})
+	}
+}
+
+func TestBitMap_Paged(t *testing.T) {
+	// 8 full pages and a last page of one word
+	const size = 8*PageSize*8 + 5
+	positions := []uint32{0, 1, PageSize*8 - 1, 3*PageSize*8 + 100, size - 1}
+	bm, err := NewPaged(size)
+	if err != nil {
+		t.Fatalf("NewPaged() error = %v", err)
+	}
+	if !bm.Paged() || bm.AllocatedBytes() != 0 {
+		t.Fatalf("A new paged bitmap should allocate nothing, got %d bytes", bm.AllocatedBytes())
+	}
+	bm.SetBit(5*PageSize*8, false)
+	for _, position := range positions {
+		bm.SetBit(position, true)
+	}
+	if allocated := bm.AllocatedBytes(); allocated != 2*PageSize+8 {
+		t.Errorf("Expected 2 pages and the last word to be allocated, got %d bytes", allocated)
+	}
+	if bm.Count() != uint64(len(positions)) {
+		t.Errorf("Count should be %d, got %d", len(positions), bm.Count())
+	}
+	if bm.GetBit(5*PageSize*8) || bm.GetBit(2) {
+		t.Errorf("Bits of missing and allocated pages should be unset")
+	}
+
+	var iterated []uint32
+	it := bm.Iterator()
+	for position, ok := it.Next(); ok; position, ok = it.Next() {
+		iterated = append(iterated, position)
+	}
+	if !slices.Equal(iterated, positions) {
+		t.Errorf("Iterator returned %v, want %v", iterated, positions)
+	}
+
+	// A full bitmap combines with a paged one, pages that become empty are released
+	full, _ := New(size)
+	full.SetBit(1, true)
+	full.SetBit(6*PageSize*8, true)
+	if count, _ := bm.OrCount(full); count != uint64(len(positions))+1 {
+		t.Errorf("OrCount should be %d, got %d", len(positions)+1, count)
+	}
+	if err := bm.And(full); err != nil {
+		t.Fatalf("And() error = %v", err)
+	}
+	if bm.Count() != 1 || !bm.GetBit(1) || bm.AllocatedBytes() != PageSize {
+		t.Errorf("Expected only bit 1 in one page after And, got count %d and %d bytes", bm.Count(), bm.AllocatedBytes())
+	}
+	if err := bm.Or(full); err != nil {
+		t.Fatalf("Or() error = %v", err)
+	}
+	if bm.Count() != 2 || !bm.GetBit(6*PageSize*8) || bm.AllocatedBytes() != 2*PageSize {
+		t.Errorf("Expected 2 bits in 2 pages after Or, got count %d and %d bytes", bm.Count(), bm.AllocatedBytes())
+	}
+
+	// Saved bitmaps are restored paged
+	data, _ := full.MarshalBinary()
+	decoded := &BitMap{}
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary() error = %v", err)
+	}
+	if !decoded.Paged() || decoded.AllocatedBytes() != 2*PageSize || decoded.Count() != 2 {
+		t.Errorf("Expected a paged bitmap of 2 pages and 2 bits, got %d bytes and %d bits", decoded.AllocatedBytes(), decoded.Count())
+	}
+	if saved, _ := decoded.MarshalBinary(); !slices.Equal(saved, data) {
+		t.Errorf("A restored paged bitmap should be saved as the full one")
+	}
+}
+
+func TestBitMap_PagedClearBit(t *testing.T) {
+	bm, _ := NewPaged(4 * PageSize * 8)
+	for _, position := range []uint32{1, 2, PageSize * 8} {
+		bm.SetBit(position, true)
+	}
+	bm.SetBit(1, false)
+	if bm.AllocatedBytes() != 2*PageSize {
+		t.Errorf("A page with a set bit should stay allocated, got %d bytes", bm.AllocatedBytes())
+	}
+	bm.SetBit(2, false)
+	if bm.AllocatedBytes() != PageSize || bm.Count() != 1 || bm.GetBit(2) {
+		t.Errorf("Expected the empty page to be released, got %d bytes and %d bits", bm.AllocatedBytes(), bm.Count())
+	}
+	bm.SetBit(3, true)
+	if bm.AllocatedBytes() != 2*PageSize || bm.Count() != 2 || !bm.GetBit(3) {
+		t.Errorf("Expected the page to be allocated again, got %d bytes and %d bits", bm.AllocatedBytes(), bm.Count())
+	}
+
+	// A bitmap that isn't paged keeps its pages
+	full, _ := New(4 * PageSize * 8)
+	full.SetBit(1, true)
+	full.SetBit(1, false)
+	if full.AllocatedBytes() != 4*PageSize {
+		t.Errorf("Expected the 4 pages to stay allocated, got %d bytes", full.AllocatedBytes())
+	}
+}
+
+func TestBitMap_UnmarshalBinaryPaged(t *testing.T) {
+	const size = 1 << 28
+	bm, _ := NewPaged(size)
+	bm.SetBit(3, true)
+	bm.SetBit(size-1, true)
+	data, _ := bm.MarshalBinary()
+
+	// Only the 2 pages with a set bit are allocated, not the 32 MB of words
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	decoded := &BitMap{}
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary() error = %v", err)
+	}
+	runtime.ReadMemStats(&after)
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 1<<20 {
+		t.Errorf("Expected less than 1 MB allocated to decode 2 pages, got %d bytes", allocated)
+	}
+	if decoded.AllocatedBytes() != 2*PageSize || decoded.Count() != 2 || !decoded.GetBit(size-1) {
+		t.Errorf("Expected 2 bits in 2 pages, got %d bits in %d bytes", decoded.Count(), decoded.AllocatedBytes())
+	}
+}
+
+func TestBitMap_Ranges(t *testing.T) {
+	const size = 3*PageSize*8 + 77
+	ranges := []struct {
+		set        bool
+		start, end uint64
+	}{
+		{true, 5, 6},
+		{true, 60, 200},
+		{true, PageSize*8 - 10, 2*PageSize*8 + 64},
+		{false, 100, 130},
+		{false, PageSize*8 - 3, PageSize*8 + 1},
+		{true, size - 70, size},
+		// A page cleared completely is released
+		{false, PageSize * 8, 2 * PageSize * 8},
+		{true, 0, 0},
+	}
+
+	for _, paged := range []bool{false, true} {
+		t.Run(fmt.Sprintf("Paged %v", paged), func(t *testing.T) {
+			bm, _ := New(size)
+			if paged {
+				bm, _ = NewPaged(size)
+			}
+			expected := make([]bool, size)
+			for _, r := range ranges {
+				update := bm.ClearRange
+				if r.set {
+					update = bm.SetRange
+				}
+				if !update(r.start, r.end) {
+					t.Fatalf("Range %d-%d should be valid", r.start, r.end)
+				}
+				for i := r.start; i < r.end; i++ {
+					expected[i] = r.set
+				}
+			}
+			if bm.SetRange(10, size+1) || bm.ClearRange(10, 9) {
+				t.Errorf("Ranges past the size or reversed should be invalid")
+			}
+			if paged && bm.AllocatedBytes() != 2*PageSize+8*2 {
+				t.Errorf("Expected the first page and the last 2 pages to be allocated, got %d bytes", bm.AllocatedBytes())
+			}
+
+			var rank uint64
+			var selected []uint32
+			for i := uint32(0); i < size; i++ {
+				if bm.GetBit(i) != expected[i] {
+					t.Fatalf("Bit %d should be %v", i, expected[i])
+				}
+				if expected[i] {
+					rank++
+					selected = append(selected, i)
+				}
+				// Rank scans the words of the page, check it at the edges of the ranges and at a sample of bits
+				edge := i == 0 || expected[i] != expected[i-1] || i == size-1
+				if edge || i%97 == 0 {
+					if got := bm.Rank(i); got != rank {
+						t.Fatalf("Rank(%d) = %d, want %d", i, got, rank)
+					}
+				}
+			}
+			if bm.Count() != rank || bm.Rank(math.MaxUint32) != rank {
+				t.Errorf("Count() = %d and Rank(MaxUint32) = %d, want %d", bm.Count(), bm.Rank(math.MaxUint32), rank)
+			}
+			for k, want := range selected {
+				if got, ok := bm.Select(uint64(k)); !ok || got != want {
+					t.Fatalf("Select(%d) = %d, %v, want %d", k, got, ok, want)
+				}
+			}
+			if _, ok := bm.Select(rank); ok {
+				t.Errorf("Select(%d) should fail with %d set bits", rank, rank)
+			}
+			var inRange uint64
+			for _, position := range selected {
+				if position >= PageSize*8-20 && position < size-5 {
+					inRange++
+				}
+			}
+			if got := bm.CountRange(PageSize*8-20, size-5); got != inRange {
+				t.Errorf("CountRange() = %d, want %d", got, inRange)
+			}
+			if got := bm.CountRange(50, 50); got != 0 {
+				t.Errorf("CountRange() of an empty range = %d, want 0", got)
+			}
+
+			var iterated []uint32
+			it := bm.Iterator()
+			for position, ok := it.Next(); ok; position, ok = it.Next() {
+				iterated = append(iterated, position)
+			}
+			if !slices.Equal(iterated, selected) {
+				t.Errorf("Iterator returned %d positions, want %d", len(iterated), len(selected))
+			}
+		})
+	}
+}
+
+func TestBitMap_LastBit(t *testing.T) {
+	// The bitmap of the address space has a bit for 255.255.255.255
+	const last = math.MaxUint32
+	bm, _ := NewPaged(MaxSize)
+	if !bm.SetBit(last, true) || !bm.GetBit(last) || bm.Count() != 1 {
+		t.Fatalf("The last bit of the address space should be set")
+	}
+	if rank := bm.Rank(last); rank != 1 {
+		t.Errorf("Rank(%d) = %d, want 1", uint32(last), rank)
+	}
+	if position, ok := bm.Select(0); !ok || position != last {
+		t.Errorf("Select(0) = %d, %v, want %d", position, ok, uint32(last))
+	}
+	it := bm.Iterator()
+	if position, ok := it.Next(); !ok || position != last {
+		t.Errorf("Iterator returned %d, %v, want %d", position, ok, uint32(last))
+	}
+
+	if !bm.SetRange(MaxSize-100, MaxSize) || bm.Count() != 100 {
+		t.Errorf("Expected the 100 last bits to be set, got %d", bm.Count())
+	}
+	if count := bm.CountRange(MaxSize-10, MaxSize); count != 10 {
+		t.Errorf("CountRange() = %d, want 10", count)
+	}
+	if !bm.ClearRange(MaxSize-1, MaxSize) || bm.GetBit(last) || bm.Count() != 99 {
+		t.Errorf("Expected the last bit to be cleared, got %d bits", bm.Count())
+	}
+	if bm.SetRange(MaxSize-1, MaxSize+1) {
+		t.Errorf("Ranges past the address space should be invalid")
+	}
+}
+
+func TestBitMap_MarshalBinary(t *testing.T) {
+	bm, _ := New(1001)
+	bm.SetHashFunc(header.HashFunc{ID: header.HashFNV1a, Seed: 7})
+	for i := uint32(0); i < 1001; i += 7 {
+		bm.SetBit(i, true)
+	}
+
+	data, err := bm.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() error = %v", err)
+	}
+	decoded := &BitMap{}
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary() error = %v", err)
+	}
+	if decoded.Size() != bm.Size() || decoded.Count() != bm.Count() || decoded.HashFunc() != (header.HashFunc{ID: header.HashFNV1a, Seed: 7}) {
+		t.Errorf("Expected size %d, count %d and hash fnv1a, got %d, %d and %v",
+			bm.Size(), bm.Count(), decoded.Size(), decoded.Count(), decoded.HashFunc())
+	}
+	for i := uint32(0); i < 1001; i++ {
+		if decoded.GetBit(i) != bm.GetBit(i) {
+			t.Errorf("Bit %d differs after unmarshal", i)
+		}
+	}
+
+	if err := decoded.UnmarshalBinary(data[:len(data)-1]); err == nil {
+		t.Errorf("Expected an error for a truncated bitmap")
+	}
+}
+
+func TestBitMap_Iterator(t *testing.T) {
+	bm, _ := New(1 << 20)
+	expected := []uint32{0, 7, 8, 63, 64, 1000, 65535, 1<<20 - 1}
+	for _, position := range expected {
+		bm.SetBit(position, true)
+	}
+
+	var positions []uint32
+	it := bm.Iterator()
+	for position, ok := it.Next(); ok; position, ok = it.Next() {
+		positions = append(positions, position)
+	}
+	if !slices.Equal(positions, expected) {
+		t.Errorf("Expected positions %v, got %v", expected, positions)
+	}
+	if it.HasNext() {
+		t.Errorf("Expected the iterator to be exhausted")
+	}
+}
@@ -0,0 +1,155 @@
+// Package countmin implements the Count-Min sketch with conservative update
+// (Cormode, Muthukrishnan, "An Improved Data Stream Summary: The Count-Min Sketch and its Applications").
+//
+// The sketch has d rows of w counters, a key increments one counter per row and its estimate is the minimum of them.
+// With w = ceil(e/epsilon) and d = ceil(ln(1/delta)) the estimate overestimates the true count by at most
+// epsilon*N with probability 1-delta. Conservative update only increments the counters that are at the minimum,
+// which lowers the overestimation without breaking the bound.
+//
+// The sketch can't list its keys, Sketch tracks the k keys with the highest estimates seen so far.
+package countmin
+
+import (
+	"awesomeProject/ipcounter/counters/internal/topk"
+	"awesomeProject/ipcounter/utils/murmur3"
+	"fmt"
+	"math"
+)
+
+// Entry is a tracked key, its true count is between Count-Error and Count with probability 1-delta
+type Entry = topk.Entry
+
+// Sketch is a Count-Min sketch with conservative update and a heap of the heaviest keys
+type Sketch struct {
+	width    uint32
+	depth    int
+	counters []uint64 // depth rows of width counters
+	total    uint64
+	epsilon  float64
+	capacity int
+	heavy    *topk.Heap
+	indexes  []uint32 // Counter of the current key in each row, reused by Add
+}
+
+// New creates a sketch overestimating by at most epsilon*N with probability 1-delta, tracking the k heaviest keys
+func New(epsilon, delta float64, k int) (*Sketch, error) {
+	if epsilon <= 0 || epsilon >= 1 {
+		return nil, fmt.Errorf("invalid epsilon: %v, must be between 0 and 1", epsilon)
+	}
+	if delta <= 0 || delta >= 1 {
+		return nil, fmt.Errorf("invalid delta: %v, must be between 0 and 1", delta)
+	}
+	if k <= 0 {
+		return nil, fmt.Errorf("invalid number of tracked keys: %d, must be positive", k)
+	}
+
+	width := uint32(math.Ceil(math.E / epsilon))
+	depth := int(math.Ceil(math.Log(1 / delta)))
+	return &Sketch{
+		width:    width,
+		depth:    depth,
+		counters: make([]uint64, int(width)*depth),
+		epsilon:  epsilon,
+		capacity: k,
+		heavy:    topk.New(k),
+		indexes:  make([]uint32, depth),
+	}, nil
+}
+
+// Add counts one occurrence of the key
+func (s *Sketch) Add(key uint32) {
+	s.total++
+	s.locate(key)
+
+	// Conservative update: raise the counters of the key to its new estimate at most
+	estimate := s.estimate() + 1
+	for row, index := range s.indexes {
+		counter := &s.counters[row*int(s.width)+int(index)]
+		*counter = max(*counter, estimate)
+	}
+	s.track(key, estimate)
+}
+
+// Estimate returns the estimated count of the key, never lower than the true count
+func (s *Sketch) Estimate(key uint32) uint64 {
+	s.locate(key)
+	return s.estimate()
+}
+
+// Top returns the n tracked keys with the highest estimates, by decreasing estimate
+func (s *Sketch) Top(n int) []Entry {
+	sorted := s.heavy.Sorted()
+	sorted = sorted[:min(n, len(sorted))]
+	maxError := s.MaxError()
+	for i := range sorted {
+		sorted[i].Error = min(maxError, sorted[i].Count)
+	}
+	return sorted
+}
+
+// Total returns the number of keys added
+func (s *Sketch) Total() uint64 {
+	return s.total
+}
+
+// MaxError returns the maximum overestimation of a count with probability 1-delta, epsilon*N
+func (s *Sketch) MaxError() uint64 {
+	return uint64(math.Ceil(s.epsilon * float64(s.total)))
+}
+
+// Merge adds the counters of the other sketch, both sketches must have the same dimensions.
+// The tracked keys of both sketches are tracked with their estimates in the merged sketch.
+func (s *Sketch) Merge(other *Sketch) error {
+	if s.width != other.width || s.depth != other.depth || s.capacity != other.capacity {
+		return fmt.Errorf("can't merge sketches of different dimensions: %dx%d and %dx%d",
+			s.depth, s.width, other.depth, other.width)
+	}
+	for i, counter := range other.counters {
+		s.counters[i] += counter
+	}
+	s.total += other.total
+
+	keys := make([]uint32, 0, s.heavy.Len()+other.heavy.Len())
+	for _, entries := range [][]Entry{s.heavy.Entries(), other.heavy.Entries()} {
+		for _, entry := range entries {
+			keys = append(keys, entry.Key)
+		}
+	}
+	s.heavy = topk.New(s.capacity)
+	for _, key := range keys {
+		s.track(key, s.Estimate(key))
+	}
+	return nil
+}
+
+// locate computes the counter of the key in each row from two halves of a 64-bit hash (Kirsch, Mitzenmacher)
+func (s *Sketch) locate(key uint32) {
+	hash := murmur3.Fmix64(uint64(key))
+	h1, h2 := uint32(hash), uint32(hash>>32)
+	for row := range s.indexes {
+		s.indexes[row] = (h1 + uint32(row)*h2) % s.width
+	}
+}
+
+// estimate returns the minimum of the counters found by locate
+func (s *Sketch) estimate() uint64 {
+	estimate := uint64(math.MaxUint64)
+	for row, index := range s.indexes {
+		estimate = min(estimate, s.counters[row*int(s.width)+int(index)])
+	}
+	return estimate
+}
+
+// track keeps the key if its estimate is among the k highest
+func (s *Sketch) track(key uint32, estimate uint64) {
+	switch {
+	case s.heavy.Len() < s.capacity:
+		s.heavy.Set(Entry{Key: key, Count: estimate})
+	case estimate > s.heavy.Min().Count:
+		if _, ok := s.heavy.Get(key); ok {
+			s.heavy.Set(Entry{Key: key, Count: estimate})
+		} else {
+			s.heavy.ReplaceMin(Entry{Key: key, Count: estimate})
+		}
+	}
+}
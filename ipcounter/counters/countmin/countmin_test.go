@@ -0,0 +1,91 @@
+package countmin
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+func TestEstimateBounds(t *testing.T) {
+	const epsilon = 0.001
+	s, err := New(epsilon, 0.01, 10)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	rng := rand.New(rand.NewPCG(1, 2))
+	zipf := rand.NewZipf(rng, 1.2, 1, 100000)
+	counts := make(map[uint32]uint64)
+	for i := 0; i < 200000; i++ {
+		key := uint32(zipf.Uint64())
+		s.Add(key)
+		counts[key]++
+	}
+
+	exceeded := 0
+	for key, count := range counts {
+		estimate := s.Estimate(key)
+		if estimate < count {
+			t.Fatalf("Key %d: estimate %d below the true count %d", key, estimate, count)
+		}
+		if estimate-count > s.MaxError() {
+			exceeded++
+		}
+	}
+	// The bound holds with probability 1-delta for each key
+	if exceeded > len(counts)/100 {
+		t.Errorf("%d of %d estimates exceed the error bound %d", exceeded, len(counts), s.MaxError())
+	}
+
+	top := s.Top(3)
+	for i, key := range []uint32{0, 1, 2} {
+		if top[i].Key != key || top[i].Count < counts[key] || top[i].Count-top[i].Error > counts[key] {
+			t.Errorf("Expected key %d with count %d at %d, got %+v", key, counts[key], i, top[i])
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	s, _ := New(0.01, 0.01, 5)
+	other, _ := New(0.01, 0.01, 5)
+	for i := 0; i < 1000; i++ {
+		s.Add(uint32(i % 10))
+		other.Add(uint32(i%10) + 5)
+	}
+	if err := s.Merge(other); err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+
+	if s.Total() != 2000 {
+		t.Errorf("Expected a total of 2000, got %d", s.Total())
+	}
+	// Keys 5 to 9 are in both streams
+	for _, entry := range s.Top(5) {
+		if entry.Key < 5 || entry.Key > 9 || entry.Count < 200 {
+			t.Errorf("Expected keys 5 to 9 with 200 occurrences, got %+v", entry)
+		}
+	}
+
+	otherSize, _ := New(0.001, 0.01, 5)
+	if err := s.Merge(otherSize); err == nil {
+		t.Errorf("Expected an error when merging sketches of different dimensions")
+	}
+}
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		name    string
+		epsilon float64
+		delta   float64
+		k       int
+	}{
+		{"Zero epsilon", 0, 0.01, 10},
+		{"Delta of 1", 0.01, 1, 10},
+		{"No tracked keys", 0.01, 0.01, 0},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := New(tc.epsilon, tc.delta, tc.k); err == nil {
+				t.Errorf("Expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,242 @@
+// Package frequency implements an exact frequency table of uint32 keys, e.g. how often each IP was seen.
+//
+// The table uses open addressing with linear probing. Most IPs are seen a few times, so a count takes
+// a single byte, counts that outgrow it move to an overflow map.
+package frequency
+
+import (
+	"awesomeProject/ipcounter/utils/murmur3"
+	"container/heap"
+	"fmt"
+	"math"
+	"slices"
+)
+
+const (
+	// minCapacity The smallest number of slots of the table
+	minCapacity = 16
+	// overflowed A count in the byte array that means the count is in the overflow map
+	overflowed = math.MaxUint8
+)
+
+// Entry is a key and the number of times it was added
+type Entry struct {
+	Key   uint32
+	Count uint64
+}
+
+// Table counts the occurrences of each key
+type Table struct {
+	keys     []uint32
+	counts   []uint8 // 0 marks an empty slot
+	overflow map[uint32]uint64
+	length   int // Number of distinct keys
+	total    uint64
+}
+
+// New creates a table sized for the expected number of distinct keys, it grows when it is 3/4 full
+func New(capacity int) *Table {
+	size := minCapacity
+	for size*3/4 < capacity {
+		size *= 2
+	}
+	return &Table{
+		keys:     make([]uint32, size),
+		counts:   make([]uint8, size),
+		overflow: make(map[uint32]uint64),
+	}
+}
+
+// Add counts one occurrence of the key
+func (t *Table) Add(key uint32) {
+	t.AddCount(key, 1)
+}
+
+// AddCount counts n occurrences of the key
+func (t *Table) AddCount(key uint32, n uint64) {
+	if n == 0 {
+		return
+	}
+	t.total += n
+	slot, found := t.find(key)
+	if !found {
+		if (t.length+1)*4 > len(t.keys)*3 {
+			t.grow()
+			slot, _ = t.find(key)
+		}
+		t.keys[slot] = key
+		t.length++
+	}
+
+	count := t.counts[slot]
+	switch {
+	case count == overflowed:
+		t.overflow[key] += n
+	case uint64(count)+n < overflowed:
+		t.counts[slot] = count + uint8(n)
+	default:
+		t.counts[slot] = overflowed
+		t.overflow[key] = uint64(count) + n
+	}
+}
+
+// Get returns the number of occurrences of the key
+func (t *Table) Get(key uint32) uint64 {
+	slot, found := t.find(key)
+	if !found {
+		return 0
+	}
+	return t.count(slot)
+}
+
+// Len returns the number of distinct keys
+func (t *Table) Len() int {
+	return t.length
+}
+
+// Total returns the number of occurrences of all keys
+func (t *Table) Total() uint64 {
+	return t.total
+}
+
+// ForEach calls fn for each key and its count, in no particular order
+func (t *Table) ForEach(fn func(key uint32, count uint64)) {
+	for slot, count := range t.counts {
+		if count != 0 {
+			fn(t.keys[slot], t.count(slot))
+		}
+	}
+}
+
+// Merge adds the counts of the other table
+func (t *Table) Merge(other *Table) {
+	other.ForEach(t.AddCount)
+}
+
+// Top returns the n most frequent keys, by decreasing count and then by increasing key
+func (t *Table) Top(n int) []Entry {
+	if n <= 0 {
+		return nil
+	}
+	// A min-heap of the n most frequent keys seen so far
+	top := make(entryHeap, 0, min(n, t.length))
+	t.ForEach(func(key uint32, count uint64) {
+		entry := Entry{Key: key, Count: count}
+		if len(top) < n {
+			heap.Push(&top, entry)
+		} else if less(top[0], entry) {
+			top[0] = entry
+			heap.Fix(&top, 0)
+		}
+	})
+
+	slices.SortFunc(top, func(a, b Entry) int {
+		if less(a, b) {
+			return 1
+		}
+		if less(b, a) {
+			return -1
+		}
+		return 0
+	})
+	return top
+}
+
+// find returns the slot of the key, or the empty slot where it belongs
+func (t *Table) find(key uint32) (int, bool) {
+	mask := uint32(len(t.keys) - 1)
+	slot := murmur3.Fmix32(key) & mask
+	for {
+		if t.counts[slot] == 0 {
+			return int(slot), false
+		}
+		if t.keys[slot] == key {
+			return int(slot), true
+		}
+		slot = (slot + 1) & mask
+	}
+}
+
+func (t *Table) count(slot int) uint64 {
+	if t.counts[slot] == overflowed {
+		return t.overflow[t.keys[slot]]
+	}
+	return uint64(t.counts[slot])
+}
+
+// grow doubles the number of slots and reinserts the keys, the overflow map doesn't depend on the slots
+func (t *Table) grow() {
+	keys, counts := t.keys, t.counts
+	t.keys = make([]uint32, 2*len(keys))
+	t.counts = make([]uint8, 2*len(counts))
+	for slot, count := range counts {
+		if count != 0 {
+			newSlot, _ := t.find(keys[slot])
+			t.keys[newSlot] = keys[slot]
+			t.counts[newSlot] = count
+		}
+	}
+}
+
+// HistogramBucket is the number of keys seen between Min and Max times, Max is 0 for the last open bucket
+type HistogramBucket struct {
+	Min  uint64
+	Max  uint64
+	Keys uint64
+}
+
+func (b HistogramBucket) String() string {
+	switch {
+	case b.Max == 0:
+		return fmt.Sprintf("%d+", b.Min)
+	case b.Min == b.Max:
+		return fmt.Sprintf("%d", b.Min)
+	}
+	return fmt.Sprintf("%d-%d", b.Min, b.Max)
+}
+
+// histogramBounds The upper bounds of the histogram buckets, the last bucket is open
+var histogramBounds = []uint64{1, 2, 10, 100, 1000, 10000}
+
+// Histogram returns how many keys were seen once, twice, 3-10 times, 11-100 times and so on
+func (t *Table) Histogram() []HistogramBucket {
+	buckets := make([]HistogramBucket, len(histogramBounds)+1)
+	lower := uint64(1)
+	for i, bound := range histogramBounds {
+		buckets[i] = HistogramBucket{Min: lower, Max: bound}
+		lower = bound + 1
+	}
+	buckets[len(histogramBounds)] = HistogramBucket{Min: lower}
+
+	t.ForEach(func(_ uint32, count uint64) {
+		i, _ := slices.BinarySearch(histogramBounds, count)
+		buckets[i].Keys++
+	})
+	return buckets
+}
+
+// less orders entries by count, ties by decreasing key so that smaller keys rank higher
+func less(a, b Entry) bool {
+	if a.Count != b.Count {
+		return a.Count < b.Count
+	}
+	return a.Key > b.Key
+}
+
+// entryHeap is a min-heap of entries, it implements heap.Interface
+type entryHeap []Entry
+
+func (h entryHeap) Len() int           { return len(h) }
+func (h entryHeap) Less(i, j int) bool { return less(h[i], h[j]) }
+func (h entryHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *entryHeap) Push(x any) {
+	*h = append(*h, x.(Entry))
+}
+
+func (h *entryHeap) Pop() any {
+	old := *h
+	entry := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return entry
+}
@@ -0,0 +1,115 @@
+package frequency
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestAddAndGet(t *testing.T) {
+	table := New(0)
+	expected := make(map[uint32]uint64)
+	rng := rand.New(rand.NewPCG(1, 2))
+	for i := 0; i < 100000; i++ {
+		// Few keys with large counts and many keys seen once, including 0
+		key := rng.Uint32N(1000)
+		if i%2 == 0 {
+			key = rng.Uint32()
+		}
+		table.Add(key)
+		expected[key]++
+	}
+
+	if table.Len() != len(expected) {
+		t.Errorf("Expected %d distinct keys, got %d", len(expected), table.Len())
+	}
+	if table.Total() != 100000 {
+		t.Errorf("Expected a total of 100000, got %d", table.Total())
+	}
+	for key, count := range expected {
+		if got := table.Get(key); got != count {
+			t.Fatalf("Expected count %d for key %d, got %d", count, key, got)
+		}
+	}
+	if got := table.Get(1 << 31); got != expected[1<<31] {
+		t.Errorf("Expected count %d for an absent key, got %d", expected[1<<31], got)
+	}
+}
+
+func TestAddCountOverflow(t *testing.T) {
+	testCases := []struct {
+		name   string
+		counts []uint64
+	}{
+		{"Below a byte", []uint64{100, 154}},
+		{"Exactly the overflow marker", []uint64{255}},
+		{"Crossing the byte", []uint64{200, 100}},
+		{"Already overflowed", []uint64{300, 1, 1 << 40}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			table := New(0)
+			var expected uint64
+			for _, n := range tc.counts {
+				table.AddCount(7, n)
+				expected += n
+			}
+			if got := table.Get(7); got != expected {
+				t.Errorf("Expected count %d, got %d", expected, got)
+			}
+		})
+	}
+}
+
+func TestTop(t *testing.T) {
+	table := New(0)
+	for key := uint32(1); key <= 100; key++ {
+		table.AddCount(key, uint64(key%10)*100)
+	}
+
+	top := table.Top(3)
+	// Keys 9, 19, ... have the highest count 900, the smallest keys come first
+	expected := []Entry{{9, 900}, {19, 900}, {29, 900}}
+	if !slices.Equal(top, expected) {
+		t.Errorf("Expected %v, got %v", expected, top)
+	}
+	if len(table.Top(1000)) != 90 {
+		t.Errorf("Expected all 90 keys, got %d", len(table.Top(1000)))
+	}
+	if table.Top(0) != nil {
+		t.Errorf("Expected no entries for n = 0")
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	table := New(0)
+	for key, n := range []uint64{1, 1, 2, 3, 10, 11, 5000, 20000} {
+		table.AddCount(uint32(key), n)
+	}
+
+	histogram := table.Histogram()
+	expected := map[string]uint64{"1": 2, "2": 1, "3-10": 2, "11-100": 1, "101-1000": 0, "1001-10000": 1, "10001+": 1}
+	if len(histogram) != len(expected) {
+		t.Fatalf("Expected %d buckets, got %d", len(expected), len(histogram))
+	}
+	for _, bucket := range histogram {
+		if bucket.Keys != expected[bucket.String()] {
+			t.Errorf("Expected %d keys in bucket %v, got %d", expected[bucket.String()], bucket, bucket.Keys)
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	table, other := New(0), New(0)
+	for key := uint32(0); key < 1000; key++ {
+		table.AddCount(key, 2)
+		other.AddCount(key+500, 300)
+	}
+
+	table.Merge(other)
+	if table.Len() != 1500 || table.Get(100) != 2 || table.Get(700) != 302 || table.Get(1200) != 300 {
+		t.Errorf("Unexpected counts after merge: len=%d %d %d %d",
+			table.Len(), table.Get(100), table.Get(700), table.Get(1200))
+	}
+}
@@ -0,0 +1,153 @@
+// Package header implements the versioned header of serialized counters.
+//
+// The header is 13 bytes long:
+//
+//	magic "IPCS" | version | counter type | precision | hash function id | flags | hash seed (4 bytes)
+//
+// It is followed by the payload of the counter type.
+package header
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+const (
+	// Magic starts every serialized counter
+	Magic = "IPCS"
+	// Version is the version of the format, Peek rejects the others
+	Version uint8 = 1
+	// Size is the length of the header in bytes
+	Size = len(Magic) + 9
+
+	flagSparse = 1 << 0
+)
+
+var (
+	ErrInvalidMagic = errors.New("not a serialized counter")
+	ErrTruncated    = errors.New("serialized counter is truncated")
+)
+
+// Type identifies the counter
+type Type uint8
+
+const (
+	TypeBitMap Type = iota + 1
+	TypeHyperLogLog
+	TypeHyperLogLogPlus
+	TypeHyperLogLogPlusBitMap
+	TypeRoaring
+)
+
+func (t Type) String() string {
+	switch t {
+	case TypeBitMap:
+		return "bitmap"
+	case TypeHyperLogLog:
+		return "hyperloglog"
+	case TypeHyperLogLogPlus:
+		return "hyperloglogplus"
+	case TypeHyperLogLogPlusBitMap:
+		return "hyperloglogplusbitmap"
+	case TypeRoaring:
+		return "roaring"
+	}
+	return fmt.Sprintf("unknown(%d)", uint8(t))
+}
+
+// HashID identifies the hash function applied to the IPs before they were added to the counter.
+// Counters are only comparable if they were filled with the same hash function.
+type HashID uint8
+
+const (
+	// HashNone means the IPs were added as is
+	HashNone HashID = iota
+	HashFNV1a
+	// HashMurmur3 is the seeded MurmurHash3 of the address bytes
+	HashMurmur3
+	// HashFmix32 is the finalizer of the 32-bit MurmurHash3, a bijection of the IPs
+	HashFmix32
+)
+
+func (id HashID) String() string {
+	switch id {
+	case HashNone:
+		return "none"
+	case HashFNV1a:
+		return "fnv1a"
+	case HashMurmur3:
+		return "murmur3"
+	case HashFmix32:
+		return "fmix32"
+	}
+	return fmt.Sprintf("unknown(%d)", uint8(id))
+}
+
+// HashFunc identifies the hash function and its seed, counters can only be merged if they match
+type HashFunc struct {
+	ID   HashID
+	Seed uint32 // Seed of seeded hash functions, 0 otherwise
+}
+
+func (f HashFunc) String() string {
+	if f.Seed != 0 {
+		return fmt.Sprintf("%v(seed %d)", f.ID, f.Seed)
+	}
+	return f.ID.String()
+}
+
+// Header describes a serialized counter
+type Header struct {
+	Type      Type
+	Precision uint8 // Precision of a sketch, 0 for bitmaps
+	HashFunc  HashFunc
+	Sparse    bool // The payload holds the sparse representation of a sketch
+}
+
+// Append appends the encoded header to b
+func (h Header) Append(b []byte) []byte {
+	var flags uint8
+	if h.Sparse {
+		flags |= flagSparse
+	}
+	b = append(b, Magic...)
+	b = append(b, Version, uint8(h.Type), h.Precision, uint8(h.HashFunc.ID), flags)
+	return binary.LittleEndian.AppendUint32(b, h.HashFunc.Seed)
+}
+
+// Decode reads the header of a counter of the expected type and returns the payload that follows it
+func Decode(data []byte, expected Type) (Header, []byte, error) {
+	h, payload, err := Peek(data)
+	if err != nil {
+		return Header{}, nil, err
+	}
+	if h.Type != expected {
+		return Header{}, nil, fmt.Errorf("serialized counter is a %v, expected %v", h.Type, expected)
+	}
+	return h, payload, nil
+}
+
+// Peek reads the header of a counter of any type and returns the payload that follows it
+func Peek(data []byte) (Header, []byte, error) {
+	if len(data) < len(Magic) || string(data[:len(Magic)]) != Magic {
+		return Header{}, nil, ErrInvalidMagic
+	}
+	if len(data) < Size {
+		return Header{}, nil, ErrTruncated
+	}
+	fields := data[len(Magic):Size]
+	if fields[0] != Version {
+		return Header{}, nil, fmt.Errorf("unsupported serialization version: %d", fields[0])
+	}
+	if fields[4]&^flagSparse != 0 {
+		return Header{}, nil, fmt.Errorf("unknown flags: %#x", fields[4])
+	}
+	h := Header{
+		Type:      Type(fields[1]),
+		Precision: fields[2],
+		HashFunc:  HashFunc{ID: HashID(fields[3]), Seed: binary.LittleEndian.Uint32(fields[5:])},
+		Sparse:    fields[4]&flagSparse != 0,
+	}
+	return h, data[Size:], nil
+}
@@ -0,0 +1,61 @@
+package header
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestHeaderRoundTrip(t *testing.T) {
+	h := Header{Type: TypeHyperLogLogPlus, Precision: 14, HashFunc: HashFunc{ID: HashMurmur3, Seed: 42}, Sparse: true}
+	data := append(h.Append(nil), 1, 2, 3)
+	if len(data) != Size+3 {
+		t.Fatalf("Expected %d bytes, got %d", Size+3, len(data))
+	}
+
+	decoded, payload, err := Decode(data, TypeHyperLogLogPlus)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if decoded != h {
+		t.Errorf("Expected header %+v, got %+v", h, decoded)
+	}
+	if string(payload) != "\x01\x02\x03" {
+		t.Errorf("Expected the payload after the header, got %v", payload)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	valid := Header{Type: TypeBitMap}.Append(nil)
+	badVersion := append([]byte{}, valid...)
+	badVersion[len(Magic)] = Version + 1
+	badFlags := append([]byte{}, valid...)
+	badFlags[len(Magic)+4] = 0x80
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr error
+	}{
+		{"Empty", nil, ErrInvalidMagic},
+		{"Wrong magic", []byte("ABCD12345"), ErrInvalidMagic},
+		{"Truncated", valid[:Size-1], ErrTruncated},
+		{"Version", badVersion, nil},
+		{"Flags", badFlags, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := Decode(tt.data, TypeBitMap)
+			if err == nil {
+				t.Fatalf("Expected an error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	if _, _, err := Decode(valid, TypeHyperLogLog); err == nil {
+		t.Errorf("Expected an error for a different counter type")
+	}
+}
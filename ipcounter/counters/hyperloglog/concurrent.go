@@ -0,0 +1,113 @@
+package hyperloglog
+
+import (
+	"awesomeProject/ipcounter/counters/header"
+	"awesomeProject/ipcounter/counters/internal/estimator"
+	"awesomeProject/ipcounter/counters/internal/registers"
+	"fmt"
+	"math"
+	"sync/atomic"
+)
+
+// registersPerWord is the number of 8-bit registers packed in a word of a Concurrent sketch
+const registersPerWord = 8
+
+// Concurrent is a HyperLogLog whose Add is safe for concurrent use without locks. The registers are packed
+// 8 to a 64-bit word and raised by a compare-and-swap loop, which retries when another register of the word changed.
+type Concurrent struct {
+	words     []atomic.Uint64
+	precision uint8
+	hashFunc  header.HashFunc
+}
+
+// NewConcurrent creates an empty concurrent sketch of 2^precision registers
+func NewConcurrent(precision uint8) (*Concurrent, error) {
+	if precision < estimator.MinPrecision || precision > estimator.MaxPrecision {
+		return nil, fmt.Errorf("invalid precision: %d, must be between %d and %d", precision, estimator.MinPrecision, estimator.MaxPrecision)
+	}
+	return &Concurrent{
+		words:     make([]atomic.Uint64, (1<<precision)/registersPerWord),
+		precision: precision,
+	}, nil
+}
+
+// Add adds the hash, it is safe for concurrent use
+func (c *Concurrent) Add(hash uint64) {
+	c.raise(registers.Position(hash, c.precision))
+}
+
+// raise sets the register to the rank if it is greater, with a compare-and-swap of its word
+func (c *Concurrent) raise(registerIndex uint64, rank uint8) {
+	word := &c.words[registerIndex/registersPerWord]
+	shift := registerIndex % registersPerWord * 8
+	for {
+		old := word.Load()
+		if uint8(old>>shift) >= rank {
+			return
+		}
+		if word.CompareAndSwap(old, old&^(0xff<<shift)|uint64(rank)<<shift) {
+			return
+		}
+	}
+}
+
+// registers unpacks a copy of the registers
+func (c *Concurrent) registers() []uint8 {
+	registers := make([]uint8, len(c.words)*registersPerWord)
+	for i := range c.words {
+		word := c.words[i].Load()
+		for j := range registersPerWord {
+			registers[i*registersPerWord+j] = uint8(word >> (j * 8))
+		}
+	}
+	return registers
+}
+
+func (c *Concurrent) Count() uint64 {
+	return uint64(math.Round(estimator.Estimate(c.registers())))
+}
+
+// Merge adds the other sketch by raising the registers to the register-wise maximum, both sketches must have
+// the same precision. It is safe for concurrent use with Add.
+func (c *Concurrent) Merge(other *HyperLogLog) error {
+	if c.precision != other.precision {
+		return fmt.Errorf("can't merge sketches of different precision: %d and %d", c.precision, other.precision)
+	}
+	if c.hashFunc != other.hashFunc {
+		return fmt.Errorf("can't merge sketches of different hash functions: %v and %v", c.hashFunc, other.hashFunc)
+	}
+	for i, rank := range other.registers {
+		if rank != 0 {
+			c.raise(uint64(i), rank)
+		}
+	}
+	return nil
+}
+
+// HyperLogLog returns a copy of the sketch, e.g. to serialize it. Hashes added concurrently with the copy
+// may be missing from it.
+func (c *Concurrent) HyperLogLog() *HyperLogLog {
+	h, _ := New(c.precision)
+	h.registers = c.registers()
+	h.hashFunc = c.hashFunc
+	return h
+}
+
+// Precision returns the number of bits used for addressing registers
+func (c *Concurrent) Precision() uint8 {
+	return c.precision
+}
+
+// StandardError returns the relative standard error of the count
+func (c *Concurrent) StandardError() float64 {
+	return 1.04 / math.Sqrt(float64(len(c.words)*registersPerWord))
+}
+
+// SetHashFunc records the hash function applied to the IPs, it is stored when the sketch is serialized
+func (c *Concurrent) SetHashFunc(hashFunc header.HashFunc) {
+	c.hashFunc = hashFunc
+}
+
+func (c *Concurrent) HashFunc() header.HashFunc {
+	return c.hashFunc
+}
@@ -0,0 +1,94 @@
+package hyperloglog
+
+import (
+	"awesomeProject/ipcounter/counters/header"
+	"awesomeProject/ipcounter/utils/murmur3"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestConcurrent_Add(t *testing.T) {
+	if _, err := NewConcurrent(3); err == nil {
+		t.Errorf("Expected an error for precision 3")
+	}
+	c, err := NewConcurrent(10)
+	if err != nil {
+		t.Fatalf("NewConcurrent() error = %v", err)
+	}
+	hll, _ := New(10)
+
+	// The workers add overlapping hashes, so they race on the same registers and words
+	const workers = 8
+	const hashes = 50000
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := uint64(w % 4); i < hashes; i += 4 {
+				c.Add(murmur3.Fmix64(i))
+			}
+		}()
+	}
+	for i := uint64(0); i < hashes; i++ {
+		hll.Add(murmur3.Fmix64(i))
+	}
+	wg.Wait()
+
+	// The maximum of the ranks doesn't depend on the order of the adds
+	if !slices.Equal(c.registers(), hll.registers) {
+		t.Errorf("The registers differ from the ones of a sequential sketch")
+	}
+	if c.Count() != hll.Count() {
+		t.Errorf("Count() = %d, want %d", c.Count(), hll.Count())
+	}
+	if c.StandardError() != hll.StandardError() || c.Precision() != 10 {
+		t.Errorf("StandardError() = %f, want %f", c.StandardError(), hll.StandardError())
+	}
+}
+
+func TestConcurrent_Merge(t *testing.T) {
+	c, _ := NewConcurrent(8)
+	c.SetHashFunc(header.HashFunc{ID: header.HashFmix32})
+	c.Add(1<<57 + 2)
+
+	other, _ := New(8)
+	other.SetHashFunc(header.HashFunc{ID: header.HashFmix32})
+	other.Add(1<<57 + 8)
+	other.Add(1<<59 + 16)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		if err := c.Merge(other); err != nil {
+			t.Errorf("Merge() error = %v", err)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		c.Add(1<<58 + 8)
+	}()
+	wg.Wait()
+
+	copied := c.HyperLogLog()
+	for register, want := range map[int]uint8{2: 4, 4: 4, 8: 5} {
+		if copied.registers[register] != want {
+			t.Errorf("Expected register[%d] to be %d, got: %d", register, want, copied.registers[register])
+		}
+	}
+	if copied.HashFunc() != c.HashFunc() || copied.Count() != c.Count() {
+		t.Errorf("The copy should keep the hash function and the count")
+	}
+
+	differentPrecision, _ := New(9)
+	differentPrecision.SetHashFunc(c.HashFunc())
+	if err := c.Merge(differentPrecision); err == nil {
+		t.Errorf("Expected an error when merging sketches of different precision")
+	}
+	differentHash, _ := New(8)
+	if err := c.Merge(differentHash); err == nil {
+		t.Errorf("Expected an error when merging sketches of different hash functions")
+	}
+}
@@ -1,22 +1,23 @@
 package hyperloglog
 
 import (
+	"awesomeProject/ipcounter/counters/header"
+	"awesomeProject/ipcounter/counters/internal/estimator"
+	"awesomeProject/ipcounter/counters/internal/registers"
 	"fmt"
 	"math"
-	"math/bits"
 )
 
-const two32 = 1 << 32
-
 type HyperLogLog struct {
 	registers    []uint8 // Array of registers
 	precision    uint8   // Precision (number of bits for addressing registers)
 	numRegisters uint32  // Number of registers (2^precision)
+	hashFunc     header.HashFunc
 }
 
 func New(precision uint8) (*HyperLogLog, error) {
-	if precision < 4 || precision > 16 {
-		return nil, fmt.Errorf("invalid precision: %d, must be between 4 and 16", precision)
+	if precision < estimator.MinPrecision || precision > estimator.MaxPrecision {
+		return nil, fmt.Errorf("invalid precision: %d, must be between %d and %d", precision, estimator.MinPrecision, estimator.MaxPrecision)
 	}
 
 	numRegisters := uint32(1 << precision)
@@ -27,78 +28,70 @@ func New(precision uint8) (*HyperLogLog, error) {
 	}, nil
 }
 
-func (h *HyperLogLog) Add(hash uint32) {
-	// Extract register address from the most significant bits of the hash
-	registerMask := uint32(((1 << 32) - 1) << (32 - h.precision))
-	registerIndex := (hash & registerMask) >> (32 - h.precision)
-
-	// Clear the bits used for the register address
-	remainingHash := hash ^ (hash & registerMask)
-
-	// Count the number of leading zeros + 1
-	leadingZeros := countTrailingRightZeros(remainingHash) + 1
+func (h *HyperLogLog) Add(hash uint64) {
+	registerIndex, rank := registers.Position(hash, h.precision)
 	// Update the register if the new value is greater
-	if leadingZeros > h.registers[registerIndex] {
-		h.registers[registerIndex] = leadingZeros
+	if rank > h.registers[registerIndex] {
+		h.registers[registerIndex] = rank
 	}
 }
 
 func (h *HyperLogLog) Count() uint64 {
-	estimate := calculateRawEstimate(h.registers)
+	return uint64(math.Round(estimator.Estimate(h.registers)))
+}
 
-	if estimate <= 2.5*float64(h.numRegisters) {
-		// Use linear counting for small values
-		zeroRegisters := countZeroRegisters(h.registers)
-		if zeroRegisters != 0 {
-			return uint64(linearCounting(h.numRegisters, zeroRegisters))
-		}
-		return uint64(estimate)
-	} else if estimate < two32/30 {
-		// Use raw estimate for medium values
-		return uint64(estimate)
+// Merge adds the other sketch by taking the register-wise maximum, both sketches must have the same precision
+func (h *HyperLogLog) Merge(other *HyperLogLog) error {
+	if h.precision != other.precision {
+		return fmt.Errorf("can't merge sketches of different precision: %d and %d", h.precision, other.precision)
 	}
-
-	// Use correction for large values
-	return uint64(-two32 * math.Log(1-estimate/two32))
+	if h.hashFunc != other.hashFunc {
+		return fmt.Errorf("can't merge sketches of different hash functions: %v and %v", h.hashFunc, other.hashFunc)
+	}
+	registers.Merge(h.registers, other.registers)
+	return nil
 }
 
-func calculateRawEstimate(registers []uint8) float64 {
-	sum := 0.0
-	for _, val := range registers {
-		sum += 1.0 / float64(uint64(1)<<val)
-	}
+// Precision returns the number of bits used for addressing registers
+func (h *HyperLogLog) Precision() uint8 {
+	return h.precision
+}
 
-	m := uint32(len(registers))
-	return alpha(m) * float64(m) * float64(m) / sum
+// StandardError returns the relative standard error of the count
+func (h *HyperLogLog) StandardError() float64 {
+	return 1.04 / math.Sqrt(float64(h.numRegisters))
 }
 
-func linearCounting(m uint32, v uint32) float64 {
-	return float64(m) * math.Log(float64(m)/float64(v))
+// SetHashFunc records the hash function applied to the IPs, it is stored when the sketch is serialized
+func (h *HyperLogLog) SetHashFunc(hashFunc header.HashFunc) {
+	h.hashFunc = hashFunc
 }
 
-func countZeroRegisters(registers []uint8) uint32 {
-	var count uint32
-	for _, v := range registers {
-		if v == 0 {
-			count++
-		}
-	}
-	return count
+func (h *HyperLogLog) HashFunc() header.HashFunc {
+	return h.hashFunc
 }
 
-func alpha(m uint32) float64 {
-	switch m {
-	case 16:
-		return 0.673
-	case 32:
-		return 0.697
-	case 64:
-		return 0.709
-	default:
-		return 0.7213 / (1 + 1.079/float64(m))
-	}
+// MarshalBinary implements encoding.BinaryMarshaler, the payload is the registers
+func (h *HyperLogLog) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 0, header.Size+len(h.registers))
+	data = header.Header{Type: header.TypeHyperLogLog, Precision: h.precision, HashFunc: h.hashFunc}.Append(data)
+	return append(data, h.registers...), nil
 }
 
-func countTrailingRightZeros(value uint32) uint8 {
-	return uint8(bits.TrailingZeros32(value))
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (h *HyperLogLog) UnmarshalBinary(data []byte) error {
+	hdr, payload, err := header.Decode(data, header.TypeHyperLogLog)
+	if err != nil {
+		return err
+	}
+	decoded, err := New(hdr.Precision)
+	if err != nil {
+		return err
+	}
+	if err := registers.Decode(decoded.registers, payload); err != nil {
+		return err
+	}
+	decoded.hashFunc = hdr.HashFunc
+	*h = *decoded
+	return nil
 }
@@ -1,22 +1,44 @@
 package hyperloglog
 
 import (
+	"awesomeProject/ipcounter/counters/header"
+	"awesomeProject/ipcounter/utils/murmur3"
 	"fmt"
-	"math"
 	"testing"
 )
 
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		precision uint8
+		wantErr   bool
+	}{
+		{3, true},
+		{4, false},
+		{18, false},
+		{19, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("Precision%d", tc.precision), func(t *testing.T) {
+			_, err := New(tc.precision)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("New(%d) error = %v, wantErr %v", tc.precision, err, tc.wantErr)
+			}
+		})
+	}
+}
+
 func TestHyperLogLogAdd(t *testing.T) {
 	hll, _ := New(8)
 
 	testCases := []struct {
-		hash             uint32
+		hash             uint64
 		expectedRegister int
 		expectedValue    uint8
 	}{
-		{uint32(math.Pow(2, 25) + 2), 2, 2},
-		{uint32(math.Pow(2, 26) + 8), 4, 4},
-		{uint32(math.Pow(2, 27) + 16), 8, 5},
+		{1<<57 + 2, 2, 2},
+		{1<<58 + 8, 4, 4},
+		{1<<59 + 16, 8, 5},
 	}
 
 	for _, tc := range testCases {
@@ -33,13 +55,13 @@ func TestHyperLogLogPlusCount(t *testing.T) {
 	hll, _ := New(8)
 
 	testCases := []struct {
-		hash     uint32
+		hash     uint64
 		expected uint64
 	}{
-		{uint32(math.Pow(2, 25) + 2), 1},
-		{uint32(math.Pow(2, 26) + 4), 2},
-		{uint32(math.Pow(2, 27) + 8), 3},
-		{uint32(math.Pow(2, 27) + 8), 3}, // Duplicate, should not increase count
+		{1<<57 + 2, 1},
+		{1<<58 + 4, 2},
+		{1<<59 + 8, 3},
+		{1<<59 + 8, 3}, // Duplicate, should not increase count
 	}
 
 	for i, tc := range testCases {
@@ -50,3 +72,107 @@ func TestHyperLogLogPlusCount(t *testing.T) {
 		}
 	}
 }
+
+func TestMerge(t *testing.T) {
+	testCases := []struct {
+		name   string
+		first  int
+		second int
+	}{
+		{"Sparse to sparse", 50, 50},
+		{"Sparse to sparse over threshold", 150, 150},
+		{"Sparse to dense", 1000, 50},
+		{"Dense to sparse", 50, 1000},
+		{"Dense to dense", 1000, 1000},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			hll, _ := New(8)
+			other, _ := New(8)
+			expected, _ := New(8)
+			// The second range overlaps the first one
+			for i := 0; i < tc.first; i++ {
+				hash := murmur3.Sum64([]byte(fmt.Sprintf("%d", i)))
+				hll.Add(hash)
+				expected.Add(hash)
+			}
+			for i := tc.first / 2; i < tc.first/2+tc.second; i++ {
+				hash := murmur3.Sum64([]byte(fmt.Sprintf("%d", i)))
+				other.Add(hash)
+				expected.Add(hash)
+			}
+
+			if err := hll.Merge(other); err != nil {
+				t.Fatalf("Merge() error = %v", err)
+			}
+			if hll.Count() != expected.Count() {
+				t.Errorf("Expected count %d after merge, got %d", expected.Count(), hll.Count())
+			}
+		})
+	}
+
+	otherPrecision, _ := New(9)
+	hll, _ := New(8)
+	if err := hll.Merge(otherPrecision); err == nil {
+		t.Errorf("Expected an error when merging sketches of different precision")
+	}
+
+	otherHash, _ := New(8)
+	otherHash.SetHashFunc(header.HashFunc{ID: header.HashFNV1a})
+	if err := hll.Merge(otherHash); err == nil {
+		t.Errorf("Expected an error when merging sketches of different hash functions")
+	}
+}
+
+func TestMergeRegisters(t *testing.T) {
+	hll, _ := New(8)
+	other, _ := New(8)
+	hll.registers[1], other.registers[1] = 3, 2
+	hll.registers[2], other.registers[2] = 1, 5
+
+	if err := hll.Merge(other); err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+	if hll.registers[1] != 3 || hll.registers[2] != 5 {
+		t.Errorf("Expected the register-wise maximum, got %d and %d", hll.registers[1], hll.registers[2])
+	}
+}
+
+func TestMarshalBinary(t *testing.T) {
+	testCases := []struct {
+		name string
+		n    int
+	}{
+		{"Dense", 1000},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			hll, _ := New(8)
+			hll.SetHashFunc(header.HashFunc{ID: header.HashMurmur3, Seed: 7})
+			for i := 0; i < tc.n; i++ {
+				hll.Add(murmur3.Sum64([]byte(fmt.Sprintf("%d", i))))
+			}
+
+			data, err := hll.MarshalBinary()
+			if err != nil {
+				t.Fatalf("MarshalBinary() error = %v", err)
+			}
+			decoded, _ := New(4)
+			if err := decoded.UnmarshalBinary(data); err != nil {
+				t.Fatalf("UnmarshalBinary() error = %v", err)
+			}
+			if decoded.Precision() != 8 || decoded.HashFunc() != (header.HashFunc{ID: header.HashMurmur3, Seed: 7}) {
+				t.Errorf("Expected precision 8 and hash murmur3, got %d and %v", decoded.Precision(), decoded.HashFunc())
+			}
+			if decoded.Count() != hll.Count() {
+				t.Errorf("Expected count %d after unmarshal, got %d", hll.Count(), decoded.Count())
+			}
+
+			if err := decoded.UnmarshalBinary(data[:len(data)-1]); err == nil {
+				t.Errorf("Expected an error for a truncated sketch")
+			}
+		})
+	}
+}
@@ -1,85 +1,146 @@
 package hyperloglogplus
 
 import (
+	"awesomeProject/ipcounter/counters/header"
+	"awesomeProject/ipcounter/utils/murmur3"
 	"fmt"
 	"math"
+	"math/rand/v2"
 	"testing"
 )
 
-func TestHyperLogLogPlusAddSparse(t *testing.T) {
+func TestHyperLogLogPlusCount(t *testing.T) {
 	hll, _ := New(8)
 
 	testCases := []struct {
-		hash     uint32
-		expected bool
+		hash     uint64
+		expected uint64
+	}{
+		{1<<57 + 2, 1},
+		{1<<58 + 4, 2},
+		{1<<59 + 8, 3},
+		{1<<59 + 8, 3}, // Duplicate, should not increase count
+	}
+
+	for i, tc := range testCases {
+		hll.Add(tc.hash)
+		count := hll.Count()
+		if count != tc.expected {
+			t.Errorf("After adding %d elements, expected count: %d, got: %d", i+1, tc.expected, count)
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	testCases := []struct {
+		name   string
+		first  int
+		second int
 	}{
-		{uint32(math.Pow(2, 25) + 2), true},
-		{uint32(math.Pow(2, 26) + 8), true},
+		{"Sparse to sparse", 50, 50},
+		{"Sparse to sparse over threshold", 150, 150},
+		{"Sparse to dense", 1000, 50},
+		{"Dense to sparse", 50, 1000},
+		{"Dense to dense", 1000, 1000},
 	}
 
 	for _, tc := range testCases {
-		t.Run(fmt.Sprintf("Hash%d", tc.hash), func(t *testing.T) {
-			hll.Add(tc.hash)
-			if _, ok := hll.SparseSet[tc.hash]; ok != tc.expected {
-				t.Errorf("Expected sparseSet to contain %d: %v, got: %v", tc.hash, tc.expected, ok)
+		t.Run(tc.name, func(t *testing.T) {
+			hll, _ := New(8)
+			other, _ := New(8)
+			expected, _ := New(8)
+			// The second range overlaps the first one
+			for i := 0; i < tc.first; i++ {
+				hash := murmur3.Sum64([]byte(fmt.Sprintf("%d", i)))
+				hll.Add(hash)
+				expected.Add(hash)
+			}
+			for i := tc.first / 2; i < tc.first/2+tc.second; i++ {
+				hash := murmur3.Sum64([]byte(fmt.Sprintf("%d", i)))
+				other.Add(hash)
+				expected.Add(hash)
+			}
+
+			if err := hll.Merge(other); err != nil {
+				t.Fatalf("Merge() error = %v", err)
+			}
+			if hll.IsSparse != expected.IsSparse {
+				t.Errorf("Expected sparse %v after merge, got %v", expected.IsSparse, hll.IsSparse)
+			}
+			if hll.Count() != expected.Count() {
+				t.Errorf("Expected count %d after merge, got %d", expected.Count(), hll.Count())
 			}
 		})
 	}
 
-	zeroCount := 0
-	for _, v := range hll.registers {
-		if v == 0 {
-			zeroCount++
-		}
+	otherPrecision, _ := New(9)
+	hll, _ := New(8)
+	if err := hll.Merge(otherPrecision); err == nil {
+		t.Errorf("Expected an error when merging sketches of different precision")
 	}
-	expectedZeroCount := int(math.Pow(2, 8))
-	if zeroCount != expectedZeroCount {
-		t.Errorf("Expected %d zero registers, got: %d", expectedZeroCount, zeroCount)
+
+	otherHash, _ := New(8)
+	otherHash.SetHashFunc(header.HashFunc{ID: header.HashFNV1a})
+	if err := hll.Merge(otherHash); err == nil {
+		t.Errorf("Expected an error when merging sketches of different hash functions")
 	}
 }
 
-func TestHyperLogLogPlusAddNotSparse(t *testing.T) {
-	hll, _ := New(8)
-	hll.IsSparse = false
-
+func TestMarshalBinary(t *testing.T) {
 	testCases := []struct {
-		hash             uint32
-		expectedRegister int
-		expectedValue    uint8
+		name string
+		n    int
 	}{
-		{uint32(math.Pow(2, 25) + 2), 2, 2},
-		{uint32(math.Pow(2, 26) + 8), 4, 4},
-		{uint32(math.Pow(2, 27) + 16), 8, 5},
+		{"Sparse", 100},
+		{"Dense", 1000},
 	}
 
 	for _, tc := range testCases {
-		t.Run(fmt.Sprintf("Hash%d", tc.hash), func(t *testing.T) {
-			hll.Add(tc.hash)
-			if hll.registers[tc.expectedRegister] != tc.expectedValue {
-				t.Errorf("Expected register[%d] to be %d, got: %d", tc.expectedRegister, tc.expectedValue, hll.registers[tc.expectedRegister])
+		t.Run(tc.name, func(t *testing.T) {
+			hll, _ := New(8)
+			hll.SetHashFunc(header.HashFunc{ID: header.HashMurmur3, Seed: 7})
+			for i := 0; i < tc.n; i++ {
+				hll.Add(murmur3.Sum64([]byte(fmt.Sprintf("%d", i))))
+			}
+
+			data, err := hll.MarshalBinary()
+			if err != nil {
+				t.Fatalf("MarshalBinary() error = %v", err)
+			}
+			decoded, _ := New(4)
+			if err := decoded.UnmarshalBinary(data); err != nil {
+				t.Fatalf("UnmarshalBinary() error = %v", err)
+			}
+			if decoded.Precision() != 8 || decoded.HashFunc() != (header.HashFunc{ID: header.HashMurmur3, Seed: 7}) {
+				t.Errorf("Expected precision 8 and hash murmur3, got %d and %v", decoded.Precision(), decoded.HashFunc())
+			}
+			if decoded.Count() != hll.Count() {
+				t.Errorf("Expected count %d after unmarshal, got %d", hll.Count(), decoded.Count())
+			}
+
+			if err := decoded.UnmarshalBinary(data[:len(data)-1]); err == nil {
+				t.Errorf("Expected an error for a truncated sketch")
 			}
 		})
 	}
 }
 
-func TestHyperLogLogPlusCount(t *testing.T) {
-	hll, _ := New(8)
-
-	testCases := []struct {
-		hash     uint32
-		expected uint64
-	}{
-		{uint32(math.Pow(2, 25) + 2), 1},
-		{uint32(math.Pow(2, 26) + 4), 2},
-		{uint32(math.Pow(2, 27) + 8), 3},
-		{uint32(math.Pow(2, 27) + 8), 3}, // Duplicate, should not increase count
-	}
+func TestCountAccuracy(t *testing.T) {
+	const precision = 12
+	standardError := 1.04 / math.Sqrt(1<<precision)
+	rng := rand.New(rand.NewPCG(1, 2))
 
-	for i, tc := range testCases {
-		hll.Add(tc.hash)
-		count := hll.Count()
-		if count != tc.expected {
-			t.Errorf("After adding %d elements, expected count: %d, got: %d", i+1, tc.expected, count)
-		}
+	// Sparse, the switch to dense, the bias correction range and the raw estimate
+	for _, n := range []int{100, 1000, 3000, 5000, 10000, 15000, 20000, 30000, 100000, 1000000} {
+		t.Run(fmt.Sprintf("Cardinality%d", n), func(t *testing.T) {
+			hll, _ := New(precision)
+			for i := 0; i < n; i++ {
+				hll.Add(rng.Uint64())
+			}
+			relativeError := math.Abs(float64(hll.Count())/float64(n) - 1)
+			if relativeError > 4*standardError {
+				t.Errorf("Relative error %.4f, expected below %.4f", relativeError, 4*standardError)
+			}
+		})
 	}
 }
@@ -0,0 +1,781 @@
+// Code generated by gen; DO NOT EDIT.
+
+package estimator
+
+// rawEstimateData Mean raw estimates at evenly spaced cardinalities up to 5m, per precision
+var rawEstimateData = [15][]float64{
+	{
+		11.23773, 11.72264, 12.22299, 12.73914, 13.27148, 13.81893, 14.3824, 14.96129,
+		15.55618, 16.16689, 16.79294, 17.43498, 18.09354, 18.76651, 19.45475, 20.15717,
+		20.87458, 21.60653, 22.35282, 23.11123, 23.88445, 24.66928, 25.46766, 26.27889,
+		27.09931, 27.93371, 28.77833, 29.6327, 30.4972, 31.37091, 32.25147, 33.1427,
+		34.04388, 34.95034, 35.86728, 36.78769, 37.716, 38.64807, 39.58498, 40.52966,
+		41.47445, 42.42763, 43.38513, 44.34659, 45.30982, 46.27866, 47.24548, 48.22055,
+		49.19822, 50.17771, 51.1584, 52.13843, 53.12474, 54.11031, 55.09946, 56.08871,
+		57.07611, 58.06958, 59.05878, 60.0498, 61.04376, 62.03543, 63.02698, 64.01762,
+		65.01643, 66.00617, 67.00503, 67.99785, 68.99728, 69.99488, 70.99677, 71.9955,
+		72.99565, 73.99303, 74.98947, 75.9864, 76.98496, 77.98552, 78.98098, 79.97853,
+	},
+	{
+		22.77924, 23.26173, 23.75177, 24.2491, 24.75398, 25.26658, 25.78673, 26.3142,
+		26.84948, 27.3917, 27.94217, 28.50011, 29.06453, 29.6375, 30.21844, 30.80693,
+		31.40116, 32.00265, 32.61322, 33.23035, 33.85575, 34.48865, 35.12877, 35.77476,
+		36.42881, 37.09131, 37.76017, 38.4355, 39.11847, 39.80689, 40.50289, 41.20666,
+		41.9167, 42.63433, 43.35899, 44.08804, 44.8252, 45.56839, 46.31897, 47.0773,
+		47.84191, 48.6127, 49.38745, 50.16557, 50.95305, 51.74465, 52.54246, 53.34695,
+		54.1568, 54.97082, 55.78892, 56.61408, 57.44422, 58.27888, 59.12033, 59.9663,
+		60.81645, 61.67262, 62.53052, 63.39124, 64.25754, 65.12875, 66.00582, 66.89012,
+		67.7745, 68.66314, 69.55409, 70.45187, 71.34894, 72.25242, 73.158, 74.06644,
+		74.97982, 75.89246, 76.80929, 77.72996, 78.65388, 79.5805, 80.51389, 81.44877,
+		82.38649, 83.32232, 84.2641, 85.21146, 86.15665, 87.10324, 88.05116, 89.00515,
+		89.95681, 90.90838, 91.86925, 92.82665, 93.78723, 94.7505, 95.71248, 96.68002,
+		97.64862, 98.6164, 99.58506, 100.5595, 101.5295, 102.5047, 103.4815, 104.4557,
+		105.4347, 106.4116, 107.3911, 108.369, 109.3514, 110.3361, 111.3202, 112.3044,
+		113.2879, 114.2664, 115.253, 116.242, 117.232, 118.218, 119.2094, 120.1993,
+		121.1873, 122.175, 123.1659, 124.1563, 125.1433, 126.1327, 127.1236, 128.1212,
+		129.1179, 130.1106, 131.0999, 132.0925, 133.0872, 134.0848, 135.0727, 136.0668,
+		137.0664, 138.0668, 139.0613, 140.0569, 141.0563, 142.0539, 143.0544, 144.0513,
+		145.0502, 146.0432, 147.0401, 148.0411, 149.0386, 150.0372, 151.0334, 152.0324,
+		153.0287, 154.0259, 155.0225, 156.0161, 157.0122, 158.0056, 159.0008, 159.9995,
+	},
+	{
+		45.85414, 46.82132, 47.31062, 48.29904, 49.3031, 49.80949, 50.83491, 51.35282,
+		52.40012, 53.46102, 53.99724, 55.08383, 55.63206, 56.7379, 57.85894, 58.42524,
+		59.56975, 60.14717, 61.31248, 62.49262, 63.08775, 64.28872, 64.8962, 66.11987,
+		67.35831, 67.98264, 69.24062, 69.87653, 71.15766, 72.4533, 73.1055, 74.42125,
+		75.08508, 76.42236, 77.77107, 78.45051, 79.81759, 80.50977, 81.89691, 83.30116,
+		84.00777, 85.432, 86.14971, 87.5929, 89.04636, 89.77828, 91.2505, 91.99209,
+		93.48823, 94.99323, 95.75406, 97.28304, 98.05155, 99.59549, 101.1475, 101.9287,
+		103.4969, 104.2888, 105.8757, 107.4724, 108.2758, 109.8932, 110.7044, 112.3348,
+		113.9737, 114.7989, 116.4495, 117.2833, 118.9554, 120.6348, 121.48, 123.17,
+		124.0192, 125.7288, 127.4382, 128.2997, 130.0259, 130.8959, 132.6378, 134.3914,
+		135.2666, 137.0233, 137.9077, 139.6762, 141.4532, 142.3501, 144.1344, 145.0352,
+		146.8436, 148.652, 149.5566, 151.374, 152.2872, 154.1096, 155.9399, 156.8599,
+		158.6928, 159.6123, 161.4613, 163.3152, 164.2483, 166.1065, 167.0362, 168.9051,
+		170.7832, 171.7176, 173.5939, 174.5366, 176.424, 178.3091, 179.252, 181.1528,
+		182.102, 184.0062, 185.9078, 186.8671, 188.7781, 189.7308, 191.6442, 193.5683,
+		194.529, 196.4584, 197.4229, 199.3472, 201.2853, 202.2556, 204.1934, 205.1679,
+		207.1065, 209.0591, 210.0355, 211.9798, 212.9575, 214.9096, 216.8533, 217.8366,
+		219.7836, 220.7701, 222.7274, 224.6857, 225.6673, 227.6343, 228.6181, 230.5818,
+		232.5566, 233.5439, 235.5077, 236.5006, 238.4712, 240.4392, 241.4246, 243.4046,
+		244.3902, 246.3576, 248.3354, 249.3339, 251.3305, 252.3126, 254.2945, 256.291,
+		257.2852, 259.2673, 260.2574, 262.2305, 264.2034, 265.1968, 267.1777, 268.171,
+		270.1586, 272.1463, 273.1255, 275.1168, 276.1096, 278.1017, 280.0912, 281.0819,
+		283.0645, 284.0539, 286.0491, 288.0467, 289.0419, 291.0187, 292.0149, 294.0036,
+		295.9852, 296.9837, 298.9738, 299.9744, 301.9677, 303.9584, 304.9484, 306.9453,
+		307.942, 309.9457, 311.9286, 312.9241, 314.912, 315.9107, 317.9009, 319.8857,
+	},
+	{
+		92.9981, 94.45897, 95.93572, 97.42904, 99.4472, 100.9786, 102.5261, 104.0882,
+		105.6683, 107.8024, 109.4218, 111.0572, 112.7094, 114.3768, 116.628, 118.3326,
+		120.0531, 121.7909, 123.547, 125.9142, 127.7063, 129.5177, 131.3418, 133.1832,
+		135.6637, 137.5415, 139.4377, 141.3429, 143.267, 145.8539, 147.8137, 149.7963,
+		151.7873, 153.7978, 156.4937, 158.5402, 160.5975, 162.6664, 164.7563, 167.571,
+		169.6928, 171.8252, 173.9739, 176.1403, 179.0524, 181.2461, 183.4636, 185.6964,
+		187.9372, 190.9473, 193.2214, 195.5034, 197.802, 200.1147, 203.2139, 205.5621,
+		207.9137, 210.2763, 212.6572, 215.8544, 218.2561, 220.6725, 223.1078, 225.544,
+		228.825, 231.2961, 233.7833, 236.2766, 238.7703, 242.1263, 244.6587, 247.1933,
+		249.7371, 252.2838, 255.7103, 258.2794, 260.8678, 263.4651, 266.0735, 269.5686,
+		272.1986, 274.8355, 277.4714, 280.1273, 283.6754, 286.3374, 289.0088, 291.6992,
+		294.3854, 297.9826, 300.6878, 303.4013, 306.1377, 308.8744, 312.5281, 315.2777,
+		318.0418, 320.7983, 323.5647, 327.2617, 330.0391, 332.8421, 335.6385, 338.444,
+		342.1684, 344.9702, 347.7688, 350.5705, 353.3959, 357.1842, 360.0306, 362.8684,
+		365.7275, 368.5762, 372.3846, 375.2457, 378.0992, 380.9516, 383.8168, 387.642,
+		390.5105, 393.3797, 396.246, 399.1475, 403.0054, 405.919, 408.8255, 411.7215,
+		414.6383, 418.5128, 421.4329, 424.3482, 427.2717, 430.1845, 434.0798, 436.9995,
+		439.9415, 442.8518, 445.7953, 449.6896, 452.6372, 455.5769, 458.5172, 461.4784,
+		465.3908, 468.3303, 471.2748, 474.2354, 477.1903, 481.1274, 484.0764, 487.0485,
+		489.9962, 492.9685, 496.9251, 499.8956, 502.8739, 505.8456, 508.7906, 512.7784,
+		515.7486, 518.7275, 521.6988, 524.6654, 528.6323, 531.6091, 534.5968, 537.5605,
+		540.5429, 544.4995, 547.4724, 550.4682, 553.4268, 556.4055, 560.4037, 563.3659,
+		566.3617, 569.3379, 572.3242, 576.305, 579.2983, 582.2589, 585.2378, 588.2076,
+		592.2003, 595.199, 598.1853, 601.171, 604.1821, 608.1923, 611.1895, 614.1587,
+		617.1578, 620.1485, 624.1342, 627.1056, 630.0815, 633.0699, 636.0734, 640.0967,
+	},
+	{
+		186.7731, 189.6994, 193.1576, 196.1543, 199.692, 202.7604, 205.859, 209.5193,
+		212.6903, 216.4287, 219.671, 222.9454, 226.8051, 230.1541, 234.1039, 237.5195,
+		240.965, 245.03, 248.549, 252.6952, 256.2781, 259.9034, 264.1667, 267.8544,
+		272.198, 275.9556, 279.7494, 284.2088, 288.0711, 292.613, 296.5437, 300.5,
+		305.1602, 309.1846, 313.921, 318.0127, 322.1276, 326.9748, 331.1527, 336.054,
+		340.2941, 344.572, 349.5945, 353.9337, 359.0207, 363.4259, 367.8611, 373.0553,
+		377.5398, 382.8044, 387.3269, 391.8918, 397.2392, 401.8561, 407.2796, 411.9528,
+		416.6515, 422.1833, 426.9313, 432.5088, 437.3235, 442.1742, 447.825, 452.7038,
+		458.4152, 463.335, 468.2927, 474.1037, 479.103, 484.9507, 490.0142, 495.0718,
+		501.0119, 506.1269, 512.0926, 517.2224, 522.4063, 528.462, 533.6811, 539.7813,
+		545.0287, 550.283, 556.4465, 561.7602, 567.9685, 573.2893, 578.6505, 584.9256,
+		590.3088, 596.6222, 602.0498, 607.4759, 613.8457, 619.3027, 625.7131, 631.1995,
+		636.6946, 643.1536, 648.6748, 655.1276, 660.6684, 666.2316, 672.7654, 678.3819,
+		684.8885, 690.5158, 696.158, 702.7201, 708.3709, 714.9825, 720.6473, 726.3136,
+		732.9311, 738.6474, 745.3124, 751.0293, 756.7507, 763.419, 769.1678, 775.8481,
+		781.5752, 787.3508, 794.0804, 799.8108, 806.5687, 812.3773, 818.1742, 824.9643,
+		830.7725, 837.5656, 843.367, 849.2249, 856.0368, 861.8795, 868.6916, 874.5385,
+		880.3997, 887.2299, 893.1009, 899.9817, 905.8582, 911.7231, 918.6294, 924.5426,
+		931.4561, 937.3477, 943.2548, 950.1368, 956.0376, 962.9631, 968.8763, 974.7919,
+		981.6886, 987.632, 994.5549, 1000.452, 1006.363, 1013.276, 1019.216, 1026.144,
+		1032.036, 1037.986, 1044.924, 1050.872, 1057.808, 1063.736, 1069.683, 1076.614,
+		1082.57, 1089.5, 1095.421, 1101.389, 1108.388, 1114.371, 1121.322, 1127.35,
+		1133.282, 1140.238, 1146.202, 1153.215, 1159.204, 1165.17, 1172.123, 1178.134,
+		1185.078, 1191.052, 1197.04, 1203.991, 1209.964, 1216.936, 1222.933, 1228.897,
+		1235.887, 1241.831, 1248.779, 1254.773, 1260.762, 1267.718, 1273.732, 1280.695,
+	},
+	{
+		374.3251, 380.678, 387.1045, 393.6104, 400.1881, 406.3337, 413.0597, 419.8679,
+		426.7491, 433.7072, 440.1976, 447.3041, 454.4772, 461.7162, 469.0495, 475.8759,
+		483.3626, 490.9265, 498.5659, 506.2698, 513.4442, 521.2946, 529.2103, 537.2025,
+		545.2741, 552.7873, 561.0108, 569.3033, 577.6597, 586.0876, 593.9354, 602.4999,
+		611.1191, 619.8239, 628.6048, 636.7725, 645.6763, 654.6777, 663.7307, 672.8525,
+		681.3396, 690.5999, 699.9096, 709.2983, 718.7878, 727.6116, 737.2224, 746.8591,
+		756.5852, 766.3494, 775.4143, 785.3265, 795.2789, 805.2762, 815.3414, 824.6863,
+		834.8634, 845.1416, 855.4576, 865.8146, 875.4289, 885.9242, 896.4421, 906.9633,
+		917.555, 927.417, 938.1387, 948.9006, 959.7429, 970.6095, 980.6858, 991.6386,
+		1002.664, 1013.727, 1024.801, 1035.068, 1046.217, 1057.464, 1068.731, 1080.083,
+		1090.602, 1102.034, 1113.448, 1124.966, 1136.479, 1147.183, 1158.75, 1170.39,
+		1182.08, 1193.804, 1204.662, 1216.421, 1228.231, 1240.04, 1251.88, 1262.869,
+		1274.806, 1286.781, 1298.762, 1310.777, 1321.837, 1333.935, 1345.981, 1358.09,
+		1370.191, 1381.421, 1393.654, 1405.866, 1418.071, 1430.305, 1441.645, 1453.945,
+		1466.235, 1478.517, 1490.841, 1502.274, 1514.66, 1527.054, 1539.505, 1551.905,
+		1563.471, 1575.905, 1588.346, 1600.913, 1613.359, 1625.021, 1637.545, 1650.11,
+		1662.634, 1675.215, 1686.871, 1699.461, 1712.055, 1724.684, 1737.282, 1748.964,
+		1761.633, 1774.392, 1787.116, 1799.857, 1811.608, 1824.312, 1837.059, 1849.837,
+		1862.607, 1874.343, 1887.099, 1899.847, 1912.581, 1925.431, 1937.235, 1950.013,
+		1962.887, 1975.748, 1988.495, 2000.373, 2013.244, 2026.111, 2038.957, 2051.775,
+		2063.585, 2076.451, 2089.275, 2102.085, 2114.962, 2126.83, 2139.756, 2152.604,
+		2165.352, 2178.215, 2190.082, 2203.021, 2215.915, 2228.788, 2241.733, 2253.743,
+		2266.811, 2279.771, 2292.735, 2305.674, 2317.56, 2330.487, 2343.443, 2356.341,
+		2369.283, 2381.249, 2394.243, 2407.258, 2420.092, 2433.044, 2445.03, 2457.858,
+		2470.87, 2483.814, 2496.863, 2508.776, 2521.661, 2534.609, 2547.667, 2560.61,
+	},
+	{
+		749.923, 762.6358, 774.9979, 788.0116, 801.175, 813.9893, 827.463, 840.5604,
+		854.3154, 868.22, 881.734, 895.9698, 909.7704, 924.2706, 938.9523, 953.1927,
+		968.1469, 982.661, 997.9309, 1013.341, 1028.319, 1044.019, 1059.259, 1075.261,
+		1091.401, 1107.069, 1123.494, 1139.441, 1156.189, 1173.062, 1189.424, 1206.551,
+		1223.215, 1240.608, 1258.137, 1275.11, 1292.971, 1310.259, 1328.351, 1346.623,
+		1364.288, 1382.805, 1400.742, 1419.536, 1438.474, 1456.745, 1475.919, 1494.455,
+		1513.826, 1533.364, 1552.307, 1572.123, 1591.237, 1611.282, 1631.439, 1650.898,
+		1671.28, 1690.969, 1711.569, 1732.241, 1752.24, 1773.272, 1793.475, 1814.675,
+		1835.855, 1856.342, 1877.776, 1898.573, 1920.211, 1941.998, 1962.894, 1984.796,
+		2005.883, 2027.855, 2049.98, 2071.369, 2093.771, 2115.379, 2137.887, 2160.54,
+		2182.299, 2205.038, 2227.12, 2249.965, 2273.04, 2295.145, 2318.382, 2340.726,
+		2364.112, 2387.507, 2410.087, 2433.564, 2456.323, 2479.958, 2503.711, 2526.573,
+		2550.444, 2573.331, 2597.232, 2621.152, 2644.181, 2668.272, 2691.532, 2715.712,
+		2739.956, 2763.245, 2787.66, 2811.03, 2835.536, 2860.161, 2883.701, 2908.322,
+		2931.906, 2956.594, 2981.263, 3004.918, 3029.671, 3053.488, 3078.375, 3103.069,
+		3126.987, 3152.008, 3176.124, 3201.015, 3226.012, 3250.186, 3275.339, 3299.621,
+		3324.754, 3349.995, 3374.154, 3399.291, 3423.644, 3448.878, 3474.261, 3498.622,
+		3524.011, 3548.345, 3573.666, 3599.079, 3623.547, 3649.091, 3673.721, 3699.162,
+		3724.483, 3749.04, 3774.482, 3798.912, 3824.456, 3849.975, 3874.7, 3900.435,
+		3924.991, 3950.564, 3976.11, 4000.877, 4026.603, 4051.341, 4077.134, 4103.009,
+		4127.588, 4153.287, 4178.023, 4203.766, 4229.579, 4254.274, 4280.075, 4304.859,
+		4330.642, 4356.386, 4381.096, 4407.056, 4431.757, 4457.556, 4483.433, 4508.402,
+		4534.164, 4558.929, 4584.882, 4610.848, 4635.892, 4661.733, 4686.622, 4712.538,
+		4738.4, 4763.046, 4788.975, 4813.818, 4839.666, 4865.61, 4890.43, 4916.305,
+		4941.145, 4967.11, 4992.629, 5017.51, 5043.585, 5068.555, 5094.394, 5120.297,
+	},
+	{
+		1501.116, 1526.058, 1551.299, 1576.835, 1603.191, 1629.315, 1655.739, 1682.476,
+		1709.477, 1737.299, 1764.863, 1792.777, 1820.936, 1849.414, 1878.752, 1907.803,
+		1937.166, 1966.793, 1996.749, 2027.573, 2058.074, 2088.866, 2119.987, 2151.377,
+		2183.625, 2215.632, 2247.851, 2280.419, 2313.21, 2346.953, 2380.323, 2414,
+		2447.926, 2482.119, 2517.221, 2551.894, 2586.873, 2622.279, 2657.829, 2694.342,
+		2730.421, 2766.81, 2803.47, 2840.368, 2878.152, 2915.45, 2953.154, 2990.967,
+		3028.989, 3068.068, 3106.822, 3145.562, 3184.509, 3223.748, 3263.98, 3303.64,
+		3343.502, 3383.761, 3423.995, 3465.373, 3506.1, 3547.15, 3588.37, 3629.794,
+		3672.287, 3714.1, 3756.233, 3798.399, 3840.89, 3884.29, 3927.128, 3969.976,
+		4013.109, 4056.59, 4100.841, 4144.672, 4188.52, 4232.601, 4276.926, 4322.196,
+		4366.751, 4411.578, 4456.442, 4501.378, 4547.403, 4592.579, 4638.102, 4683.684,
+		4729.476, 4776.089, 4821.936, 4868.016, 4914.393, 4960.634, 5008.021, 5054.539,
+		5101.318, 5148.126, 5195.116, 5243.043, 5290.143, 5337.517, 5384.843, 5432.417,
+		5480.878, 5528.477, 5576.017, 5624.093, 5671.985, 5720.838, 5769.046, 5817.203,
+		5865.523, 5913.917, 5963.268, 6011.668, 6060.523, 6109.119, 6157.724, 6207.239,
+		6255.886, 6304.569, 6353.506, 6402.492, 6452.704, 6501.683, 6551.093, 6600.45,
+		6649.511, 6699.838, 6749.428, 6799.126, 6848.699, 6898.283, 6948.812, 6998.666,
+		7048.25, 7097.988, 7147.85, 7198.828, 7248.584, 7298.474, 7348.675, 7398.513,
+		7449.554, 7499.787, 7549.9, 7600.056, 7650.119, 7701.493, 7751.78, 7802.03,
+		7852.325, 7902.249, 7953.442, 8003.877, 8054.314, 8104.466, 8154.729, 8206.103,
+		8256.367, 8307.155, 8357.41, 8408.06, 8459.344, 8509.854, 8560.193, 8610.964,
+		8661.946, 8713.529, 8764.074, 8814.613, 8865.248, 8915.62, 8967.157, 9017.584,
+		9068.331, 9118.968, 9170.009, 9221.805, 9272.8, 9323.353, 9373.805, 9424.408,
+		9475.817, 9526.503, 9577.287, 9627.76, 9678.603, 9730.369, 9781.121, 9831.967,
+		9882.502, 9933.375, 9985.392, 10036.22, 10087.15, 10137.7, 10188.58, 10240.52,
+	},
+	{
+		3002.993, 3052.92, 3103.933, 3154.99, 3207.114, 3259.36, 3312.174, 3366.135,
+		3420.228, 3475.368, 3530.604, 3586.446, 3643.347, 3700.302, 3758.528, 3816.642,
+		3875.368, 3935.237, 3995.197, 4056.321, 4117.307, 4178.937, 4241.687, 4304.483,
+		4368.521, 4432.43, 4496.938, 4562.719, 4628.412, 4695.193, 4761.908, 4829.12,
+		4897.685, 4966.009, 5035.628, 5104.978, 5174.91, 5246.233, 5317.331, 5389.462,
+		5461.632, 5534.411, 5608.332, 5681.965, 5756.84, 5831.634, 5906.942, 5983.154,
+		6059.453, 6136.971, 6214.128, 6291.905, 6371.132, 6449.712, 6529.413, 6608.874,
+		6688.831, 6769.932, 6850.654, 6932.944, 7014.603, 7096.94, 7180.427, 7263.542,
+		7347.807, 7431.274, 7515.224, 7600.438, 7685.491, 7771.553, 7857.322, 7943.413,
+		8030.776, 8117.341, 8205.05, 8292.541, 8380.086, 8468.739, 8557.117, 8646.78,
+		8736.038, 8824.983, 8915.649, 9005.989, 9097.452, 9188.241, 9278.518, 9370.144,
+		9461.556, 9554.221, 9645.914, 9738.062, 9831.515, 9924.61, 10018.69, 10111.74,
+		10205.58, 10300.19, 10394.38, 10489.6, 10583.94, 10678.32, 10774.2, 10869.41,
+		10965.03, 11060.56, 11156, 11252.9, 11348.37, 11445.1, 11541.15, 11637.2,
+		11734.73, 11831.67, 11929.4, 12027.06, 12124.6, 12222.75, 12320.03, 12418.79,
+		12516.69, 12614.56, 12713.73, 12811.53, 12910.94, 13009.45, 13107.79, 13207.13,
+		13305.79, 13405.8, 13505.26, 13604.34, 13704.25, 13803.12, 13903, 14002.49,
+		14101.68, 14202.22, 14301.92, 14403.13, 14503.16, 14603.06, 14703.86, 14803.25,
+		14904.26, 15004.4, 15104.89, 15206.49, 15307.17, 15408.59, 15509.06, 15609.41,
+		15710.6, 15811.25, 15912.86, 16013.99, 16114.58, 16216.04, 16316.91, 16418.24,
+		16518.38, 16619.2, 16721.18, 16822.42, 16924.55, 17025.92, 17127.13, 17229.53,
+		17331.07, 17433.39, 17534.64, 17635.47, 17737.85, 17838.67, 17941.45, 18042.4,
+		18143.99, 18246.36, 18348.12, 18450.1, 18551.42, 18652.87, 18755.35, 18857.34,
+		18959.93, 19062.05, 19162.61, 19265.71, 19366.75, 19469.02, 19570.61, 19671.89,
+		19774.83, 19875.74, 19977.88, 20080.77, 20182.85, 20285.48, 20387.76, 20491.1,
+	},
+	{
+		6006.834, 6107.157, 6208.62, 6311.286, 6415.261, 6519.774, 6625.962, 6733.373,
+		6841.908, 6951.783, 7062.207, 7174.497, 7287.863, 7402.244, 7518, 7634.341,
+		7752.453, 7871.662, 7992.162, 8113.928, 8236.064, 8359.856, 8484.877, 8610.895,
+		8738.108, 8866.286, 8995.976, 9126.655, 9258.595, 9391.607, 9524.645, 9660.16,
+		9796.471, 9933.824, 10072.34, 10211.27, 10352.21, 10493.84, 10636.6, 10780.62,
+		10924.93, 11071.02, 11217.94, 11365.73, 11514.66, 11663.89, 11815.04, 11967.15,
+		12120.11, 12274.07, 12428.48, 12584.63, 12741.92, 12900.18, 13059.1, 13217.85,
+		13378.49, 13540.37, 13702.77, 13865.92, 14028.77, 14194, 14359.5, 14526.38,
+		14694.05, 14861.04, 15029.76, 15199.4, 15369.93, 15540.57, 15711.44, 15883.76,
+		16056.92, 16230.58, 16405.15, 16580.81, 16757.3, 16934.6, 17112.15, 17290.48,
+		17468.02, 17647.1, 17826.95, 18007.49, 18188.77, 18369.83, 18551.59, 18734.18,
+		18916.97, 19100.99, 19285.33, 19470.46, 19656.49, 19843.15, 20029.9, 20216.7,
+		20404.46, 20592.67, 20781.24, 20969.67, 21157.19, 21347.05, 21536.69, 21727.96,
+		21919.24, 22110.02, 22301.54, 22493.51, 22686.51, 22879.87, 23073.51, 23267.7,
+		23460.69, 23654.62, 23848.75, 24043.95, 24238.72, 24433.9, 24629.92, 24825.35,
+		25019.66, 25216.68, 25414.19, 25611.47, 25809.13, 26005.35, 26202.75, 26401.01,
+		26599.07, 26796.8, 26993.41, 27192.17, 27390.36, 27589.53, 27789.92, 27988.48,
+		28187.7, 28388.41, 28589.37, 28789.68, 28989.9, 29190.96, 29391.71, 29591.98,
+		29792.42, 29993.12, 30194.42, 30394.98, 30596.15, 30799.19, 31000.39, 31202.33,
+		31404.45, 31605.74, 31808.34, 32009.17, 32211.12, 32414.15, 32616.6, 32819.18,
+		33021.4, 33225.89, 33428, 33630.15, 33833.9, 34036.88, 34240.25, 34443.4,
+		34646.57, 34849.6, 35051.76, 35255.49, 35460.64, 35665.45, 35870.31, 36071.34,
+		36275.6, 36478.62, 36682.21, 36886.39, 37089.65, 37293.29, 37498.26, 37701.3,
+		37904.58, 38107.68, 38310.95, 38514.2, 38719.91, 38923.76, 39126.49, 39329.04,
+		39533.42, 39736.95, 39941.86, 40146.12, 40350.81, 40554.64, 40760.24, 40965.11,
+	},
+	{
+		12014.87, 12215.43, 12417.89, 12623.33, 12830.92, 13040.67, 13253.29, 13467.7,
+		13684.67, 13904.31, 14125.58, 14349.93, 14576.27, 14805.28, 15036.67, 15270.02,
+		15506.04, 15743.65, 15984.34, 16227.56, 16472.6, 16720, 16969.4, 17221.84,
+		17476.43, 17732.46, 17991.21, 18252.06, 18515.56, 18781.74, 19048.78, 19319.62,
+		19591.41, 19866.5, 20143.74, 20422.45, 20704.25, 20987.22, 21272.74, 21560.23,
+		21849.61, 22142.15, 22434.96, 22730.65, 23028.08, 23327.52, 23630.07, 23933.73,
+		24239.71, 24548.18, 24857.99, 25170.08, 25483.83, 25799.51, 26116.94, 26436.56,
+		26757.22, 27078.82, 27403.72, 27730.91, 28057.46, 28388.37, 28719.04, 29053.33,
+		29388.31, 29723.86, 30060.8, 30399.46, 30740.55, 31083.24, 31425.83, 31770.78,
+		32117.45, 32464.98, 32814.7, 33165.04, 33517.25, 33870.37, 34225.5, 34582.41,
+		34939.1, 35298.21, 35657.09, 36018.54, 36382.4, 36744.72, 37108.64, 37474.27,
+		37842.02, 38209.92, 38578.54, 38949.55, 39320.3, 39693.34, 40068.68, 40442.66,
+		40818.46, 41193.39, 41570.94, 41949.7, 42327.35, 42707.5, 43087.38, 43469.94,
+		43852.54, 44234.49, 44619.9, 45001.84, 45388.58, 45772.58, 46158.49, 46545.15,
+		46933.55, 47319.8, 47709.75, 48097.71, 48486.49, 48874.78, 49265.71, 49658.86,
+		50051.58, 50446.25, 50837.35, 51230.94, 51625.74, 52019.75, 52414.33, 52809.41,
+		53204.3, 53599.94, 53995.08, 54394.14, 54790.85, 55192.75, 55591.72, 55990.92,
+		56392.76, 56790.52, 57190, 57589.73, 57988.4, 58387.6, 58786.74, 59189.44,
+		59592.7, 59997.69, 60401.18, 60804.9, 61207.55, 61607.75, 62012.68, 62416.27,
+		62820.35, 63221.04, 63628.76, 64030.42, 64435.8, 64840.25, 65243.91, 65647.29,
+		66051.82, 66458.67, 66863.96, 67270.71, 67676.41, 68080.77, 68485.22, 68889.77,
+		69297.13, 69703.54, 70108.79, 70516.51, 70923.14, 71331.33, 71737.55, 72142.78,
+		72551.34, 72955.97, 73363.88, 73772.07, 74177.92, 74586.14, 74992.99, 75400.51,
+		75808.16, 76214.95, 76623.3, 77031.35, 77440.13, 77849.36, 78256.92, 78666.41,
+		79073.56, 79484.19, 79893.04, 80301.51, 80708.76, 81117.32, 81529.8, 81938.19,
+	},
+	{
+		24030.86, 24431.71, 24837.16, 25247.31, 25662.7, 26082.08, 26506.62, 26935.95,
+		27369.34, 27808.28, 28251.86, 28699.72, 29151.99, 29610.3, 30073.4, 30540.51,
+		31012.27, 31489.05, 31970.91, 32457.85, 32949.09, 33443.78, 33942.98, 34446.81,
+		34955.55, 35469.5, 35987.91, 36510.36, 37037.54, 37570.95, 38106.29, 38646.68,
+		39190.94, 39740.01, 40293.13, 40851.15, 41414.01, 41981.3, 42551.13, 43126.27,
+		43705.94, 44288.43, 44874.65, 45466.73, 46064.02, 46664.5, 47267.46, 47874.62,
+		48486.89, 49103.02, 49722.78, 50345.54, 50971.85, 51601.81, 52236.93, 52873.81,
+		53515.17, 54161.04, 54809.49, 55462.51, 56118.83, 56777.1, 57440.1, 58104.29,
+		58773.22, 59446.57, 60123.14, 60801.48, 61481.07, 62166.01, 62855.85, 63543.09,
+		64237.01, 64933.74, 65631.21, 66334.48, 67040.07, 67744.65, 68455.87, 69171.74,
+		69891.87, 70608.88, 71327.67, 72047.94, 72772.95, 73497.61, 74225.05, 74955.62,
+		75690.52, 76426.45, 77165.84, 77903.29, 78644.4, 79392.32, 80140.44, 80891.87,
+		81640.52, 82393.44, 83142.53, 83897.51, 84654.57, 85410.85, 86171.62, 86933.92,
+		87695.23, 88462.3, 89228.9, 89998.06, 90767.86, 91542.39, 92314.19, 93085.69,
+		93862.6, 94640.82, 95420.93, 96200.72, 96981.22, 97762.84, 98542.18, 99327.46,
+		100113.9, 100901.6, 101686.7, 102472.7, 103262.9, 104057.1, 104843, 105635.7,
+		106427.4, 107218.8, 108012.3, 108806.4, 109603.9, 110400.1, 111197.8, 111995.4,
+		112791.5, 113592.1, 114392.2, 115196.2, 116000, 116798.2, 117600.8, 118402,
+		119207, 120008.7, 120812.4, 121615.8, 122422.4, 123227.4, 124033.8, 124842.1,
+		125648, 126458.3, 127268.5, 128076.3, 128883.4, 129698.4, 130510.4, 131320.3,
+		132122.9, 132934.6, 133744.8, 134554.7, 135367.2, 136180, 136988.8, 137797.3,
+		138606.2, 139418.7, 140236.6, 141044.5, 141856.8, 142670.4, 143485.8, 144298.3,
+		145111.3, 145929.2, 146741, 147554.5, 148368.5, 149184.2, 149995.6, 150809,
+		151619.9, 152435.4, 153253.2, 154071.6, 154885.9, 155704.8, 156519.4, 157337.7,
+		158151.4, 158968.6, 159787.1, 160601.2, 161413.9, 162236, 163051.9, 163871,
+	},
+	{
+		48062.8, 48864.38, 49676.11, 50496.29, 51326.89, 52166.19, 53014.61, 53874.64,
+		54742.34, 55620.75, 56507.42, 57402.26, 58309.15, 59224.65, 60149.48, 61083.02,
+		62025.97, 62980.05, 63942.13, 64914.75, 65895.85, 66886.32, 67885.59, 68894.46,
+		69912.78, 70939.18, 71974.36, 73020.68, 74074.03, 75136.55, 76205.53, 77286.05,
+		78377.77, 79476.34, 80582.47, 81697.61, 82826.76, 83958.88, 85102.15, 86253.2,
+		87413.67, 88579.21, 89754.6, 90936.1, 92127.79, 93327.26, 94532.84, 95748.09,
+		96969.88, 98201.44, 99443.01, 100689.2, 101945.6, 103205.8, 104476.6, 105754.1,
+		107033.2, 108319.7, 109617.6, 110922, 112234.8, 113552.4, 114879.8, 116209.8,
+		117553, 118896.2, 120245.2, 121607.2, 122967.9, 124336.5, 125709.4, 127088.8,
+		128472.9, 129863.9, 131264.2, 132671.1, 134079.8, 135492.5, 136916.5, 138341.1,
+		139770.6, 141205.1, 142645.9, 144093.5, 145548.4, 147000.7, 148459.5, 149924,
+		151390, 152860.8, 154332.4, 155816.7, 157302.4, 158793.9, 160278.2, 161771.6,
+		163268.2, 164778.4, 166282.2, 167797.1, 169315.7, 170828.8, 172348.5, 173876.9,
+		175407.5, 176938.2, 178477.3, 180016, 181554.3, 183101.4, 184641.4, 186194.8,
+		187749.8, 189304.1, 190856.9, 192409.3, 193969.9, 195539.1, 197099.6, 198670.3,
+		200235.7, 201808.4, 203382.9, 204960.5, 206534.7, 208109.2, 209695.9, 211272.2,
+		212861.4, 214445.8, 216032.6, 217624.6, 219219, 220814.6, 222411, 224006.4,
+		225600.8, 227199.8, 228795.1, 230398.7, 232001.1, 233604.5, 235207.5, 236809.8,
+		238409.6, 240012.4, 241615.8, 243226.2, 244836.6, 246448.8, 248057.8, 249671.4,
+		251286.9, 252902.7, 254514, 256127.2, 257748.8, 259361, 260985.7, 262600.2,
+		264219.6, 265836.4, 267452, 269079.7, 270700.2, 272324.7, 273954.7, 275583.5,
+		277209.5, 278839.7, 280463.2, 282093.5, 283716.3, 285349.4, 286983.4, 288622.2,
+		290248.3, 291872.9, 293497.6, 295130.3, 296754.2, 298395, 300026.1, 301645.5,
+		303282.1, 304908.3, 306533.8, 308156, 309793.3, 311423.5, 313049.9, 314698.9,
+		316324.2, 317966.1, 319594.5, 321236.8, 322867.4, 324492.8, 326118.1, 327758.1,
+	},
+	{
+		96125.99, 97730.55, 99352.81, 100994.2, 102655.5, 104335, 106033.2, 107750.4,
+		109488.2, 111243, 113016.9, 114810.4, 116624.8, 118456, 120303.8, 122174.3,
+		124060.1, 125966.5, 127891, 129834.9, 131795.3, 133773.2, 135771.1, 137791.6,
+		139824.6, 141878, 143953.2, 146044.3, 148152.6, 150276.9, 152417.7, 154578.4,
+		156755.5, 158952.3, 161164.1, 163399.6, 165649.7, 167917.6, 170202.1, 172506.1,
+		174823.9, 177158.8, 179511.8, 181878.2, 184262.7, 186657.8, 189071.9, 191502.3,
+		193951.3, 196410.8, 198890.2, 201384.2, 203892.1, 206417.5, 208955.1, 211511.2,
+		214075, 216650.8, 219234.7, 221842.8, 224460.9, 227088.9, 229736.2, 232395.1,
+		235064.7, 237756, 240451.8, 243170.1, 245891, 248636.7, 251389.1, 254148.7,
+		256914.9, 259699.9, 262501.1, 265307.6, 268122.7, 270949.1, 273788.6, 276626,
+		279487.4, 282361.1, 285242, 288130.1, 291023.1, 293940.9, 296865.2, 299805.2,
+		302743.2, 305687.9, 308639.2, 311613.5, 314589.6, 317556, 320519.8, 323516.3,
+		326518.4, 329530.6, 332551, 335572.4, 338596, 341639.2, 344695.6, 347735,
+		350800.7, 353870.4, 356936.7, 360009.2, 363077.9, 366166, 369259.8, 372353.8,
+		375444.4, 378544.6, 381633.5, 384739.8, 387865.6, 391002.9, 394138.5, 397260.1,
+		400405.1, 403545.6, 406698.9, 409841.2, 412998.2, 416145.8, 419308.9, 422457.2,
+		425613.4, 428786.2, 431967.7, 435127.2, 438316.7, 441498, 444691.6, 447870.4,
+		451073.5, 454260.5, 457455, 460663.6, 463878, 467090.9, 470301.5, 473507.7,
+		476720.7, 479960.8, 483169.6, 486412.7, 489626.5, 492858.5, 496087.6, 499325.5,
+		502542.2, 505781, 509022.8, 512249.3, 515492.1, 518733.9, 521953.5, 525200.2,
+		528435.1, 531688.5, 534948.8, 538212.9, 541453.2, 544693.8, 547932.6, 551172.5,
+		554413.8, 557661.4, 560931.8, 564162.6, 567419.3, 570652.7, 573891.1, 577159.3,
+		580414.9, 583666.6, 586934.2, 590191.4, 593436.9, 596692.5, 599961.4, 603229.1,
+		606482.8, 609739.1, 613003.2, 616252.7, 619496.7, 622760.2, 626019, 629251.8,
+		632500.6, 635772.7, 639026.5, 642302.5, 645578.5, 648839, 652120.5, 655373.1,
+	},
+	{
+		192254.9, 195462.4, 198707.9, 201989.2, 205311.3, 208670.7, 212067.8, 215503,
+		218973.4, 222483.4, 226028.1, 229613.1, 233240.7, 236898.5, 240596.7, 244335.6,
+		248113.2, 251923.4, 255776.6, 259665.3, 263585.9, 267547.2, 271540.7, 275573.8,
+		279644, 283758.9, 287897.5, 292080.2, 296301, 300562.7, 304857.5, 309191.9,
+		313552.5, 317939.7, 322370.6, 326837.3, 331340.9, 335888.3, 340456.2, 345045.1,
+		349675.9, 354335.7, 359039.8, 363778.8, 368551.9, 373353.2, 378175.7, 383034,
+		387928.6, 392853.3, 397827, 402811.9, 407844, 412885.8, 417972.4, 423080.7,
+		428211.1, 433370.7, 438549.5, 443764.4, 449011.7, 454294.3, 459592.4, 464916.3,
+		470264.1, 475655.4, 481053.4, 486471.5, 491919.4, 497413.2, 502927, 508437.3,
+		513982.3, 519551.7, 525140.3, 530768.5, 536401.6, 542055.3, 547725.3, 553448.5,
+		559172.6, 564913.1, 570665.3, 576453.8, 582268.4, 588108.8, 593943.1, 599801.7,
+		605672, 611559.8, 617465.5, 623377.1, 629296.1, 635242, 641235.4, 647205.1,
+		653192.3, 659223, 665265.4, 671328, 677412.3, 683467.1, 689568.1, 695687.5,
+		701795.7, 707913.2, 714048.2, 720176.7, 726323.9, 732536.8, 738733.9, 744912.4,
+		751116.9, 757330.1, 763552.4, 769776.4, 776006.1, 782254.5, 788508.5, 794813.5,
+		801114.4, 807408.4, 813717.3, 819990.9, 826312.4, 832607.8, 838953.7, 845277.6,
+		851666.9, 858015.7, 864364.7, 870747.4, 877095, 883496, 889912.6, 896314.5,
+		902703.9, 909044.1, 915447.8, 921856.4, 928254.3, 934677.5, 941146.9, 947573.8,
+		953984.8, 960406.1, 966802.2, 973262.5, 979706.5, 986158, 992603.5, 999087.6,
+		1005567, 1012057, 1018531, 1024979, 1031438, 1037855, 1044345, 1050839,
+		1057331, 1063779, 1070237, 1076764, 1083247, 1089715, 1096224, 1102693,
+		1109192, 1115703, 1122193, 1128732, 1135227, 1141696, 1148202, 1154696,
+		1161218, 1167703, 1174235, 1180778, 1187353, 1193894, 1200419, 1206970,
+		1213509, 1220031, 1226599, 1233043, 1239574, 1246121, 1252664, 1259151,
+		1265687, 1272188, 1278746, 1285310, 1291878, 1298395, 1304944, 1311483,
+	},
+}
+
+// biasData Mean bias of the raw estimates in rawEstimateData
+var biasData = [15][]float64{
+	{
+		10.23773, 9.722638, 9.222993, 8.739136, 8.271476, 7.818927, 7.382399, 6.961286,
+		6.556185, 6.16689, 5.792937, 5.434978, 5.093539, 4.766509, 4.454752, 4.15717,
+		3.874582, 3.606529, 3.352819, 3.111234, 2.884453, 2.669281, 2.467657, 2.278893,
+		2.099311, 1.933705, 1.778329, 1.632697, 1.497198, 1.370906, 1.251469, 1.142705,
+		1.043883, 0.9503448, 0.8672786, 0.7876869, 0.7160038, 0.6480652, 0.5849768, 0.529663,
+		0.474451, 0.4276272, 0.3851325, 0.3465936, 0.3098177, 0.27866, 0.2454824, 0.2205522,
+		0.1982239, 0.1777089, 0.1583958, 0.1384306, 0.1247375, 0.1103122, 0.0994574, 0.08870637,
+		0.07610886, 0.06958009, 0.05878083, 0.04980024, 0.04376011, 0.03543037, 0.02697861, 0.01761545,
+		0.01643237, 0.006172536, 0.00502525, -0.002146759, -0.00272498, -0.005124803, -0.003229154, -0.004500282,
+		-0.004346287, -0.006966677, -0.01052831, -0.01360169, -0.01503809, -0.01448223, -0.01902154, -0.02147317,
+	},
+	{
+		21.77924, 21.26173, 20.75177, 20.2491, 19.75398, 19.26658, 18.78673, 18.3142,
+		17.84948, 17.3917, 16.94217, 16.50011, 16.06453, 15.6375, 15.21844, 14.80693,
+		14.40116, 14.00265, 13.61322, 13.23035, 12.85575, 12.48865, 12.12877, 11.77476,
+		11.42881, 11.09131, 10.76017, 10.4355, 10.11847, 9.806895, 9.502893, 9.206659,
+		8.916699, 8.634329, 8.358994, 8.088035, 7.825199, 7.568389, 7.318969, 7.077303,
+		6.841913, 6.612696, 6.387452, 6.165573, 5.953045, 5.744653, 5.54246, 5.346946,
+		5.156796, 4.970818, 4.788916, 4.614084, 4.444225, 4.278876, 4.120333, 3.966297,
+		3.816453, 3.67262, 3.530519, 3.391243, 3.257537, 3.128752, 3.005816, 2.890122,
+		2.774499, 2.663137, 2.554087, 2.45187, 2.34894, 2.252419, 2.158001, 2.066437,
+		1.979824, 1.892457, 1.809291, 1.729955, 1.653881, 1.580495, 1.513891, 1.448765,
+		1.386488, 1.322322, 1.264099, 1.211461, 1.156654, 1.103244, 1.051162, 1.005152,
+		0.9568089, 0.9083819, 0.8692548, 0.8266509, 0.7872328, 0.750501, 0.7124804, 0.680016,
+		0.6486196, 0.6163974, 0.585064, 0.5595404, 0.529522, 0.5046798, 0.4814729, 0.4556677,
+		0.4347114, 0.4115511, 0.3910957, 0.3689574, 0.351385, 0.3361401, 0.3202056, 0.3043913,
+		0.2878521, 0.2664296, 0.2529825, 0.2419676, 0.231964, 0.2180068, 0.2094016, 0.1992916,
+		0.1872511, 0.174964, 0.1659181, 0.1563155, 0.1433403, 0.1327331, 0.1235969, 0.1211548,
+		0.1179418, 0.1106043, 0.09985143, 0.09247081, 0.0872034, 0.08477638, 0.07273877, 0.06681982,
+		0.06638447, 0.06675688, 0.06133315, 0.05693205, 0.0563071, 0.05390216, 0.05442073, 0.05127908,
+		0.05016897, 0.04316839, 0.04008962, 0.04106301, 0.03858482, 0.03717455, 0.03342944, 0.0324365,
+		0.02869406, 0.02585365, 0.02250293, 0.01609735, 0.01216149, 0.005602526, 0.000781119, -0.0005000981,
+	},
+	{
+		44.85414, 43.82132, 43.31062, 42.29904, 41.3031, 40.80949, 39.83491, 39.35282,
+		38.40012, 37.46102, 36.99724, 36.08383, 35.63206, 34.7379, 33.85894, 33.42524,
+		32.56975, 32.14717, 31.31248, 30.49262, 30.08775, 29.28872, 28.8962, 28.11987,
+		27.35831, 26.98264, 26.24062, 25.87653, 25.15766, 24.4533, 24.1055, 23.42125,
+		23.08508, 22.42236, 21.77107, 21.45051, 20.81759, 20.50977, 19.89691, 19.30116,
+		19.00777, 18.432, 18.14971, 17.5929, 17.04636, 16.77828, 16.2505, 15.99209,
+		15.48823, 14.99323, 14.75406, 14.28304, 14.05155, 13.59549, 13.14748, 12.9287,
+		12.49692, 12.28879, 11.87572, 11.47237, 11.27578, 10.89323, 10.70443, 10.33482,
+		9.973737, 9.79887, 9.449517, 9.283315, 8.955371, 8.634832, 8.48001, 8.169966,
+		8.019244, 7.728758, 7.438152, 7.299732, 7.025858, 6.895891, 6.637764, 6.391372,
+		6.266563, 6.023255, 5.907661, 5.676234, 5.453219, 5.350071, 5.134387, 5.035174,
+		4.843635, 4.651986, 4.556599, 4.373966, 4.287217, 4.109587, 3.939893, 3.859868,
+		3.692775, 3.612261, 3.461329, 3.315162, 3.24827, 3.106486, 3.036203, 2.905094,
+		2.783183, 2.717554, 2.593949, 2.536574, 2.424003, 2.309109, 2.25203, 2.152838,
+		2.101984, 2.006222, 1.907812, 1.867109, 1.778103, 1.730848, 1.644195, 1.568263,
+		1.528965, 1.458352, 1.422878, 1.347206, 1.285313, 1.255638, 1.19342, 1.167927,
+		1.106461, 1.059073, 1.035513, 0.9798193, 0.9575244, 0.9095603, 0.8532725, 0.8365962,
+		0.7835859, 0.7701266, 0.7273538, 0.6856946, 0.6672832, 0.6343196, 0.6180986, 0.5817797,
+		0.5565994, 0.5439448, 0.5077224, 0.5006235, 0.4711836, 0.4392326, 0.4245892, 0.4045795,
+		0.3902003, 0.3576312, 0.3354198, 0.3339078, 0.3304608, 0.3125929, 0.2944979, 0.2910065,
+		0.2852234, 0.2672919, 0.2574478, 0.2304803, 0.2033744, 0.1967752, 0.1776768, 0.1710014,
+		0.1585963, 0.1463267, 0.1255138, 0.1167844, 0.1096312, 0.101687, 0.09124256, 0.08188636,
+		0.06453078, 0.0538827, 0.04907186, 0.04667385, 0.04186752, 0.01866616, 0.01491804, 0.003587306,
+		-0.01481292, -0.01629522, -0.02621521, -0.02560113, -0.03234603, -0.04163784, -0.05157843, -0.05474187,
+		-0.05802841, -0.0542566, -0.07137518, -0.07587428, -0.0880266, -0.08934163, -0.09909548, -0.1142811,
+	},
+	{
+		89.9981, 88.45897, 86.93572, 85.42904, 83.4472, 81.97865, 80.52607, 79.0882,
+		77.66833, 75.80244, 74.42183, 73.05719, 71.70938, 70.37676, 68.628, 67.33264,
+		66.05312, 64.79094, 63.54696, 61.91417, 60.70629, 59.5177, 58.34175, 57.18321,
+		55.66374, 54.54151, 53.43767, 52.34289, 51.26704, 49.85392, 48.81368, 47.79629,
+		46.78733, 45.79778, 44.49375, 43.54016, 42.59753, 41.66642, 40.75629, 39.57098,
+		38.69282, 37.82524, 36.97385, 36.14028, 35.05241, 34.24607, 33.46364, 32.69639,
+		31.93721, 30.94733, 30.2214, 29.50339, 28.80204, 28.11466, 27.21387, 26.56214,
+		25.91367, 25.27631, 24.65723, 23.85438, 23.25606, 22.67254, 22.1078, 21.54398,
+		20.82497, 20.29608, 19.78335, 19.27663, 18.77031, 18.12629, 17.6587, 17.19327,
+		16.73706, 16.28383, 15.71027, 15.27939, 14.86779, 14.46507, 14.07351, 13.56864,
+		13.19857, 12.83552, 12.47143, 12.12735, 11.67539, 11.33739, 11.00883, 10.69924,
+		10.38537, 9.982553, 9.687806, 9.401306, 9.137708, 8.874399, 8.528092, 8.277721,
+		8.041774, 7.798326, 7.564666, 7.261738, 7.03912, 6.842083, 6.63853, 6.443992,
+		6.168412, 5.970156, 5.768831, 5.570499, 5.395856, 5.184213, 5.030597, 4.868411,
+		4.727539, 4.57621, 4.384638, 4.245725, 4.099208, 3.951601, 3.816815, 3.642017,
+		3.510507, 3.379659, 3.245952, 3.147453, 3.005443, 2.919041, 2.825451, 2.72154,
+		2.638287, 2.51277, 2.432876, 2.348153, 2.271729, 2.184469, 2.079753, 1.999521,
+		1.941481, 1.851835, 1.795319, 1.689607, 1.637167, 1.576927, 1.517172, 1.478428,
+		1.390828, 1.330264, 1.274842, 1.235357, 1.190267, 1.127357, 1.076446, 1.048521,
+		0.9961603, 0.9685203, 0.9251094, 0.8955653, 0.873925, 0.8455863, 0.7906488, 0.7784245,
+		0.7485701, 0.7274766, 0.6987788, 0.6654448, 0.6323243, 0.6091472, 0.5967991, 0.5604523,
+		0.5428716, 0.4995211, 0.4724003, 0.4682063, 0.4268218, 0.405534, 0.4037494, 0.3659269,
+		0.3616958, 0.3378747, 0.3241893, 0.3049751, 0.298322, 0.258904, 0.2377909, 0.2075676,
+		0.2002799, 0.1989545, 0.1853236, 0.1710462, 0.1820789, 0.1922886, 0.1894678, 0.158728,
+		0.1577934, 0.1484658, 0.1342364, 0.1055717, 0.08148898, 0.06986846, 0.07336962, 0.09668777,
+	},
+	{
+		180.7731, 177.6994, 174.1576, 171.1543, 167.692, 164.7604, 161.859, 158.5193,
+		155.6903, 152.4287, 149.671, 146.9454, 143.8051, 141.1541, 138.1039, 135.5195,
+		132.965, 130.03, 127.549, 124.6952, 122.2781, 119.9034, 117.1667, 114.8544,
+		112.198, 109.9556, 107.7494, 105.2088, 103.0711, 100.613, 98.54375, 96.50005,
+		94.16025, 92.18463, 89.92103, 88.01275, 86.12762, 83.97481, 82.15274, 80.05404,
+		78.29407, 76.57196, 74.5945, 72.93374, 71.0207, 69.42591, 67.86106, 66.05527,
+		64.53978, 62.80436, 61.32687, 59.89183, 58.23918, 56.85612, 55.27962, 53.95281,
+		52.65149, 51.18327, 49.93134, 48.50884, 47.32346, 46.17424, 44.82499, 43.70382,
+		42.41519, 41.33502, 40.29271, 39.10373, 38.10296, 36.95073, 36.01418, 35.07176,
+		34.0119, 33.12693, 32.09256, 31.22235, 30.40633, 29.46204, 28.68106, 27.78131,
+		27.0287, 26.28299, 25.44651, 24.76021, 23.96847, 23.2893, 22.65055, 21.92558,
+		21.30882, 20.62216, 20.04981, 19.47593, 18.84571, 18.30273, 17.71308, 17.19955,
+		16.69456, 16.15355, 15.67481, 15.12761, 14.66843, 14.2316, 13.76539, 13.38186,
+		12.8885, 12.51579, 12.15804, 11.72007, 11.37089, 10.98248, 10.64733, 10.31365,
+		9.931105, 9.647436, 9.31241, 9.029277, 8.75069, 8.419035, 8.167832, 7.848145,
+		7.575188, 7.350843, 7.080424, 6.81079, 6.568689, 6.377299, 6.174205, 5.964253,
+		5.772547, 5.565622, 5.367025, 5.22494, 5.036847, 4.879487, 4.69163, 4.538481,
+		4.399661, 4.229928, 4.100892, 3.981727, 3.858228, 3.723083, 3.629384, 3.542582,
+		3.456107, 3.347741, 3.254811, 3.136793, 3.03757, 2.963092, 2.87632, 2.791938,
+		2.688559, 2.631973, 2.55494, 2.451818, 2.363267, 2.27589, 2.215925, 2.144497,
+		2.035946, 1.986136, 1.923736, 1.872179, 1.808113, 1.735531, 1.682819, 1.614258,
+		1.569826, 1.499764, 1.421283, 1.388631, 1.387998, 1.370814, 1.321912, 1.34979,
+		1.282442, 1.238339, 1.202232, 1.214793, 1.203995, 1.16991, 1.123229, 1.133917,
+		1.078434, 1.051761, 1.040462, 0.9907414, 0.9641317, 0.9357976, 0.9333014, 0.8967633,
+		0.8868866, 0.8311686, 0.7788037, 0.7725338, 0.7622267, 0.7180164, 0.7322368, 0.6954706,
+	},
+	{
+		362.3251, 355.678, 349.1045, 342.6104, 336.1881, 330.3337, 324.0597, 317.8679,
+		311.7491, 305.7072, 300.1976, 294.3041, 288.4772, 282.7162, 277.0495, 271.8759,
+		266.3626, 260.9265, 255.5659, 250.2698, 245.4442, 240.2946, 235.2103, 230.2025,
+		225.2741, 220.7873, 216.0108, 211.3033, 206.6597, 202.0876, 197.9354, 193.4999,
+		189.1191, 184.8239, 180.6048, 176.7725, 172.6763, 168.6777, 164.7307, 160.8525,
+		157.3396, 153.5999, 149.9096, 146.2983, 142.7878, 139.6116, 136.2224, 132.8591,
+		129.5852, 126.3494, 123.4143, 120.3265, 117.2789, 114.2762, 111.3414, 108.6863,
+		105.8634, 103.1416, 100.4576, 97.81456, 95.42888, 92.92418, 90.44214, 87.96326,
+		85.55503, 83.41698, 81.13872, 78.90063, 76.74289, 74.60952, 72.68581, 70.63857,
+		68.66396, 66.72663, 64.80112, 63.06782, 61.21721, 59.46373, 57.73139, 56.08305,
+		54.60171, 53.03445, 51.44832, 49.9656, 48.47896, 47.18253, 45.75045, 44.39006,
+		43.08038, 41.80439, 40.6617, 39.42123, 38.23123, 37.04011, 35.87971, 34.86926,
+		33.80586, 32.78097, 31.76234, 30.77731, 29.83747, 28.9352, 27.98053, 27.09032,
+		26.19058, 25.42066, 24.65412, 23.86647, 23.07115, 22.30483, 21.64479, 20.94488,
+		20.23482, 19.5165, 18.84069, 18.27436, 17.65953, 17.05351, 16.50534, 15.90453,
+		15.47124, 14.9049, 14.34601, 13.91254, 13.35883, 13.02126, 12.54513, 12.11026,
+		11.63442, 11.21537, 10.87084, 10.46071, 10.05508, 9.683905, 9.282428, 8.96445,
+		8.633222, 8.391626, 8.116276, 7.856887, 7.607972, 7.312365, 7.058996, 6.837253,
+		6.606917, 6.342553, 6.098857, 5.847095, 5.581049, 5.431297, 5.23465, 5.01308,
+		4.886568, 4.747723, 4.494654, 4.372589, 4.244249, 4.110744, 3.957287, 3.775439,
+		3.584898, 3.450777, 3.274969, 3.084715, 2.962107, 2.829727, 2.75619, 2.603532,
+		2.352201, 2.215311, 2.081531, 2.021077, 1.915127, 1.788422, 1.732689, 1.742877,
+		1.810581, 1.771136, 1.734502, 1.6735, 1.559541, 1.48725, 1.443134, 1.341287,
+		1.282816, 1.248961, 1.242921, 1.257533, 1.092209, 1.044077, 1.029526, 0.8575733,
+		0.8698167, 0.8139265, 0.8629714, 0.775743, 0.6612968, 0.6090945, 0.6666937, 0.6095137,
+	},
+	{
+		724.923, 711.6358, 698.9979, 686.0116, 673.175, 660.9893, 648.463, 636.5604,
+		624.3154, 612.22, 600.734, 588.9698, 577.7704, 566.2706, 554.9523, 544.1927,
+		533.1469, 522.661, 511.9309, 501.3413, 491.3189, 481.0192, 471.2585, 461.2607,
+		451.4014, 442.0695, 432.4943, 423.4407, 414.1886, 405.0622, 396.4238, 387.5513,
+		379.215, 370.608, 362.1367, 354.1097, 345.9711, 338.2591, 330.3508, 322.6228,
+		315.2878, 307.8049, 300.7424, 293.5356, 286.4736, 279.7447, 272.9194, 266.4545,
+		259.8255, 253.3641, 247.307, 241.1225, 235.2374, 229.2824, 223.4386, 217.8979,
+		212.2797, 206.9694, 201.5692, 196.241, 191.2398, 186.2725, 181.4748, 176.6753,
+		171.8552, 167.3425, 162.7755, 158.5731, 154.2106, 149.9978, 145.8935, 141.7958,
+		137.8834, 133.8554, 129.9799, 126.3685, 122.771, 119.3793, 115.8866, 112.54,
+		109.2989, 106.0382, 103.1204, 99.96527, 97.04002, 94.14513, 91.38227, 88.72568,
+		86.11154, 83.50704, 81.08686, 78.56386, 76.32309, 73.9581, 71.71095, 69.57279,
+		67.44394, 65.33109, 63.23223, 61.1523, 59.18074, 57.2724, 55.53239, 53.71162,
+		51.95642, 50.2446, 48.66002, 47.02998, 45.53613, 44.16142, 42.70086, 41.3219,
+		39.90556, 38.59393, 37.26279, 35.91751, 34.67135, 33.48812, 32.37505, 31.06909,
+		29.98679, 29.00771, 28.12425, 27.01535, 26.0124, 25.18553, 24.33881, 23.62138,
+		22.75404, 21.99545, 21.15442, 20.29058, 19.64418, 18.87826, 18.26092, 17.62177,
+		17.01145, 16.34454, 15.66616, 15.07926, 14.54703, 14.09102, 13.7206, 13.16212,
+		12.48345, 12.03958, 11.48209, 10.91242, 10.45589, 9.974931, 9.700282, 9.434856,
+		8.991401, 8.564043, 8.109542, 7.876995, 7.603321, 7.340546, 7.13388, 7.008591,
+		6.587772, 6.286955, 6.022822, 5.766237, 5.57942, 5.274343, 5.075212, 4.858865,
+		4.641536, 4.385694, 4.095797, 4.055634, 3.756564, 3.556131, 3.432534, 3.402328,
+		3.164019, 2.929079, 2.882194, 2.847894, 2.892479, 2.733258, 2.621849, 2.53778,
+		2.399806, 2.045938, 1.974754, 1.817547, 1.666094, 1.609568, 1.429633, 1.304888,
+		1.145271, 1.110474, 0.6285525, 0.5099966, 0.5850162, 0.5550971, 0.3943947, 0.2966259,
+	},
+	{
+		1450.116, 1424.058, 1398.299, 1372.835, 1347.191, 1322.315, 1297.739, 1273.476,
+		1249.477, 1225.299, 1201.863, 1178.777, 1155.936, 1133.414, 1110.752, 1088.803,
+		1067.166, 1045.793, 1024.749, 1003.573, 983.0735, 962.8662, 942.9871, 923.377,
+		903.6247, 884.6323, 865.8515, 847.4189, 829.2103, 810.9529, 793.3225, 776.0004,
+		758.9263, 742.119, 725.2214, 708.8937, 692.8726, 677.2794, 661.8293, 646.3417,
+		631.4205, 616.8105, 602.4697, 588.3678, 574.152, 560.4502, 547.1539, 533.9668,
+		520.9889, 508.0684, 495.822, 483.5615, 471.5086, 459.7484, 447.9805, 436.6396,
+		425.502, 414.7607, 403.9951, 393.3729, 383.1002, 373.1496, 363.3697, 353.7939,
+		344.2871, 335.0996, 326.233, 317.3992, 308.8904, 300.2895, 292.1275, 283.9756,
+		276.1086, 268.5903, 260.8414, 253.6719, 246.5197, 239.6012, 232.9264, 226.1958,
+		219.7507, 213.5782, 207.4418, 201.3776, 195.4033, 189.5788, 184.1021, 178.6835,
+		173.4756, 168.0885, 162.9359, 158.0161, 153.3934, 148.6344, 144.021, 139.5386,
+		135.3177, 131.1262, 127.1161, 123.0428, 119.1429, 115.5172, 111.8434, 108.4167,
+		104.8776, 101.4773, 98.01736, 95.09309, 91.98535, 88.83776, 86.04594, 83.203,
+		80.52324, 77.91719, 75.26788, 72.66813, 70.52332, 68.11899, 65.72427, 63.23935,
+		60.88622, 58.56901, 56.50556, 54.49184, 52.70372, 50.68308, 49.09268, 47.45042,
+		45.51114, 43.83771, 42.42769, 41.12631, 39.69865, 38.28338, 36.81216, 35.6657,
+		34.25042, 32.98844, 31.85038, 30.82776, 29.58426, 28.47353, 27.6754, 26.51253,
+		25.55393, 24.78699, 23.89989, 23.05595, 22.11889, 21.49286, 20.78011, 20.03023,
+		19.32475, 18.24881, 17.44196, 16.87691, 16.31428, 15.46615, 14.72937, 14.10281,
+		13.36657, 13.15452, 12.41002, 12.05969, 11.34411, 10.85368, 10.19297, 9.963989,
+		9.945514, 9.528559, 9.07415, 8.61323, 8.247756, 7.619526, 7.15715, 6.583738,
+		6.331035, 5.967634, 6.008767, 5.805418, 5.799862, 5.352913, 4.805292, 4.408075,
+		3.81696, 3.503152, 3.286728, 2.760075, 2.60275, 2.368976, 2.121329, 1.967188,
+		1.501806, 1.375308, 1.391898, 1.223999, 1.147107, 0.7047224, 0.583081, 0.5167652,
+	},
+	{
+		2900.993, 2848.92, 2796.933, 2745.99, 2695.114, 2645.36, 2596.174, 2547.135,
+		2499.228, 2451.368, 2404.604, 2358.446, 2312.347, 2267.302, 2222.528, 2178.642,
+		2135.368, 2092.237, 2050.197, 2008.321, 1967.307, 1926.937, 1886.687, 1847.483,
+		1808.521, 1770.43, 1732.938, 1695.719, 1659.412, 1623.193, 1587.908, 1553.12,
+		1518.685, 1485.009, 1451.628, 1418.978, 1386.91, 1355.233, 1324.331, 1293.462,
+		1263.632, 1234.411, 1205.332, 1176.965, 1148.84, 1121.634, 1094.942, 1068.154,
+		1042.453, 1016.971, 992.1278, 967.905, 944.1321, 920.7124, 897.4134, 874.8744,
+		852.831, 830.9316, 809.6539, 788.9435, 768.6032, 748.9402, 729.427, 710.5419,
+		691.8069, 673.2735, 655.224, 637.4379, 620.491, 603.553, 587.3215, 571.413,
+		555.7762, 540.341, 525.0502, 510.5411, 496.0858, 481.7393, 468.1168, 454.7796,
+		442.0377, 428.9834, 416.6489, 404.9892, 393.4517, 382.241, 370.5185, 359.1442,
+		348.5558, 338.2213, 327.9143, 318.062, 308.5149, 299.6103, 290.6943, 281.7436,
+		273.5839, 265.1909, 257.3782, 249.6002, 241.9421, 234.3154, 227.1954, 220.4075,
+		213.0312, 206.5578, 200.0011, 193.8976, 187.3744, 181.1026, 175.1534, 169.1959,
+		163.7287, 158.6668, 153.4034, 149.0638, 144.6046, 139.7501, 135.0312, 130.7879,
+		126.6939, 122.5626, 118.734, 114.5303, 110.9401, 107.4508, 103.7909, 100.1282,
+		96.79437, 93.80291, 91.25722, 88.34465, 85.25411, 82.11742, 78.99541, 76.48784,
+		73.67709, 71.21764, 68.91664, 67.1251, 65.16386, 63.06189, 60.86017, 58.255,
+		56.25786, 54.39853, 52.88961, 51.48561, 50.16746, 48.58605, 47.06356, 45.40818,
+		43.59835, 42.25447, 40.86224, 39.99478, 38.57651, 37.03639, 35.90625, 34.2355,
+		32.37643, 31.19955, 30.18017, 29.42378, 28.54655, 27.92278, 27.13157, 26.5343,
+		26.07038, 25.39038, 24.63923, 23.46557, 22.84675, 21.67035, 21.45357, 20.40003,
+		19.99386, 19.35865, 19.12461, 18.10096, 17.41936, 16.8678, 16.35366, 16.33535,
+		15.92949, 16.04867, 14.60885, 14.7099, 13.75387, 13.01992, 12.61125, 11.88721,
+		11.83495, 10.74145, 9.88465, 10.7706, 10.84936, 10.4811, 10.75722, 11.10296,
+	},
+	{
+		5802.834, 5698.157, 5594.62, 5492.286, 5391.261, 5291.774, 5192.962, 5095.373,
+		4998.908, 4903.783, 4810.207, 4717.497, 4625.863, 4535.244, 4446, 4358.341,
+		4271.453, 4185.662, 4101.162, 4017.928, 3936.064, 3854.856, 3774.877, 3695.895,
+		3618.108, 3542.286, 3466.976, 3392.655, 3319.595, 3247.607, 3176.645, 3107.16,
+		3038.471, 2970.824, 2904.335, 2839.275, 2775.205, 2711.836, 2649.602, 2588.619,
+		2528.932, 2470.022, 2411.936, 2354.729, 2298.659, 2243.892, 2190.041, 2137.154,
+		2085.112, 2034.071, 1984.477, 1935.625, 1887.921, 1841.182, 1795.1, 1749.85,
+		1705.494, 1662.372, 1619.77, 1577.924, 1536.773, 1496.998, 1457.496, 1419.384,
+		1382.05, 1345.037, 1308.763, 1273.398, 1238.934, 1204.568, 1171.438, 1138.762,
+		1106.915, 1075.579, 1045.149, 1016.808, 988.2983, 960.6, 933.1532, 906.4752,
+		880.0181, 854.1014, 828.951, 804.492, 780.7729, 757.8298, 734.5947, 712.1754,
+		689.9658, 668.9944, 649.3335, 629.4553, 610.4922, 592.15, 573.8988, 556.7038,
+		539.4639, 522.6749, 506.2392, 489.6741, 473.1908, 458.0464, 442.6921, 428.9556,
+		415.2424, 402.0217, 388.5407, 375.5075, 363.5132, 351.8674, 341.5146, 330.7027,
+		318.6859, 307.6185, 296.7457, 287.9542, 277.7206, 267.9014, 258.916, 249.3548,
+		239.6601, 231.6776, 224.19, 216.4662, 209.1291, 201.349, 193.7471, 187.0128,
+		180.0694, 172.8047, 165.4093, 159.172, 152.3553, 146.5296, 141.9206, 136.4778,
+		130.6962, 126.4099, 122.3692, 117.6824, 113.8963, 109.9609, 105.7061, 100.9832,
+		96.42262, 93.12129, 89.42065, 84.9783, 81.14947, 79.19329, 76.3858, 73.3294,
+		70.45344, 66.73833, 64.33576, 61.16824, 58.11765, 56.15145, 53.59897, 51.18081,
+		49.40368, 48.88554, 46.00029, 43.15058, 41.90049, 40.87725, 39.25475, 37.4009,
+		35.57419, 33.60301, 31.76357, 30.48964, 30.64045, 30.45392, 30.30685, 27.34131,
+		26.59659, 24.61839, 23.20603, 22.38777, 21.64629, 20.28543, 20.26011, 18.30255,
+		16.57793, 15.68353, 13.95195, 12.20478, 12.90757, 11.75567, 10.49452, 8.035666,
+		7.421102, 5.949086, 5.857253, 6.116366, 5.810827, 4.638012, 5.235317, 5.106887,
+	},
+	{
+		11605.87, 11396.43, 11189.89, 10985.33, 10782.92, 10583.67, 10386.29, 10191.7,
+		9998.672, 9808.312, 9620.583, 9434.934, 9252.268, 9071.275, 8892.668, 8717.018,
+		8543.036, 8371.65, 8202.341, 8035.562, 7871.604, 7708.999, 7549.401, 7391.841,
+		7236.427, 7083.463, 6932.211, 6784.063, 6637.56, 6493.742, 6351.781, 6212.617,
+		6075.414, 5940.497, 5807.736, 5677.452, 5549.247, 5423.217, 5298.737, 5176.232,
+		5056.614, 4939.15, 4822.96, 4708.645, 4596.085, 4486.524, 4379.067, 4273.733,
+		4169.706, 4068.185, 3968.994, 3871.076, 3775.832, 3681.512, 3588.94, 3499.562,
+		3410.215, 3322.816, 3237.72, 3154.91, 3072.465, 2993.365, 2915.036, 2839.328,
+		2764.312, 2690.858, 2617.804, 2547.459, 2478.55, 2411.243, 2344.831, 2279.779,
+		2217.453, 2154.98, 2094.702, 2036.035, 1978.247, 1922.375, 1867.504, 1814.407,
+		1762.102, 1711.209, 1661.091, 1612.542, 1566.398, 1519.725, 1473.639, 1430.273,
+		1388.022, 1345.919, 1305.536, 1266.551, 1228.3, 1191.342, 1156.679, 1121.658,
+		1087.458, 1053.389, 1020.938, 989.6994, 958.3517, 928.4989, 899.3771, 871.9401,
+		844.5365, 817.4924, 792.9034, 765.8366, 742.5815, 716.5813, 693.4927, 670.149,
+		649.5511, 625.8031, 605.7505, 584.7087, 563.4934, 542.7818, 523.7109, 506.8601,
+		490.5782, 475.2495, 457.3468, 440.9357, 425.7413, 410.7471, 395.3331, 381.4064,
+		366.3006, 351.9389, 338.0835, 327.1405, 314.8463, 306.7456, 295.7235, 285.9175,
+		277.7625, 266.5222, 256.0014, 245.7265, 235.4023, 224.5969, 214.7438, 207.4405,
+		200.6975, 196.689, 190.1843, 184.9035, 177.553, 167.7539, 163.6803, 157.2703,
+		152.3485, 143.0424, 140.7604, 133.4233, 128.7951, 124.2545, 117.9138, 111.287,
+		106.8159, 103.6658, 99.9564, 96.70554, 92.40523, 87.77469, 82.21851, 77.77032,
+		75.12762, 71.53854, 67.78788, 65.50962, 63.13762, 61.33357, 57.54892, 53.78178,
+		52.34042, 47.9745, 45.88435, 44.07019, 40.91575, 39.13717, 36.9891, 34.5062,
+		32.16118, 29.94932, 28.29697, 27.35036, 26.13329, 25.35549, 23.92173, 23.40779,
+		21.56323, 22.18906, 21.03501, 20.50915, 17.75823, 17.32289, 19.79695, 18.18569,
+	},
+	{
+		23211.86, 22793.71, 22380.16, 21971.31, 21566.7, 21167.08, 20772.62, 20382.95,
+		19997.34, 19616.28, 19240.86, 18869.72, 18502.99, 18142.3, 17785.4, 17433.51,
+		17086.27, 16744.05, 16406.91, 16073.85, 15746.09, 15421.78, 15101.98, 14786.81,
+		14475.55, 14170.5, 13869.91, 13573.36, 13281.54, 12994.95, 12711.29, 12432.68,
+		12157.94, 11888.01, 11621.13, 11360.15, 11104.01, 10852.3, 10603.13, 10358.27,
+		10118.94, 9882.433, 9649.652, 9422.726, 9200.015, 8981.504, 8765.457, 8553.623,
+		8346.887, 8143.019, 7943.779, 7747.54, 7554.851, 7365.81, 7180.933, 6998.811,
+		6821.166, 6648.043, 6477.486, 6310.51, 6147.826, 5987.103, 5831.1, 5676.293,
+		5525.217, 5379.568, 5237.137, 5096.478, 4957.074, 4822.006, 4692.85, 4561.091,
+		4436.015, 4313.735, 4191.208, 4075.482, 3962.071, 3847.648, 3739.872, 3635.741,
+		3536.869, 3434.885, 3334.67, 3235.935, 3140.954, 3046.612, 2955.047, 2866.621,
+		2782.523, 2698.447, 2618.845, 2537.287, 2459.401, 2388.322, 2316.441, 2248.87,
+		2178.519, 2112.441, 2042.53, 1977.509, 1915.566, 1852.853, 1794.618, 1737.918,
+		1679.226, 1627.301, 1574.9, 1525.057, 1475.862, 1430.389, 1383.193, 1335.69,
+		1293.595, 1252.817, 1212.925, 1173.72, 1135.217, 1097.839, 1058.177, 1023.464,
+		990.9422, 959.6397, 925.6987, 892.7094, 862.8589, 838.1402, 805.0068, 778.6761,
+		751.4497, 722.7742, 697.3255, 672.3528, 650.9073, 628.1199, 605.8435, 584.3749,
+		561.5009, 543.1444, 524.1588, 508.1525, 492.9595, 472.201, 455.7932, 437.9721,
+		422.9645, 405.6679, 390.3631, 374.8169, 362.4422, 347.3571, 334.848, 324.1439,
+		311.0265, 302.3279, 292.5041, 281.339, 269.3836, 265.3752, 258.4219, 248.2911,
+		231.9232, 224.6284, 215.8417, 206.7452, 199.2404, 192.9526, 182.8206, 172.3292,
+		162.203, 154.656, 153.5572, 142.4792, 135.8368, 130.3604, 125.8466, 119.2837,
+		113.3338, 112.16, 104.9637, 98.54817, 93.50756, 90.17628, 82.63912, 77.03248,
+		67.89537, 64.37289, 63.18952, 62.55135, 57.93548, 56.81097, 52.42487, 51.72216,
+		46.35743, 44.60005, 43.06863, 38.22097, 31.85152, 34.9773, 31.90128, 30.95781,
+	},
+	{
+		46424.8, 45588.38, 44761.11, 43943.29, 43134.89, 42336.19, 41546.61, 40767.64,
+		39997.34, 39236.75, 38485.42, 37742.26, 37010.15, 36287.65, 35573.48, 34869.02,
+		34173.97, 33489.05, 32813.13, 32146.75, 31489.85, 30842.32, 30202.59, 29573.46,
+		28952.78, 28341.18, 27738.36, 27145.68, 26561.03, 25984.55, 25415.53, 24858.05,
+		24310.77, 23771.34, 23238.47, 22715.61, 22206.76, 21699.88, 21205.15, 20717.2,
+		20239.67, 19767.21, 19303.6, 18847.1, 18399.79, 17961.26, 17528.84, 17105.09,
+		16688.88, 16281.44, 15885.01, 15493.24, 15110.63, 14732.84, 14364.57, 14004.07,
+		13645.24, 13292.71, 12952.59, 12617.97, 12292.84, 11972.44, 11660.84, 11352.85,
+		11057.05, 10762.21, 10473.23, 10196.21, 9918.852, 9648.517, 9383.417, 9124.794,
+		8869.882, 8622.861, 8384.151, 8153.105, 7923.804, 7697.53, 7483.461, 7269.143,
+		7060.64, 6857.078, 6658.885, 6468.542, 6284.408, 6098.744, 5919.465, 5744.981,
+		5573.014, 5404.807, 5238.444, 5084.717, 4931.366, 4784.919, 4630.24, 4485.627,
+		4344.179, 4215.446, 4081.173, 3957.147, 3837.665, 3712.827, 3593.51, 3483.893,
+		3375.47, 3268.187, 3169.295, 3069.021, 2969.257, 2877.424, 2779.371, 2694.76,
+		2610.834, 2527.132, 2440.906, 2355.274, 2277.873, 2208.062, 2130.559, 2062.266,
+		1989.707, 1924.409, 1859.869, 1799.513, 1734.693, 1671.241, 1619.864, 1557.206,
+		1508.387, 1453.839, 1402.557, 1356.569, 1312.04, 1269.562, 1226.952, 1184.417,
+		1140.807, 1100.763, 1058.142, 1022.672, 987.0931, 952.5414, 916.4634, 880.8349,
+		841.5777, 806.3842, 771.7525, 743.1813, 715.5878, 688.8082, 659.8121, 635.4177,
+		611.8808, 589.7441, 562.0379, 537.1541, 520.8182, 493.9774, 480.6777, 456.1992,
+		437.5856, 416.3651, 393.0085, 382.673, 364.1734, 350.6803, 342.7173, 332.5451,
+		320.5127, 311.6601, 297.2496, 289.4874, 273.2674, 268.4187, 263.4, 264.2419,
+		252.3325, 237.9173, 224.5629, 218.3115, 204.2326, 207.0486, 199.1335, 180.5175,
+		178.1383, 166.3247, 153.8435, 136.9671, 136.2648, 127.4988, 115.9016, 126.9215,
+		113.1525, 117.0683, 106.5306, 110.8324, 103.4414, 89.80385, 77.0531, 78.06479,
+	},
+	{
+		92849.99, 91177.55, 89522.81, 87887.22, 86271.46, 84674.97, 83096.21, 81536.42,
+		79997.24, 78474.96, 76972.91, 75489.42, 74026.83, 72580.99, 71151.76, 69746.31,
+		68355.12, 66984.52, 65631.95, 64298.88, 62983.34, 61684.19, 60405.08, 59148.61,
+		57904.59, 56681.98, 55480.18, 54294.34, 53125.6, 51972.88, 50837.65, 49721.38,
+		48621.54, 47541.33, 46476.06, 45435.62, 44408.66, 43399.57, 42407.06, 41434.1,
+		40475.87, 39533.83, 38609.79, 37699.16, 36806.68, 35925.75, 35062.89, 34216.28,
+		33388.26, 32570.83, 31774.22, 30991.19, 30222.12, 29470.55, 28731.05, 28011.16,
+		27298.03, 26596.79, 25903.71, 25234.8, 24576.94, 23927.89, 23298.2, 22680.06,
+		22072.7, 21487.95, 20906.77, 20348.14, 19792.01, 19260.65, 18737.06, 18219.67,
+		17708.89, 17216.88, 16741.07, 16271.61, 15809.74, 15359.06, 14921.64, 14482,
+		14067.42, 13664.15, 13268.03, 12879.1, 12495.13, 12136.92, 11784.23, 11447.16,
+		11108.17, 10775.87, 10451.2, 10148.52, 9847.571, 9537.004, 9223.751, 8944.253,
+		8669.4, 8404.589, 8147.957, 7892.445, 7639.962, 7406.196, 7185.633, 6947.978,
+		6736.705, 6530.385, 6319.711, 6115.221, 5906.851, 5718.027, 5535.756, 5352.838,
+		5166.431, 4989.555, 4801.47, 4631.779, 4480.623, 4340.932, 4199.478, 4044.09,
+		3913.073, 3776.637, 3652.88, 3518.182, 3398.226, 3269.777, 3155.854, 3027.2,
+		2906.439, 2802.202, 2707.722, 2590.176, 2502.654, 2406.956, 2323.608, 2226.433,
+		2152.455, 2062.462, 1979.993, 1911.613, 1850.003, 1785.882, 1719.536, 1648.74,
+		1584.725, 1548.757, 1480.597, 1446.743, 1383.516, 1338.455, 1291.611, 1252.498,
+		1192.249, 1154.033, 1118.803, 1069.303, 1035.147, 999.9101, 942.5088, 912.2457,
+		871.0766, 847.5217, 830.8286, 817.9203, 781.1612, 745.7873, 707.6043, 670.4688,
+		634.8266, 605.3719, 599.7955, 553.6035, 533.3452, 489.725, 451.1244, 443.3444,
+		421.898, 396.5654, 387.1773, 367.3589, 336.8899, 315.4874, 307.4241, 298.1142,
+		274.8204, 255.1449, 242.1751, 214.68, 181.7486, 168.25, 151.0175, 106.8481,
+		78.58671, 73.67154, 50.51903, 50.54978, 49.48201, 33.02024, 37.47542, 13.09203,
+	},
+	{
+		185701.9, 182355.4, 179047.9, 175775.2, 172543.3, 169349.7, 166192.8, 163075,
+		159991.4, 156947.4, 153939.1, 150970.1, 148044.7, 145148.5, 142292.7, 139478.6,
+		136702.2, 133959.4, 131258.6, 128593.3, 125960.9, 123368.2, 120808.7, 118287.8,
+		115804, 113365.9, 110950.5, 108580.2, 106247, 103954.7, 101696.5, 99476.91,
+		97284.5, 95117.71, 92994.6, 90908.3, 88857.9, 86852.35, 84866.24, 82901.07,
+		80978.92, 79084.67, 77235.8, 75420.82, 73639.86, 71888.16, 70156.69, 68461.95,
+		66802.63, 65173.26, 63594.02, 62024.91, 60503.95, 58991.8, 57524.43, 56079.69,
+		54656.11, 53262.7, 51887.5, 50548.41, 49242.73, 47971.34, 46716.4, 45486.32,
+		44280.13, 43118.42, 41962.36, 40827.54, 39721.39, 38661.21, 37622.03, 36578.29,
+		35570.28, 34585.7, 33620.26, 32695.47, 31774.6, 30875.28, 29991.26, 29160.53,
+		28331.65, 27518.13, 26717.34, 25951.82, 25212.43, 24499.76, 23780.05, 23085.66,
+		22402.01, 21735.79, 21088.52, 20446.14, 19812.08, 19204.01, 18643.36, 18060.13,
+		17493.31, 16970.97, 16459.43, 15968.04, 15499.28, 15000.11, 14548.11, 14113.46,
+		13667.74, 13232.18, 12813.19, 12388.68, 11981.94, 11640.76, 11284.88, 10909.35,
+		10560.87, 10220.08, 9888.359, 9559.406, 9235.133, 8930.5, 8630.453, 8381.546,
+		8129.422, 7869.352, 7625.285, 7344.885, 7112.394, 6854.815, 6646.657, 6417.642,
+		6252.947, 6047.74, 5843.723, 5672.364, 5466.982, 5313.987, 5176.586, 5025.452,
+		4860.871, 4648.148, 4497.847, 4352.373, 4197.286, 4066.472, 3982.898, 3855.812,
+		3712.788, 3581.143, 3423.187, 3330.48, 3220.516, 3117.975, 3010.526, 2940.569,
+		2866.789, 2803.364, 2723.076, 2618.067, 2522.558, 2386.608, 2323.486, 2263.359,
+		2201.992, 2095.7, 2001.244, 1974.067, 1903.399, 1817.615, 1773.125, 1689.202,
+		1634.114, 1590.514, 1528.325, 1512.834, 1455.093, 1370.352, 1321.953, 1263.131,
+		1230.693, 1162.755, 1141.326, 1130.212, 1151.569, 1139.164, 1111.464, 1108.201,
+		1093.311, 1062.269, 1075.967, 966.6485, 943.8125, 936.5441, 926.8854, 860.2325,
+		843.4657, 790.1431, 794.4897, 805.1848, 819.1906, 783.4687, 777.508, 763.3702,
+	},
+}
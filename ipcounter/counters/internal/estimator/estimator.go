@@ -0,0 +1,113 @@
+// Package estimator implements the cardinality estimate of the HyperLogLog++ paper
+// (https://static.googleusercontent.com/media/research.google.com/en//pubs/archive/40671.pdf, figure 7):
+// linear counting for small cardinalities and the raw estimate with empirical bias correction above
+// the threshold of the precision.
+package estimator
+
+import (
+	"math"
+	"math/bits"
+	"sort"
+)
+
+//go:generate go run ./gen -output bias_tables.go
+
+const (
+	// MinPrecision and MaxPrecision bound the precisions with bias correction data
+	MinPrecision = 4
+	MaxPrecision = 18
+
+	// biasNeighbors The number of nearest raw estimates averaged by the bias estimation
+	biasNeighbors = 6
+)
+
+// thresholds Cardinalities below which linear counting is more accurate than the corrected raw estimate,
+// for precisions 4 to 18 (the paper, appendix)
+var thresholds = [MaxPrecision - MinPrecision + 1]float64{
+	10, 20, 40, 80, 220, 400, 900, 1800, 3100, 6500, 11500, 20000, 50000, 120000, 350000,
+}
+
+// Estimate returns the estimated cardinality of the registers of a dense sketch, len(registers) must be
+// a power of two between 2^MinPrecision and 2^MaxPrecision
+func Estimate(registers []uint8) float64 {
+	m := float64(len(registers))
+	precision := uint8(bits.TrailingZeros(uint(len(registers))))
+
+	raw := RawEstimate(registers)
+	corrected := raw
+	if raw <= 5*m {
+		corrected = raw - EstimateBias(raw, precision)
+	}
+
+	if zeroRegisters := countZeroRegisters(registers); zeroRegisters != 0 {
+		linear := LinearCounting(len(registers), zeroRegisters)
+		if linear <= thresholds[precision-MinPrecision] {
+			return linear
+		}
+	}
+
+	return corrected
+}
+
+// RawEstimate returns the raw HyperLogLog estimate, the normalized harmonic mean of 2^register
+func RawEstimate(registers []uint8) float64 {
+	sum := 0.0
+	for _, val := range registers {
+		sum += 1.0 / float64(uint64(1)<<val)
+	}
+
+	m := len(registers)
+	return Alpha(m) * float64(m) * float64(m) / sum
+}
+
+// LinearCounting estimates the cardinality from the number of empty registers
+func LinearCounting(m int, zeroRegisters int) float64 {
+	return float64(m) * math.Log(float64(m)/float64(zeroRegisters))
+}
+
+// Alpha returns the bias correction constant of the raw estimate for m registers
+func Alpha(m int) float64 {
+	switch m {
+	case 16:
+		return 0.673
+	case 32:
+		return 0.697
+	case 64:
+		return 0.709
+	default:
+		return 0.7213 / (1 + 1.079/float64(m))
+	}
+}
+
+// EstimateBias returns the bias of the raw estimate, the mean bias of the nearest raw estimates in the empirical data
+func EstimateBias(raw float64, precision uint8) float64 {
+	estimates := rawEstimateData[precision-MinPrecision]
+	biases := biasData[precision-MinPrecision]
+
+	// Grow the window of nearest neighbors around the position of the raw estimate
+	right := sort.SearchFloat64s(estimates, raw)
+	left := right
+	for right-left < biasNeighbors && right-left < len(estimates) {
+		if left == 0 || (right < len(estimates) && estimates[right]-raw < raw-estimates[left-1]) {
+			right++
+		} else {
+			left--
+		}
+	}
+
+	sum := 0.0
+	for _, bias := range biases[left:right] {
+		sum += bias
+	}
+	return sum / float64(right-left)
+}
+
+func countZeroRegisters(registers []uint8) int {
+	var count int
+	for _, v := range registers {
+		if v == 0 {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,107 @@
+package estimator
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"math/rand/v2"
+	"sort"
+	"testing"
+)
+
+// simulate fills the registers with n random hashes
+func simulate(registers []uint8, n int, rng *rand.Rand) {
+	clear(registers)
+	precision := uint8(bits.TrailingZeros(uint(len(registers))))
+	for i := 0; i < n; i++ {
+		hash := rng.Uint64()
+		index := hash >> (64 - precision)
+		rank := uint8(bits.TrailingZeros64(hash|1<<(64-precision))) + 1
+		registers[index] = max(registers[index], rank)
+	}
+}
+
+func TestBiasTables(t *testing.T) {
+	for i := range rawEstimateData {
+		if len(rawEstimateData[i]) == 0 || len(rawEstimateData[i]) != len(biasData[i]) {
+			t.Errorf("Precision %d: %d raw estimates and %d biases", i+MinPrecision, len(rawEstimateData[i]), len(biasData[i]))
+		}
+		if !sort.Float64sAreSorted(rawEstimateData[i]) {
+			t.Errorf("Precision %d: raw estimates must be sorted", i+MinPrecision)
+		}
+	}
+}
+
+func TestEstimateBias(t *testing.T) {
+	estimates := rawEstimateData[0]
+	biases := biasData[0]
+
+	// Below and above the data the nearest neighbors are the first and the last points
+	if bias, expected := EstimateBias(0, MinPrecision), mean(biases[:biasNeighbors]); bias != expected {
+		t.Errorf("Expected bias %f below the data, got %f", expected, bias)
+	}
+	if bias, expected := EstimateBias(1e9, MinPrecision), mean(biases[len(biases)-biasNeighbors:]); bias != expected {
+		t.Errorf("Expected bias %f above the data, got %f", expected, bias)
+	}
+	if bias, expected := EstimateBias(estimates[20], MinPrecision), mean(biases[17:23]); math.Abs(bias-expected) > 1e-9 {
+		t.Errorf("Expected bias %f inside the data, got %f", expected, bias)
+	}
+}
+
+func mean(values []float64) float64 {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// TestEstimateAccuracy sweeps cardinalities around the switch from linear counting to the raw estimate,
+// where the classic estimator has its bias bump, and checks the mean and the spread of the relative error
+func TestEstimateAccuracy(t *testing.T) {
+	for _, precision := range []uint8{4, 8, 10, 14} {
+		m := 1 << precision
+		standardError := 1.04 / math.Sqrt(float64(m))
+		trials := max(20, (1<<20)/(10*m))
+		rng := rand.New(rand.NewPCG(uint64(precision), 2))
+		registers := make([]uint8, m)
+
+		for _, factor := range []float64{0.1, 0.5, 1, 2, 2.5, 3, 4, 5, 6, 10} {
+			n := int(factor * float64(m))
+			if n == 0 {
+				continue
+			}
+			t.Run(fmt.Sprintf("Precision%d/Cardinality%d", precision, n), func(t *testing.T) {
+				var sumError, sumSquaredError float64
+				for trial := 0; trial < trials; trial++ {
+					simulate(registers, n, rng)
+					relativeError := Estimate(registers)/float64(n) - 1
+					sumError += relativeError
+					sumSquaredError += relativeError * relativeError
+				}
+				bias := sumError / float64(trials)
+				rmse := math.Sqrt(sumSquaredError / float64(trials))
+
+				// The mean error of the corrected estimate stays well inside the standard error
+				if math.Abs(bias) > standardError/2 {
+					t.Errorf("Mean relative error %.4f, expected within %.4f", bias, standardError/2)
+				}
+				if rmse > 1.5*standardError {
+					t.Errorf("Root mean square relative error %.4f, expected below %.4f", rmse, 1.5*standardError)
+				}
+			})
+		}
+	}
+}
+
+// TestEstimateLargeCardinality checks that the estimate of 64-bit hashes isn't capped near 2^32
+func TestEstimateLargeCardinality(t *testing.T) {
+	registers := make([]uint8, 1<<14)
+	for i := range registers {
+		registers[i] = 36
+	}
+	estimate := Estimate(registers)
+	if estimate != RawEstimate(registers) || estimate < 1<<40 {
+		t.Errorf("Expected the raw estimate above 2^40, got %.0f", estimate)
+	}
+}
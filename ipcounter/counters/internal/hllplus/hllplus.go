@@ -0,0 +1,176 @@
+// Package hllplus implements the HyperLogLog++ sketch shared by the hyperloglogplus and hyperloglogplusbitmap packages:
+// a sparse representation for small cardinalities, switched to dense registers when it outgrows them.
+package hllplus
+
+import (
+	"awesomeProject/ipcounter/counters/header"
+	"awesomeProject/ipcounter/counters/internal/estimator"
+	"awesomeProject/ipcounter/counters/internal/registers"
+	"awesomeProject/ipcounter/counters/internal/sparse"
+	"fmt"
+	"math"
+)
+
+type Sketch struct {
+	registers       []uint8     // Array of registers, allocated when the sketch becomes dense
+	precision       uint8       // Precision (number of bits for addressing registers)
+	numRegisters    uint32      // Number of registers (2^precision)
+	IsSparse        bool        // Flag to indicate if using sparse representation
+	sparse          *sparse.Set // Sparse representation of small cardinalities
+	sparseThreshold int         // Size of the sparse representation in bytes that triggers the switch to dense
+	hashFunc        header.HashFunc
+}
+
+func New(precision uint8) (*Sketch, error) {
+	if precision < estimator.MinPrecision || precision > estimator.MaxPrecision {
+		return nil, fmt.Errorf("invalid precision: %d, must be between %d and %d", precision, estimator.MinPrecision, estimator.MaxPrecision)
+	}
+
+	numRegisters := uint32(1 << precision)
+	return &Sketch{
+		numRegisters: numRegisters,
+		precision:    precision,
+		IsSparse:     true,
+		sparse:       sparse.New(precision),
+		// The sparse representation is used while it takes less than 6 bits per register, as in the paper
+		sparseThreshold: int(numRegisters) * 6 / 8,
+	}, nil
+}
+
+func (h *Sketch) Add(hash uint64) {
+	if h.IsSparse {
+		h.sparse.Add(hash)
+
+		// Check if we need to switch to dense representation, buffered hashes take more space than merged ones
+		if h.sparse.Size() > h.sparseThreshold {
+			h.sparse.Flush()
+			if h.sparse.Size() > h.sparseThreshold {
+				h.toDense()
+			}
+		}
+		return
+	}
+
+	registerIndex, rank := registers.Position(hash, h.precision)
+	// Update the register if the new value is greater
+	if rank > h.registers[registerIndex] {
+		h.registers[registerIndex] = rank
+	}
+}
+
+// toDense allocates the registers and moves the sparse representation to them
+func (h *Sketch) toDense() {
+	h.registers = make([]uint8, h.numRegisters)
+	h.sparse.ForEach(h.updateRegister)
+	h.IsSparse = false
+	h.sparse = nil
+}
+
+func (h *Sketch) updateRegister(index uint32, rank uint8) {
+	if rank > h.registers[index] {
+		h.registers[index] = rank
+	}
+}
+
+// Merge adds the other sketch, both sketches must have the same precision.
+// Two sparse representations are merged list by list, a dense sketch by the register-wise maximum.
+func (h *Sketch) Merge(other *Sketch) error {
+	if h.precision != other.precision {
+		return fmt.Errorf("can't merge sketches of different precision: %d and %d", h.precision, other.precision)
+	}
+	if h.hashFunc != other.hashFunc {
+		return fmt.Errorf("can't merge sketches of different hash functions: %v and %v", h.hashFunc, other.hashFunc)
+	}
+	switch {
+	case h.IsSparse && other.IsSparse:
+		if err := h.sparse.Merge(other.sparse); err != nil {
+			return err
+		}
+		if h.sparse.Size() > h.sparseThreshold {
+			h.toDense()
+		}
+	case other.IsSparse:
+		other.sparse.ForEach(h.updateRegister)
+	default:
+		if h.IsSparse {
+			h.toDense()
+		}
+		registers.Merge(h.registers, other.registers)
+	}
+	return nil
+}
+
+func (h *Sketch) Count() uint64 {
+	if h.IsSparse {
+		return h.sparse.Count()
+	}
+	return uint64(math.Round(estimator.Estimate(h.registers)))
+}
+
+func (h *Sketch) DisableSparseSet() {
+	if h.IsSparse {
+		h.toDense()
+	}
+}
+
+// Precision returns the number of bits used for addressing registers
+func (h *Sketch) Precision() uint8 {
+	return h.precision
+}
+
+// StandardError returns the relative standard error of the count, the sparse representation is nearly exact
+func (h *Sketch) StandardError() float64 {
+	if h.IsSparse {
+		return h.sparse.StandardError()
+	}
+	return 1.04 / math.Sqrt(float64(h.numRegisters))
+}
+
+// SetHashFunc records the hash function applied to the IPs, it is stored when the sketch is serialized
+func (h *Sketch) SetHashFunc(hashFunc header.HashFunc) {
+	h.hashFunc = hashFunc
+}
+
+func (h *Sketch) HashFunc() header.HashFunc {
+	return h.hashFunc
+}
+
+// MarshalBinary serializes the sketch with a header of the type.
+// The payload of a sparse sketch is its encoded list, a dense one stores the registers.
+func (h *Sketch) MarshalBinary(typ header.Type) ([]byte, error) {
+	hdr := header.Header{Type: typ, Precision: h.precision, HashFunc: h.hashFunc, Sparse: h.IsSparse}
+	if !h.IsSparse {
+		data := make([]byte, 0, header.Size+len(h.registers))
+		return append(hdr.Append(data), h.registers...), nil
+	}
+	data := make([]byte, 0, header.Size+4+h.sparse.Size())
+	return h.sparse.AppendBinary(hdr.Append(data)), nil
+}
+
+// Unmarshal decodes a sketch serialized with a header of the type
+func Unmarshal(data []byte, typ header.Type) (*Sketch, error) {
+	hdr, payload, err := header.Decode(data, typ)
+	if err != nil {
+		return nil, err
+	}
+	decoded, err := New(hdr.Precision)
+	if err != nil {
+		return nil, err
+	}
+	decoded.hashFunc = hdr.HashFunc
+
+	if hdr.Sparse {
+		decoded.sparse, err = sparse.Decode(hdr.Precision, payload)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		decoded.registers = make([]uint8, decoded.numRegisters)
+		if err := registers.Decode(decoded.registers, payload); err != nil {
+			return nil, err
+		}
+		decoded.IsSparse = false
+		decoded.sparse = nil
+	}
+	return decoded, nil
+}
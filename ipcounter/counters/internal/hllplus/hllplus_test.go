@@ -0,0 +1,110 @@
+package hllplus
+
+import (
+	"awesomeProject/ipcounter/utils/murmur3"
+	"fmt"
+	"testing"
+)
+
+func TestSketchAddSparse(t *testing.T) {
+	hll, _ := New(8)
+
+	testCases := []struct {
+		hash          uint64
+		expectedIndex uint32
+		expectedRank  uint8
+	}{
+		{1<<57 + 2, 2, 2},
+		{1<<58 + 8, 4, 4},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Hash%d", tc.hash), func(t *testing.T) {
+			hll.Add(tc.hash)
+			if n := hll.sparse.Len(); n != i+1 {
+				t.Errorf("Expected %d hashes in the sparse representation, got: %d", i+1, n)
+			}
+			found := false
+			hll.sparse.ForEach(func(index uint32, rank uint8) {
+				found = found || (index == tc.expectedIndex && rank == tc.expectedRank)
+			})
+			if !found {
+				t.Errorf("Expected register %d with rank %d in the sparse representation", tc.expectedIndex, tc.expectedRank)
+			}
+		})
+	}
+
+	// The registers are only allocated when the sketch becomes dense
+	if hll.registers != nil {
+		t.Errorf("Expected no registers in sparse mode, got %d", len(hll.registers))
+	}
+}
+
+func TestSketchAddNotSparse(t *testing.T) {
+	hll, _ := New(8)
+	hll.toDense()
+
+	testCases := []struct {
+		hash             uint64
+		expectedRegister int
+		expectedValue    uint8
+	}{
+		{1<<57 + 2, 2, 2},
+		{1<<58 + 8, 4, 4},
+		{1<<59 + 16, 8, 5},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("Hash%d", tc.hash), func(t *testing.T) {
+			hll.Add(tc.hash)
+			if hll.registers[tc.expectedRegister] != tc.expectedValue {
+				t.Errorf("Expected register[%d] to be %d, got: %d", tc.expectedRegister, tc.expectedValue, hll.registers[tc.expectedRegister])
+			}
+		})
+	}
+}
+
+func TestSketchThresholdTransition(t *testing.T) {
+	precision := uint8(4) // Use low precision for quick threshold reach
+	hll, err := New(precision)
+	if err != nil {
+		t.Fatalf("Failed to create sketch: %v", err)
+	}
+	// Check that the initial state is sparse
+	if !hll.IsSparse {
+		t.Errorf("Expected initial state to be sparse")
+	}
+
+	// Add elements until the sparse representation outgrows the threshold
+	dense, _ := New(precision)
+	dense.toDense()
+	for i := 0; hll.IsSparse; i++ {
+		hash := murmur3.Sum64([]byte(fmt.Sprintf("%d", i)))
+		hll.Add(hash)
+		dense.Add(hash)
+		if hll.IsSparse && hll.sparse.Size() > hll.sparseThreshold {
+			t.Fatalf("Expected to switch to dense mode at iteration %d, sparse size %d", i, hll.sparse.Size())
+		}
+		if i > hll.sparseThreshold {
+			t.Fatalf("Expected to switch to dense mode after at most %d hashes", hll.sparseThreshold)
+		}
+	}
+
+	// The registers restored from the sparse representation match the ones filled directly
+	for i := range dense.registers {
+		if hll.registers[i] != dense.registers[i] {
+			t.Errorf("Expected register[%d] to be %d, got %d", i, dense.registers[i], hll.registers[i])
+		}
+	}
+
+	// Check that registers contain non-zero values
+	zeroCount := 0
+	for _, v := range hll.registers {
+		if v == 0 {
+			zeroCount++
+		}
+	}
+	if zeroCount == len(hll.registers) {
+		t.Errorf("Expected some non-zero registers after transition")
+	}
+}
@@ -0,0 +1,39 @@
+// Package registers implements the dense registers shared by the HyperLogLog sketches:
+// the register and rank of a hash, the register-wise maximum and the decoding of saved registers.
+package registers
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// Position returns the register of the hash, addressed by its precision most significant bits,
+// and its rank, the number of trailing zeros + 1 of the remaining bits.
+// The bit above the remaining bits stops the count when they are all zero.
+func Position(hash uint64, precision uint8) (uint64, uint8) {
+	return hash >> (64 - precision), uint8(bits.TrailingZeros64(hash|1<<(64-precision))) + 1
+}
+
+// Merge sets the registers to the register-wise maximum of both register arrays
+func Merge(registers, other []uint8) {
+	for i, val := range other {
+		if val > registers[i] {
+			registers[i] = val
+		}
+	}
+}
+
+// Decode copies the serialized registers, a register can't be larger than the 64-p remaining bits + 1
+func Decode(registers, payload []byte) error {
+	if len(payload) != len(registers) {
+		return fmt.Errorf("%d registers expected, got %d", len(registers), len(payload))
+	}
+	maxRank := uint8(65 - bits.TrailingZeros(uint(len(registers))))
+	for i, val := range payload {
+		if val > maxRank {
+			return fmt.Errorf("invalid register value %d at %d", val, i)
+		}
+	}
+	copy(registers, payload)
+	return nil
+}
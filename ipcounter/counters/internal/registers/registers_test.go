@@ -0,0 +1,57 @@
+package registers
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestPosition(t *testing.T) {
+	testCases := []struct {
+		hash          uint64
+		precision     uint8
+		expectedIndex uint64
+		expectedRank  uint8
+	}{
+		{1<<57 + 2, 8, 2, 2},
+		{1<<58 + 8, 8, 4, 4},
+		{1<<59 + 16, 8, 8, 5},
+		// The remaining bits are all zero
+		{1 << 63, 8, 128, 57},
+		{0, 4, 0, 61},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("Hash%d", tc.hash), func(t *testing.T) {
+			index, rank := Position(tc.hash, tc.precision)
+			if index != tc.expectedIndex || rank != tc.expectedRank {
+				t.Errorf("Position() = %d, %d, want %d, %d", index, rank, tc.expectedIndex, tc.expectedRank)
+			}
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	registers := []uint8{0, 5, 2, 7}
+	Merge(registers, []uint8{3, 1, 2, 9})
+	if expected := []uint8{3, 5, 2, 9}; !slices.Equal(registers, expected) {
+		t.Errorf("Merge() = %v, want %v", registers, expected)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	registers := make([]uint8, 16)
+	if err := Decode(registers, make([]byte, 15)); err == nil {
+		t.Errorf("Expected an error for a missing register")
+	}
+	// 16 registers leave 60 bits, so the rank is at most 61
+	payload := make([]byte, 16)
+	payload[3] = 62
+	if err := Decode(registers, payload); err == nil {
+		t.Errorf("Expected an error for a rank past the remaining bits")
+	}
+	payload[3] = 61
+	if err := Decode(registers, payload); err != nil || registers[3] != 61 {
+		t.Errorf("Decode() error = %v, register = %d", err, registers[3])
+	}
+}
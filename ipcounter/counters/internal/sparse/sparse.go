@@ -0,0 +1,260 @@
+// Package sparse implements the sparse representation of HyperLogLog++
+// (https://static.googleusercontent.com/media/research.google.com/en//pubs/archive/40671.pdf, section 5.3).
+//
+// A 64-bit hash is encoded with the higher precision p' = 25: the register index of the dense sketch is
+// extended with the p'-p lowest bits of the hash, which also define the rank (trailing zeros + 1)
+// used by the dense registers. The rank is only stored when these bits are all zero:
+//
+//	key (25 bits) | rank (6 bits) | flag (1 bit)
+//
+// Encoded hashes are kept in a sorted list, difference and varint encoded, plus a small unsorted
+// buffer that is merged into the list when it is full.
+package sparse
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"slices"
+)
+
+// Precision is the precision p' of the sparse representation
+const Precision = 25
+
+const (
+	numKeys  = 1 << Precision
+	rankBits = 6
+	// minBufferSize The smallest number of encoded hashes buffered before they are merged into the list
+	minBufferSize = 16
+)
+
+var errCorrupted = errors.New("corrupted sparse list")
+
+// Set is a sparse HyperLogLog++ sketch for a dense sketch of the given precision
+type Set struct {
+	precision uint8
+	list      []byte   // Sorted encoded hashes, difference and varint encoded
+	length    int      // Number of encoded hashes in the list
+	buffer    []uint32 // Encoded hashes not merged into the list yet
+}
+
+// New creates an empty Set for a dense sketch of the given precision
+func New(precision uint8) *Set {
+	bufferSize := (1 << precision) / 16
+	if bufferSize < minBufferSize {
+		bufferSize = minBufferSize
+	}
+	return &Set{
+		precision: precision,
+		buffer:    make([]uint32, 0, bufferSize),
+	}
+}
+
+// Add adds a hash, the top precision bits select the register like in the dense sketch
+func (s *Set) Add(hash uint64) {
+	s.buffer = append(s.buffer, s.encode(hash))
+	if len(s.buffer) == cap(s.buffer) {
+		s.Flush()
+	}
+}
+
+// encode packs the extended register index and, if the extra bits don't define it, the rank of the hash
+func (s *Set) encode(hash uint64) uint32 {
+	extraBits := Precision - s.precision
+	index := uint32(hash >> (64 - s.precision))
+	extra := uint32(hash) & (1<<extraBits - 1)
+	key := index<<extraBits | extra
+	if extra != 0 {
+		return key << (rankBits + 1)
+	}
+	// At most 64-p trailing zeros + 1, which fits the rank bits
+	rank := uint32(bits.TrailingZeros64(hash|1<<(64-s.precision))) + 1
+	return key<<(rankBits+1) | rank<<1 | 1
+}
+
+// decode returns the register index and the rank of an encoded hash
+func (s *Set) decode(encoded uint32) (uint32, uint8) {
+	extraBits := Precision - s.precision
+	key := encoded >> (rankBits + 1)
+	index := key >> extraBits
+	if encoded&1 == 1 {
+		return index, uint8(encoded>>1) & (1<<rankBits - 1)
+	}
+	extra := key & (1<<extraBits - 1)
+	return index, uint8(bits.TrailingZeros32(extra)) + 1
+}
+
+// Size returns the number of bytes used by the encoded hashes, the list and the buffer
+func (s *Set) Size() int {
+	return len(s.list) + 4*len(s.buffer)
+}
+
+// Len returns the number of distinct keys
+func (s *Set) Len() int {
+	s.Flush()
+	return s.length
+}
+
+// Count estimates the number of distinct hashes with linear counting over the 2^p' keys
+func (s *Set) Count() uint64 {
+	n := s.Len()
+	if n == 0 {
+		return 0
+	}
+	return uint64(math.Round(numKeys * math.Log(numKeys/float64(numKeys-n))))
+}
+
+// StandardError returns the relative standard error of linear counting over the 2^p' keys
+func (s *Set) StandardError() float64 {
+	n := float64(s.Len())
+	if n == 0 {
+		return 0
+	}
+	t := n / numKeys
+	return math.Sqrt(numKeys*(math.Exp(t)-t-1)) / n
+}
+
+// ForEach calls fn with the register index and the rank of each encoded hash, e.g. to convert to a dense sketch
+func (s *Set) ForEach(fn func(index uint32, rank uint8)) {
+	s.Flush()
+	r := listReader{data: s.list}
+	for encoded, ok := r.next(); ok; encoded, ok = r.next() {
+		fn(s.decode(encoded))
+	}
+}
+
+// Merge adds the hashes of another Set of the same precision
+func (s *Set) Merge(other *Set) error {
+	if s.precision != other.precision {
+		return fmt.Errorf("can't merge sparse sets of different precision: %d and %d", s.precision, other.precision)
+	}
+	s.Flush()
+	other.Flush()
+	first := listReader{data: s.list}
+	second := listReader{data: other.list}
+	s.list, s.length = mergeSorted(first.next, second.next, len(s.list)+len(other.list))
+	return nil
+}
+
+// Flush merges the buffer into the list
+func (s *Set) Flush() {
+	if len(s.buffer) == 0 {
+		return
+	}
+	slices.Sort(s.buffer)
+	list := listReader{data: s.list}
+	buffer := s.buffer
+	nextBuffered := func() (uint32, bool) {
+		if len(buffer) == 0 {
+			return 0, false
+		}
+		encoded := buffer[0]
+		buffer = buffer[1:]
+		return encoded, true
+	}
+	s.list, s.length = mergeSorted(list.next, nextBuffered, len(s.list)+2*len(s.buffer))
+	s.buffer = s.buffer[:0]
+}
+
+// mergeSorted merges two sorted sequences of encoded hashes into a new list.
+// Of the hashes with the same key only the last one is kept, it has the highest rank.
+func mergeSorted(first, second func() (uint32, bool), sizeHint int) ([]byte, int) {
+	var w listWriter
+	w.data = make([]byte, 0, sizeHint)
+	var pending uint32
+	hasPending := false
+	push := func(encoded uint32) {
+		if hasPending && encoded>>(rankBits+1) != pending>>(rankBits+1) {
+			w.write(pending)
+		}
+		pending, hasPending = encoded, true
+	}
+
+	a, okA := first()
+	b, okB := second()
+	for okA || okB {
+		if okA && (!okB || a <= b) {
+			push(a)
+			a, okA = first()
+		} else {
+			push(b)
+			b, okB = second()
+		}
+	}
+	if hasPending {
+		w.write(pending)
+	}
+	return w.data, w.length
+}
+
+// AppendBinary appends the serialized list: the number of encoded hashes followed by the list
+func (s *Set) AppendBinary(b []byte) []byte {
+	s.Flush()
+	b = binary.LittleEndian.AppendUint32(b, uint32(s.length))
+	return append(b, s.list...)
+}
+
+// Decode restores a Set serialized with AppendBinary and checks that the list is valid
+func Decode(precision uint8, data []byte) (*Set, error) {
+	if len(data) < 4 {
+		return nil, errCorrupted
+	}
+	s := New(precision)
+	s.length = int(binary.LittleEndian.Uint32(data))
+	s.list = append([]byte(nil), data[4:]...)
+
+	r := listReader{data: s.list}
+	n := 0
+	var previousKey uint32
+	for encoded, ok := r.next(); ok; encoded, ok = r.next() {
+		key := encoded >> (rankBits + 1)
+		extraBits := Precision - precision
+		_, rank := s.decode(encoded)
+		validFlag := (encoded&1 == 1) == (key&(1<<extraBits-1) == 0)
+		if (n > 0 && key <= previousKey) || !validFlag || rank == 0 || rank > 65-precision {
+			return nil, errCorrupted
+		}
+		previousKey = key
+		n++
+	}
+	if r.err || n != s.length {
+		return nil, errCorrupted
+	}
+	return s, nil
+}
+
+// listWriter appends encoded hashes as varint differences to the previous one
+type listWriter struct {
+	data     []byte
+	previous uint32
+	length   int
+}
+
+func (w *listWriter) write(encoded uint32) {
+	w.data = binary.AppendUvarint(w.data, uint64(encoded-w.previous))
+	w.previous = encoded
+	w.length++
+}
+
+// listReader decodes a list written by listWriter
+type listReader struct {
+	data     []byte
+	previous uint32
+	err      bool
+}
+
+func (r *listReader) next() (uint32, bool) {
+	if len(r.data) == 0 {
+		return 0, false
+	}
+	delta, n := binary.Uvarint(r.data)
+	if n <= 0 || delta > math.MaxUint32 {
+		r.data, r.err = nil, true
+		return 0, false
+	}
+	r.data = r.data[n:]
+	r.previous += uint32(delta)
+	return r.previous, true
+}
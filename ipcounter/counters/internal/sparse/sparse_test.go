@@ -0,0 +1,133 @@
+package sparse
+
+import (
+	"math/bits"
+	"math/rand"
+	"testing"
+)
+
+// denseRegister returns the register index and rank of a hash as the dense sketches compute them
+func denseRegister(hash uint64, precision uint8) (uint32, uint8) {
+	return uint32(hash >> (64 - precision)), uint8(bits.TrailingZeros64(hash|1<<(64-precision))) + 1
+}
+
+func TestEncodeDecode(t *testing.T) {
+	testCases := []struct {
+		name string
+		hash uint64
+	}{
+		{"Extra bits set", 0x123456789ABCDEF0 | 1},
+		{"Extra bits zero", 0x1234567800000000},
+		{"Zero hash", 0},
+		{"All ones", 0xFFFFFFFFFFFFFFFF},
+		{"Only register bits", 0xFFFFC00000000000},
+	}
+
+	for _, precision := range []uint8{4, 14, 16, 18} {
+		s := New(precision)
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				index, rank := s.decode(s.encode(tc.hash))
+				expectedIndex, expectedRank := denseRegister(tc.hash, precision)
+				if index != expectedIndex || rank != expectedRank {
+					t.Errorf("Precision %d: expected register %d with rank %d, got %d with rank %d",
+						precision, expectedIndex, expectedRank, index, rank)
+				}
+			})
+		}
+	}
+}
+
+func TestAddAndForEach(t *testing.T) {
+	const precision = 10
+	s := New(precision)
+	registers := make([]uint8, 1<<precision)
+	for i := 0; i < 5000; i++ {
+		hash := rand.Uint64()
+		if i%3 == 0 {
+			// Duplicates and hashes with zero extra bits
+			hash &^= 1<<(Precision-precision) - 1
+		}
+		s.Add(hash)
+		s.Add(hash)
+		index, rank := denseRegister(hash, precision)
+		registers[index] = max(registers[index], rank)
+	}
+
+	fromSparse := make([]uint8, 1<<precision)
+	s.ForEach(func(index uint32, rank uint8) {
+		fromSparse[index] = max(fromSparse[index], rank)
+	})
+	for i := range registers {
+		if registers[i] != fromSparse[i] {
+			t.Errorf("Expected register[%d] to be %d, got %d", i, registers[i], fromSparse[i])
+		}
+	}
+}
+
+func TestCount(t *testing.T) {
+	s := New(14)
+	for i := 0; i < 10000; i++ {
+		s.Add(rand.Uint64())
+	}
+	count := s.Count()
+	// The 25-bit keys collide rarely, so the count is nearly exact
+	if count < 9990 || count > 10010 {
+		t.Errorf("Expected a count close to 10000, got %d", count)
+	}
+	if s.Size() > 4*10000 {
+		t.Errorf("Expected the encoded list to be smaller than the raw hashes, got %d bytes", s.Size())
+	}
+}
+
+func TestMerge(t *testing.T) {
+	s, other, expected := New(12), New(12), New(12)
+	for i := 0; i < 3000; i++ {
+		hash := rand.Uint64()
+		if i < 2000 {
+			s.Add(hash)
+		}
+		if i >= 1000 {
+			other.Add(hash)
+		}
+		expected.Add(hash)
+	}
+
+	if err := s.Merge(other); err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+	if s.Len() != expected.Len() || string(s.list) != string(expected.list) {
+		t.Errorf("Expected the merged list to match, got %d keys instead of %d", s.Len(), expected.Len())
+	}
+	if err := s.Merge(New(13)); err == nil {
+		t.Errorf("Expected an error when merging different precisions")
+	}
+}
+
+func TestDecode(t *testing.T) {
+	s := New(12)
+	for i := 0; i < 1000; i++ {
+		s.Add(rand.Uint64())
+	}
+	data := s.AppendBinary(nil)
+
+	decoded, err := Decode(12, data)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if decoded.Len() != s.Len() || decoded.Count() != s.Count() {
+		t.Errorf("Expected %d keys after decoding, got %d", s.Len(), decoded.Len())
+	}
+
+	corrupted := [][]byte{
+		nil,
+		data[:len(data)-1],
+		append(append([]byte{}, data...), 1),
+		append(append([]byte{}, data[:4]...), 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF),
+	}
+	for i, data := range corrupted {
+		if _, err := Decode(12, data); err == nil {
+			t.Errorf("Expected an error for corrupted data %d", i)
+		}
+	}
+}
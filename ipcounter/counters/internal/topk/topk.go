@@ -0,0 +1,138 @@
+// Package topk implements a bounded set of keys with the lowest count on top, the building block
+// of the heavy hitters sketches: the key with the lowest count is the one to evict.
+package topk
+
+import (
+	"slices"
+)
+
+// Entry is a key, its estimated count and the maximum overestimation of the count
+type Entry struct {
+	Key   uint32
+	Count uint64
+	Error uint64
+}
+
+// Heap is a min-heap of entries by count, with an index of the position of each key
+type Heap struct {
+	entries  []Entry
+	position map[uint32]int
+}
+
+// New creates an empty heap for up to capacity keys
+func New(capacity int) *Heap {
+	return &Heap{
+		entries:  make([]Entry, 0, capacity),
+		position: make(map[uint32]int, capacity),
+	}
+}
+
+// Len returns the number of keys
+func (h *Heap) Len() int {
+	return len(h.entries)
+}
+
+// Min returns the entry with the lowest count, the heap must not be empty
+func (h *Heap) Min() Entry {
+	return h.entries[0]
+}
+
+// Get returns the entry of the key
+func (h *Heap) Get(key uint32) (Entry, bool) {
+	i, ok := h.position[key]
+	if !ok {
+		return Entry{}, false
+	}
+	return h.entries[i], true
+}
+
+// Set inserts the entry or replaces the entry of the same key
+func (h *Heap) Set(entry Entry) {
+	if i, ok := h.position[entry.Key]; ok {
+		h.entries[i] = entry
+		h.fix(i)
+		return
+	}
+	h.entries = append(h.entries, entry)
+	h.position[entry.Key] = len(h.entries) - 1
+	h.up(len(h.entries) - 1)
+}
+
+// ReplaceMin evicts the entry with the lowest count and inserts the new one, the key must not be in the heap
+func (h *Heap) ReplaceMin(entry Entry) {
+	delete(h.position, h.entries[0].Key)
+	h.entries[0] = entry
+	h.position[entry.Key] = 0
+	h.down(0)
+}
+
+// Entries returns the entries in no particular order, the slice must not be modified
+func (h *Heap) Entries() []Entry {
+	return h.entries
+}
+
+// Sorted returns the entries by decreasing count and then by increasing key
+func (h *Heap) Sorted() []Entry {
+	sorted := slices.Clone(h.entries)
+	slices.SortFunc(sorted, func(a, b Entry) int {
+		if a.Count != b.Count {
+			if a.Count > b.Count {
+				return -1
+			}
+			return 1
+		}
+		if a.Key < b.Key {
+			return -1
+		}
+		if a.Key > b.Key {
+			return 1
+		}
+		return 0
+	})
+	return sorted
+}
+
+func (h *Heap) less(i, j int) bool {
+	return h.entries[i].Count < h.entries[j].Count
+}
+
+func (h *Heap) swap(i, j int) {
+	h.entries[i], h.entries[j] = h.entries[j], h.entries[i]
+	h.position[h.entries[i].Key] = i
+	h.position[h.entries[j].Key] = j
+}
+
+func (h *Heap) fix(i int) {
+	if !h.down(i) {
+		h.up(i)
+	}
+}
+
+func (h *Heap) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !h.less(i, parent) {
+			return
+		}
+		h.swap(i, parent)
+		i = parent
+	}
+}
+
+// down moves the entry down to its place and reports whether it moved
+func (h *Heap) down(i int) bool {
+	start := i
+	for {
+		smallest := i
+		for _, child := range []int{2*i + 1, 2*i + 2} {
+			if child < len(h.entries) && h.less(child, smallest) {
+				smallest = child
+			}
+		}
+		if smallest == i {
+			return i > start
+		}
+		h.swap(i, smallest)
+		i = smallest
+	}
+}
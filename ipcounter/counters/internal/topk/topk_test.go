@@ -0,0 +1,51 @@
+package topk
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+func TestHeap(t *testing.T) {
+	h := New(100)
+	rng := rand.New(rand.NewPCG(1, 2))
+	expected := make(map[uint32]uint64)
+	for i := 0; i < 1000; i++ {
+		key := rng.Uint32N(100)
+		count := rng.Uint64N(1000)
+		h.Set(Entry{Key: key, Count: count})
+		expected[key] = count
+
+		lowest := uint64(1000)
+		for _, count := range expected {
+			lowest = min(lowest, count)
+		}
+		if h.Min().Count != lowest {
+			t.Fatalf("Expected the lowest count %d on top, got %d", lowest, h.Min().Count)
+		}
+	}
+
+	if h.Len() != len(expected) {
+		t.Errorf("Expected %d keys, got %d", len(expected), h.Len())
+	}
+	for key, count := range expected {
+		if entry, ok := h.Get(key); !ok || entry.Count != count {
+			t.Errorf("Expected count %d for key %d, got %+v", count, key, entry)
+		}
+	}
+
+	sorted := h.Sorted()
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].Count > sorted[i-1].Count {
+			t.Fatalf("Expected decreasing counts, got %d after %d", sorted[i].Count, sorted[i-1].Count)
+		}
+	}
+
+	evicted := h.Min()
+	h.ReplaceMin(Entry{Key: 1000, Count: 5000})
+	if _, ok := h.Get(evicted.Key); ok {
+		t.Errorf("Expected key %d to be evicted", evicted.Key)
+	}
+	if entry, ok := h.Get(1000); !ok || h.Sorted()[0] != entry {
+		t.Errorf("Expected key 1000 to have the highest count, got %+v", h.Sorted()[0])
+	}
+}
@@ -0,0 +1,164 @@
+// Package prefix counts distinct IPs grouped by network prefix, e.g. the number of distinct /24s seen
+// and the number of unique hosts in each /16, in the same pass as the global distinct count.
+//
+// A bitmap of the whole address space finds the IPs seen for the first time, only those increment the host count
+// of their prefix at each length. A prefix is seen when its host count isn't 0.
+package prefix
+
+import (
+	"awesomeProject/ipcounter/counters/bitmap"
+	"awesomeProject/ipcounter/counters/internal/topk"
+	"fmt"
+	"slices"
+)
+
+const (
+	// MinLength is the shortest prefix length, the prefixes of a length of at least 8 don't span several /8s
+	MinLength = 8
+	// MaxLength is the longest prefix length, a /24 breakdown takes 64 MB
+	MaxLength = 24
+)
+
+// Prefix is a network prefix and its number of unique hosts
+type Prefix struct {
+	IP     uint32 // First IP of the prefix
+	Length uint8
+	Hosts  uint64
+}
+
+// Aggregator counts the unique IPs and the unique hosts of every prefix of the tracked lengths
+type Aggregator struct {
+	hosts  *bitmap.BitMap
+	levels []level
+}
+
+// level is the breakdown of one prefix length
+type level struct {
+	length uint8
+	hosts  []uint32 // Unique hosts of each prefix
+}
+
+// New creates an aggregator of the prefix lengths, each between MinLength and MaxLength
+func New(lengths ...uint8) (*Aggregator, error) {
+	hosts, err := bitmap.NewPaged(bitmap.MaxSize)
+	if err != nil {
+		return nil, err
+	}
+	a := &Aggregator{hosts: hosts}
+	for _, length := range slices.Compact(slices.Sorted(slices.Values(lengths))) {
+		if length < MinLength || length > MaxLength {
+			return nil, fmt.Errorf("invalid prefix length: %d, must be between %d and %d", length, MinLength, MaxLength)
+		}
+		a.levels = append(a.levels, level{length: length, hosts: make([]uint32, 1<<length)})
+	}
+	return a, nil
+}
+
+// Add counts the IP. Only IPs in different /8s can be added concurrently.
+func (a *Aggregator) Add(ip uint32) {
+	if a.hosts.GetBit(ip) {
+		return
+	}
+	a.hosts.SetBit(ip, true)
+	for _, l := range a.levels {
+		l.hosts[ip>>(32-l.length)]++
+	}
+}
+
+// Count returns the number of unique IPs
+func (a *Aggregator) Count() uint64 {
+	return a.hosts.Count()
+}
+
+// Contains reports whether the IP was added
+func (a *Aggregator) Contains(ip uint32) bool {
+	return a.hosts.GetBit(ip)
+}
+
+// Hosts returns the bitmap of the unique IPs, it must not be modified
+func (a *Aggregator) Hosts() *bitmap.BitMap {
+	return a.hosts
+}
+
+// Lengths returns the tracked prefix lengths in increasing order
+func (a *Aggregator) Lengths() []uint8 {
+	lengths := make([]uint8, len(a.levels))
+	for i, l := range a.levels {
+		lengths[i] = l.length
+	}
+	return lengths
+}
+
+// Prefixes returns the number of distinct prefixes of the length seen, the length must be tracked
+func (a *Aggregator) Prefixes(length uint8) (uint64, error) {
+	l, err := a.level(length)
+	if err != nil {
+		return 0, err
+	}
+	var count uint64
+	for _, hosts := range l.hosts {
+		if hosts != 0 {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// HostsIn returns the number of unique hosts in the prefix of the length containing the IP
+func (a *Aggregator) HostsIn(ip uint32, length uint8) (uint64, error) {
+	l, err := a.level(length)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(l.hosts[ip>>(32-length)]), nil
+}
+
+// Top returns the n prefixes of the length with the most unique hosts, by decreasing hosts and then by increasing IP
+func (a *Aggregator) Top(length uint8, n int) ([]Prefix, error) {
+	l, err := a.level(length)
+	if err != nil {
+		return nil, err
+	}
+	if n <= 0 {
+		return nil, nil
+	}
+
+	heap := topk.New(n)
+	for prefix, hosts := range l.hosts {
+		switch {
+		case hosts == 0:
+		case heap.Len() < n:
+			heap.Set(topk.Entry{Key: uint32(prefix), Count: uint64(hosts)})
+		case uint64(hosts) > heap.Min().Count:
+			heap.ReplaceMin(topk.Entry{Key: uint32(prefix), Count: uint64(hosts)})
+		}
+	}
+
+	sorted := heap.Sorted()
+	top := make([]Prefix, len(sorted))
+	for i, entry := range sorted {
+		top[i] = Prefix{IP: entry.Key << (32 - length), Length: length, Hosts: entry.Count}
+	}
+	return top, nil
+}
+
+// Merge adds the IPs of the other aggregator, both aggregators must track the same prefix lengths
+func (a *Aggregator) Merge(other *Aggregator) error {
+	if !slices.Equal(a.Lengths(), other.Lengths()) {
+		return fmt.Errorf("can't merge aggregators of different prefix lengths: %v and %v", a.Lengths(), other.Lengths())
+	}
+	it := other.hosts.Iterator()
+	for ip, ok := it.Next(); ok; ip, ok = it.Next() {
+		a.Add(ip)
+	}
+	return nil
+}
+
+func (a *Aggregator) level(length uint8) (level, error) {
+	for _, l := range a.levels {
+		if l.length == length {
+			return l, nil
+		}
+	}
+	return level{}, fmt.Errorf("prefix length %d isn't tracked", length)
+}
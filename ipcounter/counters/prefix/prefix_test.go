@@ -0,0 +1,117 @@
+package prefix
+
+import (
+	"slices"
+	"testing"
+)
+
+// ip builds an IP from its 4 bytes
+func ip(a, b, c, d uint32) uint32 {
+	return a<<24 | b<<16 | c<<8 | d
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		lengths []uint8
+		want    []uint8
+		wantErr bool
+	}{
+		{"Sorted and deduplicated", []uint8{24, 8, 16, 8}, []uint8{8, 16, 24}, false},
+		{"No breakdown", nil, []uint8{}, false},
+		{"Too short", []uint8{7}, nil, true},
+		{"Too long", []uint8{16, 25}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := New(tt.lengths...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !slices.Equal(a.Lengths(), tt.want) {
+				t.Errorf("Lengths() = %v, want %v", a.Lengths(), tt.want)
+			}
+		})
+	}
+}
+
+func TestAggregator(t *testing.T) {
+	a, err := New(8, 16, 24)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	// 10.1.1.0/24 has 5 hosts, 10.1.2.0/24 has 3, 10.2.0.0/24 has 1 and 192.168.0.0/24 has 2
+	ips := []uint32{
+		ip(10, 1, 1, 1), ip(10, 1, 1, 2), ip(10, 1, 1, 3), ip(10, 1, 1, 4), ip(10, 1, 1, 5),
+		ip(10, 1, 2, 1), ip(10, 1, 2, 2), ip(10, 1, 2, 3),
+		ip(10, 2, 0, 1),
+		ip(192, 168, 0, 1), ip(192, 168, 0, 2),
+		// Duplicates don't count
+		ip(10, 1, 1, 1), ip(192, 168, 0, 2),
+	}
+	for _, ip := range ips {
+		a.Add(ip)
+	}
+
+	if count := a.Count(); count != 11 {
+		t.Errorf("Count() = %d, want 11", count)
+	}
+	for _, tt := range []struct {
+		length uint8
+		want   uint64
+	}{{8, 2}, {16, 3}, {24, 4}} {
+		if got, _ := a.Prefixes(tt.length); got != tt.want {
+			t.Errorf("Prefixes(%d) = %d, want %d", tt.length, got, tt.want)
+		}
+	}
+	if hosts, _ := a.HostsIn(ip(10, 1, 200, 200), 16); hosts != 8 {
+		t.Errorf("HostsIn(10.1.0.0/16) = %d, want 8", hosts)
+	}
+
+	top, err := a.Top(16, 2)
+	if err != nil {
+		t.Fatalf("Top() error = %v", err)
+	}
+	want := []Prefix{
+		{IP: ip(10, 1, 0, 0), Length: 16, Hosts: 8},
+		{IP: ip(192, 168, 0, 0), Length: 16, Hosts: 2},
+	}
+	if !slices.Equal(top, want) {
+		t.Errorf("Top(16, 2) = %v, want %v", top, want)
+	}
+	top, _ = a.Top(24, 10)
+	if len(top) != 4 || top[0].Hosts != 5 || top[3] != (Prefix{IP: ip(10, 2, 0, 0), Length: 24, Hosts: 1}) {
+		t.Errorf("Top(24, 10) = %v, want 4 prefixes from 5 hosts down to 10.2.0.0/24", top)
+	}
+
+	if _, err := a.Prefixes(12); err == nil {
+		t.Errorf("Expected an error for a length that isn't tracked")
+	}
+}
+
+func TestAggregator_Merge(t *testing.T) {
+	a, _ := New(16, 24)
+	other, _ := New(16, 24)
+	a.Add(ip(10, 0, 0, 1))
+	a.Add(ip(10, 0, 0, 2))
+	other.Add(ip(10, 0, 0, 2))
+	other.Add(ip(10, 0, 1, 1))
+
+	if err := a.Merge(other); err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+	if a.Count() != 3 {
+		t.Errorf("Count() = %d, want 3", a.Count())
+	}
+	if hosts, _ := a.HostsIn(ip(10, 0, 0, 0), 16); hosts != 3 {
+		t.Errorf("HostsIn(10.0.0.0/16) = %d, want 3", hosts)
+	}
+	if prefixes, _ := a.Prefixes(24); prefixes != 2 {
+		t.Errorf("Prefixes(24) = %d, want 2", prefixes)
+	}
+
+	different, _ := New(16)
+	if err := a.Merge(different); err == nil {
+		t.Errorf("Expected an error when merging aggregators of different prefix lengths")
+	}
+}
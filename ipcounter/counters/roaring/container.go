@@ -0,0 +1,346 @@
+package roaring
+
+import (
+	"iter"
+	"math/bits"
+	"slices"
+	"sort"
+)
+
+const (
+	// arrayMaxSize is the largest array container, a larger one takes more space than a bitmap container
+	arrayMaxSize = 4096
+	// bitmapWords is the number of 64-bit words of a bitmap container
+	bitmapWords = 1 << 16 / 64
+	// bitmapBytes is the size of a bitmap container
+	bitmapBytes = bitmapWords * 8
+)
+
+// container holds the low 16 bits of the values sharing the same high 16 bits, it is never empty
+type container interface {
+	// add adds the value and reports whether it is new, the container may be replaced by another representation
+	add(low uint16) (container, bool)
+	contains(low uint16) bool
+	cardinality() int
+	// values yields the values in increasing order and reports whether the iteration should continue
+	values(yield func(uint16) bool) bool
+	// words returns a new bitmap of the values
+	words() *[bitmapWords]uint64
+	// sizeInBytes returns the size of the values in memory
+	sizeInBytes() int
+	clone() container
+}
+
+// arrayContainer is a sorted array of the values, for sparse containers
+type arrayContainer struct {
+	array []uint16
+}
+
+func (c *arrayContainer) add(low uint16) (container, bool) {
+	i, found := slices.BinarySearch(c.array, low)
+	if found {
+		return c, false
+	}
+	if len(c.array) == arrayMaxSize {
+		bitmap := newBitmapContainer(c.words())
+		bitmap.add(low)
+		return bitmap, true
+	}
+	c.array = slices.Insert(c.array, i, low)
+	return c, true
+}
+
+func (c *arrayContainer) contains(low uint16) bool {
+	_, found := slices.BinarySearch(c.array, low)
+	return found
+}
+
+func (c *arrayContainer) cardinality() int {
+	return len(c.array)
+}
+
+func (c *arrayContainer) values(yield func(uint16) bool) bool {
+	for _, low := range c.array {
+		if !yield(low) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *arrayContainer) words() *[bitmapWords]uint64 {
+	words := new([bitmapWords]uint64)
+	for _, low := range c.array {
+		words[low>>6] |= 1 << (low & 63)
+	}
+	return words
+}
+
+func (c *arrayContainer) sizeInBytes() int {
+	return 2 * len(c.array)
+}
+
+func (c *arrayContainer) clone() container {
+	return &arrayContainer{slices.Clone(c.array)}
+}
+
+// bitmapContainer is a bitmap of the 2^16 values, for dense containers
+type bitmapContainer struct {
+	bitmap *[bitmapWords]uint64
+	count  int
+}
+
+// newBitmapContainer takes ownership of the words
+func newBitmapContainer(words *[bitmapWords]uint64) *bitmapContainer {
+	return &bitmapContainer{bitmap: words, count: popcount(words)}
+}
+
+func (c *bitmapContainer) add(low uint16) (container, bool) {
+	word, bit := &c.bitmap[low>>6], uint64(1)<<(low&63)
+	if *word&bit != 0 {
+		return c, false
+	}
+	*word |= bit
+	c.count++
+	return c, true
+}
+
+func (c *bitmapContainer) contains(low uint16) bool {
+	return c.bitmap[low>>6]&(1<<(low&63)) != 0
+}
+
+func (c *bitmapContainer) cardinality() int {
+	return c.count
+}
+
+func (c *bitmapContainer) values(yield func(uint16) bool) bool {
+	for i, word := range c.bitmap {
+		for word != 0 {
+			if !yield(uint16(i<<6 + bits.TrailingZeros64(word))) {
+				return false
+			}
+			word &= word - 1
+		}
+	}
+	return true
+}
+
+func (c *bitmapContainer) words() *[bitmapWords]uint64 {
+	words := *c.bitmap
+	return &words
+}
+
+func (c *bitmapContainer) sizeInBytes() int {
+	return bitmapBytes
+}
+
+func (c *bitmapContainer) clone() container {
+	return &bitmapContainer{bitmap: c.words(), count: c.count}
+}
+
+// interval is a run of consecutive values from start to last included
+type interval struct {
+	start, last uint16
+}
+
+// runContainer is a sorted list of runs that neither overlap nor touch, for containers of long runs
+type runContainer struct {
+	runs []interval
+}
+
+func (c *runContainer) add(low uint16) (container, bool) {
+	// i is the first run starting after the value, the run before it may contain the value or end just before it
+	i := sort.Search(len(c.runs), func(i int) bool { return c.runs[i].start > low })
+	if i > 0 {
+		previous := &c.runs[i-1]
+		if low <= previous.last {
+			return c, false
+		}
+		if low == previous.last+1 {
+			previous.last = low
+			// The value may join two runs, the value before 65535 is never the start of the next run
+			if i < len(c.runs) && c.runs[i].start == low+1 {
+				previous.last = c.runs[i].last
+				c.runs = slices.Delete(c.runs, i, i+1)
+			}
+			return c, true
+		}
+	}
+	if i < len(c.runs) && c.runs[i].start == low+1 {
+		c.runs[i].start = low
+		return c, true
+	}
+	c.runs = slices.Insert(c.runs, i, interval{start: low, last: low})
+	if c.sizeInBytes() > bitmapBytes {
+		return fromWords(c.words()), true
+	}
+	return c, true
+}
+
+func (c *runContainer) contains(low uint16) bool {
+	i := sort.Search(len(c.runs), func(i int) bool { return c.runs[i].last >= low })
+	return i < len(c.runs) && c.runs[i].start <= low
+}
+
+func (c *runContainer) cardinality() int {
+	count := 0
+	for _, run := range c.runs {
+		count += int(run.last-run.start) + 1
+	}
+	return count
+}
+
+func (c *runContainer) values(yield func(uint16) bool) bool {
+	for _, run := range c.runs {
+		for low := run.start; ; low++ {
+			if !yield(low) {
+				return false
+			}
+			if low == run.last {
+				break
+			}
+		}
+	}
+	return true
+}
+
+func (c *runContainer) words() *[bitmapWords]uint64 {
+	words := new([bitmapWords]uint64)
+	for _, run := range c.runs {
+		for low := int(run.start); low <= int(run.last); low++ {
+			words[low>>6] |= 1 << (low & 63)
+		}
+	}
+	return words
+}
+
+func (c *runContainer) sizeInBytes() int {
+	return 4 * len(c.runs)
+}
+
+func (c *runContainer) clone() container {
+	return &runContainer{slices.Clone(c.runs)}
+}
+
+// fromWords returns the smallest container of the bitmap, nil if it is empty. It takes ownership of the words.
+func fromWords(words *[bitmapWords]uint64) container {
+	count := popcount(words)
+	if count == 0 {
+		return nil
+	}
+
+	// A run starts at every set bit whose previous bit is unset
+	runs := 0
+	var carry uint64
+	for _, word := range words {
+		runs += bits.OnesCount64(word &^ (word<<1 | carry))
+		carry = word >> 63
+	}
+
+	switch {
+	case 4*runs < min(2*count, bitmapBytes):
+		c := &runContainer{runs: make([]interval, 0, runs)}
+		for low := range iterateWords(words) {
+			if n := len(c.runs); n > 0 && int(c.runs[n-1].last)+1 == int(low) {
+				c.runs[n-1].last = low
+			} else {
+				c.runs = append(c.runs, interval{start: low, last: low})
+			}
+		}
+		return c
+	case count <= arrayMaxSize:
+		c := &arrayContainer{array: make([]uint16, 0, count)}
+		for low := range iterateWords(words) {
+			c.array = append(c.array, low)
+		}
+		return c
+	}
+	return &bitmapContainer{bitmap: words, count: count}
+}
+
+// iterateWords yields the set bits of the bitmap
+func iterateWords(words *[bitmapWords]uint64) iter.Seq[uint16] {
+	return func(yield func(uint16) bool) {
+		(&bitmapContainer{bitmap: words}).values(yield)
+	}
+}
+
+func popcount(words *[bitmapWords]uint64) int {
+	count := 0
+	for _, word := range words {
+		count += bits.OnesCount64(word)
+	}
+	return count
+}
+
+// operation is a set operation between two containers
+type operation int
+
+const (
+	opAnd operation = iota
+	opOr
+	opAndNot
+	opXor
+)
+
+func (op operation) apply(a, b uint64) uint64 {
+	switch op {
+	case opAnd:
+		return a & b
+	case opOr:
+		return a | b
+	case opAndNot:
+		return a &^ b
+	}
+	return a ^ b
+}
+
+// combine returns the result of the operation in its smallest representation, nil if it is empty.
+// The containers are not modified.
+func combine(a, b container, op operation) container {
+	// Filtering an array keeps an array
+	if array, ok := a.(*arrayContainer); ok && (op == opAnd || op == opAndNot) {
+		result := &arrayContainer{}
+		for _, low := range array.array {
+			if b.contains(low) == (op == opAnd) {
+				result.array = append(result.array, low)
+			}
+		}
+		if len(result.array) == 0 {
+			return nil
+		}
+		return result
+	}
+	if array, ok := b.(*arrayContainer); ok && op == opAnd {
+		return combine(array, a, op)
+	}
+
+	words, other := a.words(), b.words()
+	for i := range words {
+		words[i] = op.apply(words[i], other[i])
+	}
+	return fromWords(words)
+}
+
+// intersectionCount returns the number of values in both containers
+func intersectionCount(a, b container) int {
+	if b.cardinality() < a.cardinality() {
+		a, b = b, a
+	}
+	if array, ok := a.(*arrayContainer); ok {
+		count := 0
+		for _, low := range array.array {
+			if b.contains(low) {
+				count++
+			}
+		}
+		return count
+	}
+
+	words, other := a.words(), b.words()
+	count := 0
+	for i := range words {
+		count += bits.OnesCount64(words[i] & other[i])
+	}
+	return count
+}
@@ -0,0 +1,367 @@
+// Package roaring implements a compressed bitmap of 32-bit values
+// (Chambi, Lemire, Kaser, Godin, "Better bitmap performance with Roaring bitmaps").
+//
+// The values are split by their high 16 bits into containers of their low 16 bits. A container is a sorted array
+// of up to 4096 values, a bitmap of 2^16 bits or a list of runs of consecutive values, whichever is smaller,
+// so the memory is proportional to the data: ten IPs take a few dozen bytes instead of the 512 MB of a full bitmap.
+package roaring
+
+import (
+	"awesomeProject/ipcounter/counters/header"
+	"encoding/binary"
+	"fmt"
+	"iter"
+	"slices"
+)
+
+// Bitmap is a compressed set of 32-bit values, it isn't safe for concurrent use
+type Bitmap struct {
+	keys       []uint16    // High 16 bits of the values of each container, in increasing order
+	containers []container // Containers of the keys
+	count      uint64
+	hashFunc   header.HashFunc
+}
+
+// New creates an empty bitmap
+func New() *Bitmap {
+	return &Bitmap{}
+}
+
+// Add adds the value and reports whether it is new
+func (b *Bitmap) Add(value uint32) bool {
+	key, low := uint16(value>>16), uint16(value)
+	i, found := slices.BinarySearch(b.keys, key)
+	if !found {
+		b.keys = slices.Insert(b.keys, i, key)
+		b.containers = slices.Insert(b.containers, i, container(&arrayContainer{array: []uint16{low}}))
+		b.count++
+		return true
+	}
+	c, added := b.containers[i].add(low)
+	b.containers[i] = c
+	if added {
+		b.count++
+	}
+	return added
+}
+
+// Contains reports whether the value was added
+func (b *Bitmap) Contains(value uint32) bool {
+	i, found := slices.BinarySearch(b.keys, uint16(value>>16))
+	return found && b.containers[i].contains(uint16(value))
+}
+
+// Count returns the number of values
+func (b *Bitmap) Count() uint64 {
+	return b.count
+}
+
+// Values yields the values in increasing order
+func (b *Bitmap) Values() iter.Seq[uint32] {
+	return func(yield func(uint32) bool) {
+		for i, c := range b.containers {
+			high := uint32(b.keys[i]) << 16
+			if !c.values(func(low uint16) bool { return yield(high | uint32(low)) }) {
+				return
+			}
+		}
+	}
+}
+
+// Clone returns a copy of the bitmap
+func (b *Bitmap) Clone() *Bitmap {
+	clone := &Bitmap{
+		keys:       slices.Clone(b.keys),
+		containers: make([]container, len(b.containers)),
+		count:      b.count,
+		hashFunc:   b.hashFunc,
+	}
+	for i, c := range b.containers {
+		clone.containers[i] = c.clone()
+	}
+	return clone
+}
+
+// RunOptimize converts every container to its smallest representation, e.g. a bitmap container
+// of consecutive values to runs. Containers are only converted by Add when they outgrow their representation.
+func (b *Bitmap) RunOptimize() {
+	for i, c := range b.containers {
+		b.containers[i] = fromWords(c.words())
+	}
+}
+
+// SizeInBytes returns the size of the values in memory, without the overhead of the containers
+func (b *Bitmap) SizeInBytes() int {
+	size := 2 * len(b.keys)
+	for _, c := range b.containers {
+		size += c.sizeInBytes()
+	}
+	return size
+}
+
+// Merge adds all values of the other bitmap, like Or
+func (b *Bitmap) Merge(other *Bitmap) error {
+	return b.Or(other)
+}
+
+// And keeps the values in both bitmaps
+func (b *Bitmap) And(other *Bitmap) error {
+	return b.combine(other, opAnd)
+}
+
+// Or adds the values of the other bitmap
+func (b *Bitmap) Or(other *Bitmap) error {
+	return b.combine(other, opOr)
+}
+
+// AndNot removes the values of the other bitmap
+func (b *Bitmap) AndNot(other *Bitmap) error {
+	return b.combine(other, opAndNot)
+}
+
+// Xor keeps the values in exactly one of the bitmaps
+func (b *Bitmap) Xor(other *Bitmap) error {
+	return b.combine(other, opXor)
+}
+
+// AndCount returns the number of values in both bitmaps without modifying them
+func (b *Bitmap) AndCount(other *Bitmap) (uint64, error) {
+	if err := b.checkCompatible(other); err != nil {
+		return 0, err
+	}
+	var count uint64
+	for i, j := 0, 0; i < len(b.keys) && j < len(other.keys); {
+		switch {
+		case b.keys[i] < other.keys[j]:
+			i++
+		case b.keys[i] > other.keys[j]:
+			j++
+		default:
+			count += uint64(intersectionCount(b.containers[i], other.containers[j]))
+			i++
+			j++
+		}
+	}
+	return count, nil
+}
+
+// OrCount returns the number of values in either bitmap without modifying them
+func (b *Bitmap) OrCount(other *Bitmap) (uint64, error) {
+	intersection, err := b.AndCount(other)
+	return b.count + other.count - intersection, err
+}
+
+// AndNotCount returns the number of values in the bitmap but not in the other one without modifying them
+func (b *Bitmap) AndNotCount(other *Bitmap) (uint64, error) {
+	intersection, err := b.AndCount(other)
+	return b.count - intersection, err
+}
+
+// XorCount returns the number of values in exactly one of the bitmaps without modifying them
+func (b *Bitmap) XorCount(other *Bitmap) (uint64, error) {
+	intersection, err := b.AndCount(other)
+	return b.count + other.count - 2*intersection, err
+}
+
+// combine replaces the containers with the operation of both bitmaps, key by key.
+// The containers of the other bitmap are copied, the bitmaps never share containers.
+func (b *Bitmap) combine(other *Bitmap, op operation) error {
+	if err := b.checkCompatible(other); err != nil {
+		return err
+	}
+
+	keys := make([]uint16, 0, len(b.keys)+len(other.keys))
+	containers := make([]container, 0, len(b.keys)+len(other.keys))
+	var count uint64
+	keep := func(key uint16, c container) {
+		if c != nil {
+			keys = append(keys, key)
+			containers = append(containers, c)
+			count += uint64(c.cardinality())
+		}
+	}
+	i, j := 0, 0
+	for i < len(b.keys) || j < len(other.keys) {
+		switch {
+		case j == len(other.keys) || i < len(b.keys) && b.keys[i] < other.keys[j]:
+			// Only in this bitmap
+			if op != opAnd {
+				keep(b.keys[i], b.containers[i])
+			}
+			i++
+		case i == len(b.keys) || b.keys[i] > other.keys[j]:
+			// Only in the other bitmap
+			if op == opOr || op == opXor {
+				keep(other.keys[j], other.containers[j].clone())
+			}
+			j++
+		default:
+			keep(b.keys[i], combine(b.containers[i], other.containers[j], op))
+			i++
+			j++
+		}
+	}
+	b.keys, b.containers, b.count = keys, containers, count
+	return nil
+}
+
+func (b *Bitmap) checkCompatible(other *Bitmap) error {
+	if b.hashFunc != other.hashFunc {
+		return fmt.Errorf("can't combine bitmaps of different hash functions: %v and %v", b.hashFunc, other.hashFunc)
+	}
+	return nil
+}
+
+// SetHashFunc records the hash function applied to the values, it is stored when the bitmap is serialized
+func (b *Bitmap) SetHashFunc(hashFunc header.HashFunc) {
+	b.hashFunc = hashFunc
+}
+
+func (b *Bitmap) HashFunc() header.HashFunc {
+	return b.hashFunc
+}
+
+// Kinds of serialized containers
+const (
+	kindArray uint8 = iota + 1
+	kindBitmap
+	kindRun
+)
+
+// MarshalBinary implements encoding.BinaryMarshaler. The payload is the number of containers followed by each
+// container: its key, its kind, and the sorted values, the 1024 words of the bitmap or the count and bounds of runs.
+func (b *Bitmap) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 0, header.Size+4+3*len(b.keys)+b.SizeInBytes())
+	data = header.Header{Type: header.TypeRoaring, HashFunc: b.hashFunc}.Append(data)
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(b.keys)))
+	for i, c := range b.containers {
+		data = binary.LittleEndian.AppendUint16(data, b.keys[i])
+		switch c := c.(type) {
+		case *arrayContainer:
+			data = append(data, kindArray)
+			data = binary.LittleEndian.AppendUint16(data, uint16(len(c.array)-1))
+			for _, low := range c.array {
+				data = binary.LittleEndian.AppendUint16(data, low)
+			}
+		case *bitmapContainer:
+			data = append(data, kindBitmap)
+			for _, word := range c.bitmap {
+				data = binary.LittleEndian.AppendUint64(data, word)
+			}
+		case *runContainer:
+			data = append(data, kindRun)
+			data = binary.LittleEndian.AppendUint16(data, uint16(len(c.runs)-1))
+			for _, run := range c.runs {
+				data = binary.LittleEndian.AppendUint16(data, run.start)
+				data = binary.LittleEndian.AppendUint16(data, run.last)
+			}
+		}
+	}
+	return data, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, the keys, values and runs must be sorted
+func (b *Bitmap) UnmarshalBinary(data []byte) error {
+	h, payload, err := header.Decode(data, header.TypeRoaring)
+	if err != nil {
+		return err
+	}
+	r := reader{data: payload}
+	n := r.uint32()
+	decoded := &Bitmap{hashFunc: h.HashFunc}
+	for i := uint32(0); i < n && r.err == nil; i++ {
+		key := r.uint16()
+		if i > 0 && key <= decoded.keys[len(decoded.keys)-1] {
+			return fmt.Errorf("container keys must be increasing, got %d after %d", key, decoded.keys[len(decoded.keys)-1])
+		}
+		c, err := r.container()
+		if r.err != nil {
+			return r.err
+		}
+		if err != nil {
+			return fmt.Errorf("invalid container %d: %w", key, err)
+		}
+		decoded.keys = append(decoded.keys, key)
+		decoded.containers = append(decoded.containers, c)
+		decoded.count += uint64(c.cardinality())
+	}
+	if r.err != nil {
+		return r.err
+	}
+	if len(decoded.keys) != int(n) {
+		return header.ErrTruncated
+	}
+	if len(r.data) != 0 {
+		return fmt.Errorf("unexpected %d bytes after the containers", len(r.data))
+	}
+	*b = *decoded
+	return nil
+}
+
+// reader decodes little endian integers, it records running out of data in err
+type reader struct {
+	data []byte
+	err  error
+}
+
+func (r *reader) next(n int) []byte {
+	if len(r.data) < n {
+		r.err = header.ErrTruncated
+		r.data = nil
+		return make([]byte, n)
+	}
+	next := r.data[:n]
+	r.data = r.data[n:]
+	return next
+}
+
+func (r *reader) uint16() uint16 {
+	return binary.LittleEndian.Uint16(r.next(2))
+}
+
+func (r *reader) uint32() uint32 {
+	return binary.LittleEndian.Uint32(r.next(4))
+}
+
+func (r *reader) container() (container, error) {
+	switch kind := r.next(1)[0]; kind {
+	case kindArray:
+		n := int(r.uint16()) + 1
+		if n > arrayMaxSize {
+			return nil, fmt.Errorf("array of %d values, must be at most %d", n, arrayMaxSize)
+		}
+		c := &arrayContainer{array: make([]uint16, n)}
+		for i := range c.array {
+			c.array[i] = r.uint16()
+			if i > 0 && c.array[i] <= c.array[i-1] && r.err == nil {
+				return nil, fmt.Errorf("array values must be increasing")
+			}
+		}
+		return c, nil
+	case kindBitmap:
+		words := new([bitmapWords]uint64)
+		for i := range words {
+			words[i] = binary.LittleEndian.Uint64(r.next(8))
+		}
+		c := newBitmapContainer(words)
+		if c.count == 0 && r.err == nil {
+			return nil, fmt.Errorf("empty bitmap")
+		}
+		return c, nil
+	case kindRun:
+		n := int(r.uint16()) + 1
+		c := &runContainer{runs: make([]interval, n)}
+		for i := range c.runs {
+			c.runs[i] = interval{start: r.uint16(), last: r.uint16()}
+			if r.err != nil {
+				break
+			}
+			if c.runs[i].last < c.runs[i].start || i > 0 && int(c.runs[i].start) <= int(c.runs[i-1].last)+1 {
+				return nil, fmt.Errorf("runs must be increasing and separated")
+			}
+		}
+		return c, nil
+	default:
+		return nil, fmt.Errorf("unknown container kind: %d", kind)
+	}
+}
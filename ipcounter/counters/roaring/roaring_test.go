@@ -0,0 +1,203 @@
+package roaring
+
+import (
+	"awesomeProject/ipcounter/counters/header"
+	"maps"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// testValues returns values filling the three kinds of containers: sparse random values,
+// a dense random container and long runs, some of them crossing a container boundary
+func testValues(seed int64) []uint32 {
+	rng := rand.New(rand.NewSource(seed))
+	var values []uint32
+	for range 3000 {
+		values = append(values, rng.Uint32())
+	}
+	for range 20000 {
+		values = append(values, 7<<16|uint32(rng.Intn(1<<16)))
+	}
+	start := uint32(rng.Intn(1 << 20))
+	for v := start; v < start+150000; v++ {
+		values = append(values, v)
+	}
+	return append(values, 0, 1<<32-1)
+}
+
+// reference builds the bitmap and the set of the values
+func reference(values []uint32) (*Bitmap, map[uint32]bool) {
+	b := New()
+	set := make(map[uint32]bool)
+	for _, v := range values {
+		if added := b.Add(v); added == set[v] {
+			panic("Add must report whether the value is new")
+		}
+		set[v] = true
+	}
+	return b, set
+}
+
+func checkBitmap(t *testing.T, b *Bitmap, set map[uint32]bool) {
+	t.Helper()
+	if b.Count() != uint64(len(set)) {
+		t.Errorf("Count() = %d, want %d", b.Count(), len(set))
+	}
+	want := slices.Sorted(maps.Keys(set))
+	if got := slices.Collect(b.Values()); !slices.Equal(got, want) {
+		t.Errorf("Values() returned %d values, want %d sorted values", len(got), len(want))
+	}
+	for _, v := range want[:min(len(want), 1000)] {
+		if !b.Contains(v) {
+			t.Errorf("Contains(%d) = false, want true", v)
+		}
+	}
+}
+
+func TestBitmap_Add(t *testing.T) {
+	values := testValues(1)
+	b, set := reference(values)
+	checkBitmap(t, b, set)
+	if b.Contains(1<<16-1) != set[1<<16-1] {
+		t.Errorf("Contains(65535) = %v, want %v", b.Contains(1<<16-1), set[1<<16-1])
+	}
+
+	// Optimizing changes the representation of the containers, not the values
+	size := b.SizeInBytes()
+	b.RunOptimize()
+	checkBitmap(t, b, set)
+	if b.SizeInBytes() >= size {
+		t.Errorf("RunOptimize() should shrink the runs, got %d bytes from %d", b.SizeInBytes(), size)
+	}
+	for _, v := range values {
+		if b.Add(v) {
+			t.Fatalf("Add(%d) of an existing value reported a new value", v)
+		}
+	}
+	checkBitmap(t, b, set)
+}
+
+func TestBitmap_RunContainer(t *testing.T) {
+	b := New()
+	for _, v := range []uint32{10, 12, 11, 14, 65535, 65534, 9, 13} {
+		b.Add(v)
+	}
+	b.RunOptimize()
+	if _, ok := b.containers[0].(*runContainer); !ok {
+		t.Fatalf("Expected a run container, got %T", b.containers[0])
+	}
+	// Adding to runs extends and joins them
+	for _, v := range []uint32{16, 15, 8, 65533, 100} {
+		b.Add(v)
+	}
+	want := []interval{{8, 16}, {100, 100}, {65533, 65535}}
+	if got := b.containers[0].(*runContainer).runs; !slices.Equal(got, want) {
+		t.Errorf("Runs = %v, want %v", got, want)
+	}
+	if b.Count() != 13 || b.containers[0].cardinality() != 13 {
+		t.Errorf("Count() = %d, want 13", b.Count())
+	}
+}
+
+func TestBitmap_SetOperations(t *testing.T) {
+	tests := []struct {
+		name    string
+		combine func(b, other *Bitmap) error
+		count   func(b, other *Bitmap) (uint64, error)
+		want    func(a, b bool) bool
+	}{
+		{"and", (*Bitmap).And, (*Bitmap).AndCount, func(a, b bool) bool { return a && b }},
+		{"or", (*Bitmap).Or, (*Bitmap).OrCount, func(a, b bool) bool { return a || b }},
+		{"andnot", (*Bitmap).AndNot, (*Bitmap).AndNotCount, func(a, b bool) bool { return a && !b }},
+		{"xor", (*Bitmap).Xor, (*Bitmap).XorCount, func(a, b bool) bool { return a != b }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, setA := reference(testValues(1))
+			other, setB := reference(testValues(2))
+			// Mix the representations of the containers
+			other.RunOptimize()
+
+			want := make(map[uint32]bool)
+			for _, set := range []map[uint32]bool{setA, setB} {
+				for v := range set {
+					if tt.want(setA[v], setB[v]) {
+						want[v] = true
+					}
+				}
+			}
+
+			count, err := tt.count(b, other)
+			if err != nil {
+				t.Fatalf("count error = %v", err)
+			}
+			if count != uint64(len(want)) {
+				t.Errorf("Count of %s = %d, want %d", tt.name, count, len(want))
+			}
+			otherCount := other.Count()
+			if err := tt.combine(b, other); err != nil {
+				t.Fatalf("combine error = %v", err)
+			}
+			checkBitmap(t, b, want)
+			checkBitmap(t, other, setB)
+			if other.Count() != otherCount {
+				t.Errorf("The other bitmap must not be modified")
+			}
+
+			hashed := New()
+			hashed.SetHashFunc(header.HashFunc{ID: header.HashFmix32})
+			if err := tt.combine(b, hashed); err == nil {
+				t.Errorf("Expected an error when combining bitmaps of different hash functions")
+			}
+		})
+	}
+}
+
+func TestBitmap_Clone(t *testing.T) {
+	b, set := reference(testValues(3))
+	clone := b.Clone()
+	clone.Add(12345)
+	clone.Add(7<<16 | 1)
+	checkBitmap(t, b, set)
+}
+
+func TestBitmap_MarshalBinary(t *testing.T) {
+	b, set := reference(testValues(4))
+	b.SetHashFunc(header.HashFunc{ID: header.HashMurmur3, Seed: 3})
+	for _, optimize := range []bool{false, true} {
+		if optimize {
+			b.RunOptimize()
+		}
+		data, err := b.MarshalBinary()
+		if err != nil {
+			t.Fatalf("MarshalBinary() error = %v", err)
+		}
+		decoded := New()
+		if err := decoded.UnmarshalBinary(data); err != nil {
+			t.Fatalf("UnmarshalBinary() error = %v", err)
+		}
+		checkBitmap(t, decoded, set)
+		if decoded.HashFunc() != b.HashFunc() {
+			t.Errorf("HashFunc() = %v, want %v", decoded.HashFunc(), b.HashFunc())
+		}
+
+		for _, n := range []int{header.Size, header.Size + 3, len(data) / 2, len(data) - 1} {
+			if err := New().UnmarshalBinary(data[:n]); err == nil {
+				t.Errorf("Expected an error for %d of %d bytes", n, len(data))
+			}
+		}
+	}
+
+	empty, _ := New().MarshalBinary()
+	decoded := New()
+	if err := decoded.UnmarshalBinary(empty); err != nil || decoded.Count() != 0 {
+		t.Errorf("UnmarshalBinary() of an empty bitmap = %v with count %d", err, decoded.Count())
+	}
+
+	unsorted := header.Header{Type: header.TypeRoaring}.Append(nil)
+	unsorted = append(unsorted, 1, 0, 0, 0, 0, 0, kindArray, 1, 0, 5, 0, 5, 0)
+	if err := New().UnmarshalBinary(unsorted); err == nil {
+		t.Errorf("Expected an error for an array with a duplicate value")
+	}
+}
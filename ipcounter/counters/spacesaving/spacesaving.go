@@ -0,0 +1,121 @@
+// Package spacesaving implements the Space-Saving heavy hitters algorithm
+// (Metwally, Agrawal, El Abbadi, "Efficient Computation of Frequent and Top-k Elements in Data Streams").
+//
+// The summary monitors at most k keys. A key that isn't monitored replaces the key with the lowest count c,
+// it inherits the count c + 1 and the error c: its true count is between count-error and count.
+// Every key seen more than N/k times of N is monitored.
+package spacesaving
+
+import (
+	"awesomeProject/ipcounter/counters/internal/topk"
+	"fmt"
+)
+
+// Entry is a monitored key, its true count is between Count-Error and Count
+type Entry = topk.Entry
+
+// Summary is the Space-Saving stream summary of k counters
+type Summary struct {
+	capacity int
+	heap     *topk.Heap
+	total    uint64
+}
+
+// New creates a summary monitoring up to k keys
+func New(k int) (*Summary, error) {
+	if k <= 0 {
+		return nil, fmt.Errorf("invalid number of counters: %d, must be positive", k)
+	}
+	return &Summary{
+		capacity: k,
+		heap:     topk.New(k),
+	}, nil
+}
+
+// Add counts one occurrence of the key
+func (s *Summary) Add(key uint32) {
+	s.total++
+	if entry, ok := s.heap.Get(key); ok {
+		entry.Count++
+		s.heap.Set(entry)
+		return
+	}
+	if s.heap.Len() < s.capacity {
+		s.heap.Set(Entry{Key: key, Count: 1})
+		return
+	}
+	evicted := s.heap.Min()
+	s.heap.ReplaceMin(Entry{Key: key, Count: evicted.Count + 1, Error: evicted.Count})
+}
+
+// Top returns the n keys with the highest counts, by decreasing count
+func (s *Summary) Top(n int) []Entry {
+	sorted := s.heap.Sorted()
+	return sorted[:min(n, len(sorted))]
+}
+
+// Total returns the number of keys added
+func (s *Summary) Total() uint64 {
+	return s.total
+}
+
+// Capacity returns the number of monitored keys k
+func (s *Summary) Capacity() int {
+	return s.capacity
+}
+
+// MaxError returns the maximum overestimation of any count, N/k
+func (s *Summary) MaxError() uint64 {
+	return s.total / uint64(s.capacity)
+}
+
+// Merge adds the other summary, both summaries must monitor the same number of keys.
+// A key missing from a full summary may have been seen up to its lowest count times, the count and the error of
+// the key are increased by it (Agarwal et al., "Mergeable Summaries"). The k highest counts are kept.
+func (s *Summary) Merge(other *Summary) error {
+	if s.capacity != other.capacity {
+		return fmt.Errorf("can't merge summaries of different sizes: %d and %d", s.capacity, other.capacity)
+	}
+
+	merged := make(map[uint32]Entry, s.heap.Len()+other.heap.Len())
+	for _, entry := range s.heap.Entries() {
+		merged[entry.Key] = entry
+	}
+	for _, entry := range other.heap.Entries() {
+		if current, ok := merged[entry.Key]; ok {
+			entry.Count += current.Count
+			entry.Error += current.Error
+		} else {
+			entry.Count += s.minCount()
+			entry.Error += s.minCount()
+		}
+		merged[entry.Key] = entry
+	}
+	for key, entry := range merged {
+		if _, ok := other.heap.Get(key); !ok {
+			entry.Count += other.minCount()
+			entry.Error += other.minCount()
+			merged[key] = entry
+		}
+	}
+
+	heap := topk.New(s.capacity)
+	for _, entry := range merged {
+		if heap.Len() < s.capacity {
+			heap.Set(entry)
+		} else if entry.Count > heap.Min().Count {
+			heap.ReplaceMin(entry)
+		}
+	}
+	s.heap = heap
+	s.total += other.total
+	return nil
+}
+
+// minCount returns the count a key that isn't monitored may have, 0 while the summary isn't full
+func (s *Summary) minCount() uint64 {
+	if s.heap.Len() < s.capacity {
+		return 0
+	}
+	return s.heap.Min().Count
+}
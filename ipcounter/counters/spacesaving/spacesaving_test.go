@@ -0,0 +1,101 @@
+package spacesaving
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+// zipfStream returns a skewed stream of keys and the true count of each key
+func zipfStream(n int, seed uint64) ([]uint32, map[uint32]uint64) {
+	rng := rand.New(rand.NewPCG(seed, 1))
+	zipf := rand.NewZipf(rng, 1.2, 1, 100000)
+	keys := make([]uint32, n)
+	counts := make(map[uint32]uint64)
+	for i := range keys {
+		keys[i] = uint32(zipf.Uint64())
+		counts[keys[i]]++
+	}
+	return keys, counts
+}
+
+func TestSpaceSavingBounds(t *testing.T) {
+	const k = 100
+	keys, counts := zipfStream(100000, 1)
+	s, _ := New(k)
+	for _, key := range keys {
+		s.Add(key)
+	}
+
+	top := s.Top(k)
+	if len(top) != k {
+		t.Fatalf("Expected %d entries, got %d", k, len(top))
+	}
+	for i, entry := range top {
+		trueCount := counts[entry.Key]
+		if entry.Count < trueCount || entry.Count-entry.Error > trueCount {
+			t.Errorf("Key %d: true count %d outside [%d, %d]", entry.Key, trueCount, entry.Count-entry.Error, entry.Count)
+		}
+		if entry.Error > s.MaxError() {
+			t.Errorf("Key %d: error %d above N/k = %d", entry.Key, entry.Error, s.MaxError())
+		}
+		if i > 0 && entry.Count > top[i-1].Count {
+			t.Errorf("Expected decreasing counts, got %d after %d", entry.Count, top[i-1].Count)
+		}
+	}
+
+	// Every key seen more than N/k times is monitored
+	monitored := make(map[uint32]bool)
+	for _, entry := range top {
+		monitored[entry.Key] = true
+	}
+	for key, count := range counts {
+		if count > s.MaxError() && !monitored[key] {
+			t.Errorf("Key %d seen %d times is not monitored", key, count)
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	const k = 100
+	first, counts := zipfStream(50000, 2)
+	second, secondCounts := zipfStream(50000, 3)
+	for key, count := range secondCounts {
+		counts[key] += count
+	}
+
+	s, _ := New(k)
+	other, _ := New(k)
+	for _, key := range first {
+		s.Add(key)
+	}
+	for _, key := range second {
+		other.Add(key)
+	}
+	if err := s.Merge(other); err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+
+	if s.Total() != 100000 {
+		t.Errorf("Expected a total of 100000, got %d", s.Total())
+	}
+	for _, entry := range s.Top(10) {
+		trueCount := counts[entry.Key]
+		if entry.Count < trueCount || entry.Count-entry.Error > trueCount {
+			t.Errorf("Key %d: true count %d outside [%d, %d]", entry.Key, trueCount, entry.Count-entry.Error, entry.Count)
+		}
+	}
+	if top := s.Top(1); top[0].Key != 0 {
+		t.Errorf("Expected key 0 to be the most frequent, got %d", top[0].Key)
+	}
+
+	otherSize, _ := New(k + 1)
+	if err := s.Merge(otherSize); err == nil {
+		t.Errorf("Expected an error when merging summaries of different sizes")
+	}
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(0); err == nil {
+		t.Errorf("Expected an error for k = 0")
+	}
+}
@@ -0,0 +1,70 @@
+package ipcounter
+
+import (
+	"awesomeProject/ipcounter/utils/zstd"
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+)
+
+// Compression formats detected by the magic bytes at the start of the input
+const (
+	compressionNone  = ""
+	compressionGzip  = "gzip"
+	compressionBzip2 = "bzip2"
+	compressionZstd  = "zstd"
+)
+
+// compressionMagicSize is the number of bytes required to detect the compression
+const compressionMagicSize = 4
+
+var (
+	gzipMagic  = []byte{0x1f, 0x8b}
+	bzip2Magic = []byte("BZh")
+	zstdMagic  = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// detectCompression returns the compression format of the data starting with the given header
+func detectCompression(header []byte) string {
+	switch {
+	case bytes.HasPrefix(header, gzipMagic):
+		return compressionGzip
+	case bytes.HasPrefix(header, bzip2Magic) && len(header) > len(bzip2Magic) &&
+		header[len(bzip2Magic)] >= '1' && header[len(bzip2Magic)] <= '9':
+		return compressionBzip2
+	case bytes.HasPrefix(header, zstdMagic):
+		return compressionZstd
+	}
+	return compressionNone
+}
+
+// NewDecompressReader detects the compression of the stream by its magic bytes and returns a decoding reader.
+// Uncompressed streams are returned as is.
+func NewDecompressReader(reader io.Reader) (io.Reader, error) {
+	buffered := bufio.NewReader(reader)
+	header, err := buffered.Peek(compressionMagicSize)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+
+	switch detectCompression(header) {
+	case compressionGzip:
+		gzipReader, err := gzip.NewReader(buffered)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create gzip reader: %w", err)
+		}
+		return gzipReader, nil
+	case compressionBzip2:
+		return bzip2.NewReader(buffered), nil
+	case compressionZstd:
+		zstdReader, err := zstd.NewReader(buffered)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create zstd reader: %w", err)
+		}
+		return zstdReader, nil
+	}
+	return buffered, nil
+}
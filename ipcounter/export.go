@@ -0,0 +1,129 @@
+package ipcounter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"iter"
+	"maps"
+	"math/bits"
+	"slices"
+)
+
+// SortedIPMap is implemented by exact counters that can list their unique IPs in increasing order
+type SortedIPMap interface {
+	IPMap
+	SortedIPs() iter.Seq[uint32]
+}
+
+// CIDR is an IPv4 prefix, e.g. 10.0.0.0/8
+type CIDR struct {
+	IP   uint32 // First IP of the prefix
+	Bits uint8  // Length of the prefix, 0 to 32
+}
+
+func (c CIDR) String() string {
+	return fmt.Sprintf("%s/%d", FormatIP(c.IP), c.Bits)
+}
+
+// Size returns the number of IPs in the prefix
+func (c CIDR) Size() uint64 {
+	return 1 << (32 - c.Bits)
+}
+
+// SortedIPs lists the set bits of the bitmap, the bitmap is already sorted
+func (m *IPBitMap) SortedIPs() iter.Seq[uint32] {
+	return func(yield func(uint32) bool) {
+		it := m.bm.Iterator()
+		for ip, ok := it.Next(); ok; ip, ok = it.Next() {
+			if !yield(ip) {
+				return
+			}
+		}
+	}
+}
+
+// SortedIPs sorts a copy of the IPs of the set
+func (m *IPSet) SortedIPs() iter.Seq[uint32] {
+	return slices.Values(slices.Sorted(maps.Keys(m.set)))
+}
+
+// SortedIPs sorts a copy of the IPs of the frequency table
+func (m *IPFrequency) SortedIPs() iter.Seq[uint32] {
+	ips := make([]uint32, 0, m.table.Len())
+	m.table.ForEach(func(ip uint32, _ uint64) {
+		ips = append(ips, ip)
+	})
+	slices.Sort(ips)
+	return slices.Values(ips)
+}
+
+// AggregateCIDRs merges runs of consecutive IPs into the minimal set of CIDR prefixes covering exactly those IPs.
+// The IPs must be sorted and unique, the prefixes are returned in increasing order.
+func AggregateCIDRs(ips iter.Seq[uint32]) iter.Seq[CIDR] {
+	return func(yield func(CIDR) bool) {
+		var first, last uint64
+		inRun := false
+		for ip := range ips {
+			if inRun && uint64(ip) == last+1 {
+				last++
+				continue
+			}
+			if inRun && !coverRange(first, last, yield) {
+				return
+			}
+			first, last, inRun = uint64(ip), uint64(ip), true
+		}
+		if inRun {
+			coverRange(first, last, yield)
+		}
+	}
+}
+
+// coverRange yields the minimal prefixes covering first to last: each prefix is the largest one aligned at
+// the current IP that doesn't go past last. It reports whether the iteration should continue.
+func coverRange(first, last uint64, yield func(CIDR) bool) bool {
+	for first <= last {
+		// The alignment of the first IP bounds the size, 0 is aligned to the whole address space
+		size := uint64(1) << 32
+		if first != 0 {
+			size = first & -first
+		}
+		for size > last-first+1 {
+			size >>= 1
+		}
+		if !yield(CIDR{IP: uint32(first), Bits: uint8(32 - bits.TrailingZeros64(size))}) {
+			return false
+		}
+		first += size
+	}
+	return true
+}
+
+// WriteIPs writes the IPs one per line
+func WriteIPs(w io.Writer, ips iter.Seq[uint32]) error {
+	buffered := bufio.NewWriter(w)
+	for ip := range ips {
+		if _, err := buffered.WriteString(FormatIP(ip) + "\n"); err != nil {
+			return fmt.Errorf("failed to write IPs: %w", err)
+		}
+	}
+	if err := buffered.Flush(); err != nil {
+		return fmt.Errorf("failed to write IPs: %w", err)
+	}
+	return nil
+}
+
+// WriteCIDRs writes the minimal CIDR prefixes covering the sorted IPs one per line
+func WriteCIDRs(w io.Writer, ips iter.Seq[uint32]) error {
+	buffered := bufio.NewWriter(w)
+	for cidr := range AggregateCIDRs(ips) {
+		if _, err := buffered.WriteString(cidr.String() + "\n"); err != nil {
+			return fmt.Errorf("failed to write CIDRs: %w", err)
+		}
+	}
+	if err := buffered.Flush(); err != nil {
+		return fmt.Errorf("failed to write CIDRs: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package ipcounter
+
+import (
+	"awesomeProject/ipcounter/counters/header"
+	"awesomeProject/ipcounter/utils/fnv1a"
+	"awesomeProject/ipcounter/utils/murmur3"
+	"encoding/binary"
+	"fmt"
+)
+
+// Hasher maps IPs to hashes before they are added to the counter.
+// The hash function is recorded in serialized counters, counters filled with different hash functions can't be merged.
+// Exact counters only count correctly with a bijection, e.g. IdentityHasher or Fmix32Hasher.
+type Hasher interface {
+	Hash(ip uint32) uint32
+	HashFunc() header.HashFunc
+}
+
+// IdentityHasher adds the IPs as is
+type IdentityHasher struct{}
+
+func (IdentityHasher) Hash(ip uint32) uint32 {
+	return ip
+}
+
+func (IdentityHasher) HashFunc() header.HashFunc {
+	return header.HashFunc{ID: header.HashNone}
+}
+
+// FNV1aHasher hashes the IPs with FNV-1a
+type FNV1aHasher struct{}
+
+func (FNV1aHasher) Hash(ip uint32) uint32 {
+	return fnv1a.HashUint32(ip)
+}
+
+func (FNV1aHasher) HashFunc() header.HashFunc {
+	return header.HashFunc{ID: header.HashFNV1a}
+}
+
+// Fmix32Hasher mixes the IPs with the murmur3 finalizer, a bijection, so it can be used with exact counters
+type Fmix32Hasher struct{}
+
+func (Fmix32Hasher) Hash(ip uint32) uint32 {
+	return murmur3.Fmix32(ip)
+}
+
+func (Fmix32Hasher) HashFunc() header.HashFunc {
+	return header.HashFunc{ID: header.HashFmix32}
+}
+
+// Murmur3Hasher hashes the 4 bytes of the IPs with the seeded 32-bit murmur3
+type Murmur3Hasher struct {
+	Seed uint32
+}
+
+func (h Murmur3Hasher) Hash(ip uint32) uint32 {
+	var data [4]byte
+	binary.BigEndian.PutUint32(data[:], ip)
+	return murmur3.Sum32WithSeed(data[:], h.Seed)
+}
+
+func (h Murmur3Hasher) HashFunc() header.HashFunc {
+	return header.HashFunc{ID: header.HashMurmur3, Seed: h.Seed}
+}
+
+// IsBijection reports whether the hasher maps distinct IPs to distinct hashes, as exact counters require
+func IsBijection(hasher Hasher) bool {
+	switch hasher.(type) {
+	case IdentityHasher, Fmix32Hasher:
+		return true
+	}
+	return false
+}
+
+// ParseHasher returns the hasher by its name: none, fnv1a, fmix32 or murmur3. The seed is only used by murmur3.
+func ParseHasher(name string, seed uint32) (Hasher, error) {
+	switch name {
+	case "none":
+		return IdentityHasher{}, nil
+	case "fnv1a":
+		return FNV1aHasher{}, nil
+	case "fmix32":
+		return Fmix32Hasher{}, nil
+	case "murmur3":
+		return Murmur3Hasher{Seed: seed}, nil
+	}
+	return nil, fmt.Errorf("invalid hash function: %s", name)
+}
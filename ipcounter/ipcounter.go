@@ -1,62 +1,373 @@
 package ipcounter
 
 import (
-	"awesomeProject/ipcounter/utils/fnv1a"
 	"bytes"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"runtime"
 	"runtime/debug"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"syscall"
+	"time"
 )
 
 // IPBatchSize The number of IPs to process in a single batch
 const IPBatchSize = 200
 
+// MmapWindowSize The number of bytes mapped at once, must be a multiple of the page size
+const MmapWindowSize = 1 << 30
+
+// ReaderChunkSize The number of bytes read from a stream before the chunk is handed to a worker
+const ReaderChunkSize = 4 << 20
+
 // IPCounter represents a structure for counting unique IP addresses
 type IPCounter struct {
-	ipMap       IPMap
-	useParallel bool
-	lock        sync.Mutex
-	useHashFunc bool
+	ipMap         IPMap
+	useParallel   bool
+	lock          sync.Mutex
+	hasher        Hasher
+	validation    ValidationPolicy
+	ipv6Map       IPv6Map
+	frequencyMap  FrequencyMap   // Counts the occurrences of the IPs next to ipMap
+	frequencyLock sync.Mutex     // Locks frequencyMap in parallel mode when it can't be forked
+	shards        []IPMap        // Private forks of ipMap, one per worker, merged into ipMap at the end of a run
+	frequencies   []FrequencyMap // Private forks of frequencyMap, one per worker, merged like shards
+	ipv6Lock      sync.Mutex     // Locks ipv6Map in parallel mode when it can't be forked
+	ipv6Shards    []IPv6Map      // Private forks of ipv6Map, one per worker, merged like shards
 }
 
-func NewIPCounter(mp IPMap, useParallel, useHashFunc bool) *IPCounter {
+// NewIPCounter creates a counter filling mp, the IPs are hashed with the hasher first.
+// A nil hasher adds the IPs as is, like IdentityHasher.
+func NewIPCounter(mp IPMap, useParallel bool, hasher Hasher) *IPCounter {
+	if hasher == nil {
+		hasher = IdentityHasher{}
+	}
+	if setter, ok := mp.(hashFuncSetter); ok {
+		setter.SetHashFunc(hasher.HashFunc())
+	}
 	return &IPCounter{
 		ipMap:       mp,
 		useParallel: useParallel,
-		useHashFunc: useHashFunc,
+		hasher:      hasher,
 	}
 }
 
-// CountIPFromFile counts unique IPs from a file
-func (counter *IPCounter) CountIPFromFile(fileName string) (uint64, error) {
+// IPMap returns the IPv4 counter, e.g. to save it
+func (counter *IPCounter) IPMap() IPMap {
+	return counter.ipMap
+}
+
+// Merge adds the IPs of another counter of the same type, e.g. one restored with UnmarshalIPMap
+func (counter *IPCounter) Merge(other IPMap) error {
+	mergeable, ok := counter.ipMap.(Mergeable)
+	if !ok {
+		return fmt.Errorf("%T doesn't support merging", counter.ipMap)
+	}
+	return mergeable.Merge(other)
+}
+
+// SetValidationPolicy enables the strict parser, ValidationNone (default) counts every line without checks
+func (counter *IPCounter) SetValidationPolicy(policy ValidationPolicy) {
+	counter.validation = policy
+}
+
+// SetIPv6Map enables IPv6 parsing, IPv6 addresses are counted in the given map.
+// IPv4-mapped addresses (::ffff:a.b.c.d) are counted as IPv4. Without the map, lines are parsed as IPv4 only.
+func (counter *IPCounter) SetIPv6Map(mp IPv6Map) {
+	counter.ipv6Map = mp
+}
+
+// SetFrequencyMap enables frequency counting, the IPv4 addresses are also added to the given map before they are hashed,
+// so a single pass yields the number of unique IPs and the most frequent ones
+func (counter *IPCounter) SetFrequencyMap(mp FrequencyMap) {
+	counter.frequencyMap = mp
+}
+
+// FrequencyMap returns the frequency counter set with SetFrequencyMap, nil if frequencies aren't counted
+func (counter *IPCounter) FrequencyMap() FrequencyMap {
+	return counter.frequencyMap
+}
+
+// CountIPFromFile counts unique IPs from a file.
+// Invalid lines are reported in a *ValidationError, with ValidationCollect it is returned together with the result.
+func (counter *IPCounter) CountIPFromFile(fileName string) (*Result, error) {
+	start := time.Now()
 	file, err := os.Open(fileName)
 	if err != nil {
-		return 0, fmt.Errorf("failed to open file: %w", err)
+		return nil, fmt.Errorf("failed to open file: %w", err)
 	}
 	defer file.Close()
 
 	fileStat, err := file.Stat()
 	if err != nil {
-		return 0, fmt.Errorf("failed to get file stat: %w", err)
+		return nil, fmt.Errorf("failed to get file stat: %w", err)
+	}
+	if !fileStat.Mode().IsRegular() {
+		// Pipes, sockets and character devices can't be memory mapped
+		return counter.CountIPFromReader(file)
 	}
 	fileSize := fileStat.Size()
-	if fileSize <= 0 || fileSize != int64(int(fileSize)) {
-		return 0, fmt.Errorf("wrong file size: %d", fileStat.Size())
+	if fileSize <= 0 {
+		return nil, fmt.Errorf("wrong file size: %d", fileStat.Size())
 	}
 
-	// TODO: split
-	err = counter.ProcessFileChunk(file, 0, int(fileStat.Size()))
+	// Compressed files are decoded on the fly and parsed by the streaming path
+	header := make([]byte, compressionMagicSize)
+	n, err := file.ReadAt(header, 0)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to read file header: %w", err)
+	}
+	if detectCompression(header[:n]) != compressionNone {
+		return counter.CountIPFromReader(file)
+	}
+
+	result, err := counter.processFilePasses(file, fileSize, MmapWindowSize)
+	return counter.finishResult(result, start, err)
+}
+
+// CountIPFromReader counts unique IPs from a stream, e.g. stdin. Compressed streams are detected and decoded.
+func (counter *IPCounter) CountIPFromReader(reader io.Reader) (*Result, error) {
+	start := time.Now()
+	if err := counter.checkSinglePass(); err != nil {
+		return nil, err
+	}
+	reader, err := NewDecompressReader(reader)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
-	return counter.ipMap.Count(), nil
+	result, err := counter.processReader(reader, ReaderChunkSize)
+	return counter.finishResult(result, start, err)
 }
 
+// finishResult computes the count unless processing failed, collected invalid lines don't discard the result
+func (counter *IPCounter) finishResult(result *ingestion, start time.Time, err error) (*Result, error) {
+	if err != nil {
+		return nil, err
+	}
+	err = result.err()
+	if err != nil && counter.validation != ValidationCollect {
+		return nil, err
+	}
+
+	countStart := time.Now()
+	result.CountIPv4 = counter.ipMap.Count()
+	if counter.ipv6Map != nil {
+		result.CountIPv6 = counter.ipv6Map.Count()
+	}
+	result.Count = result.CountIPv4 + result.CountIPv6
+	result.CountTime += time.Since(countStart)
+	result.EstimatedError = combinedError(
+		result.CountIPv4, standardError(counter.ipMap),
+		result.CountIPv6, standardError(counter.ipv6Map),
+	)
+	result.Elapsed = time.Since(start)
+	return &result.Result, err
+}
+
+// ProcessReader reads the stream in newline-aligned chunks and hands them to worker goroutines
+func (counter *IPCounter) ProcessReader(reader io.Reader) error {
+	if err := counter.checkSinglePass(); err != nil {
+		return err
+	}
+	result, err := counter.processReader(reader, ReaderChunkSize)
+	if err != nil {
+		return err
+	}
+	return result.err()
+}
+
+// readerChunk is a newline-aligned part of a stream
+type readerChunk struct {
+	data   []byte
+	offset int64 // Offset of the chunk in the stream
+	index  int   // Sequence number of the chunk, used to restore the input order of the statistics
+}
+
+func (counter *IPCounter) processReader(reader io.Reader, chunkSize int) (*ingestion, error) {
+	nWorkers := 1
+	if counter.useParallel {
+		nWorkers = runtime.GOMAXPROCS(0)
+	}
+	counter.prepareShards(nWorkers)
+
+	// Processed chunks are returned to the buffers channel, so memory usage stays bounded
+	chunks := make(chan readerChunk, nWorkers)
+	buffers := make(chan []byte, 2*nWorkers)
+	getBuffer := func() []byte {
+		select {
+		case buf := <-buffers:
+			return buf[:0]
+		default:
+			return make([]byte, 0, chunkSize)
+		}
+	}
+
+	// Workers finish in any order, the statistics are collected by chunk index
+	var statsLock sync.Mutex
+	var failed atomic.Bool
+	chunkStatsByIndex := make(map[int]chunkStats)
+
+	var wg sync.WaitGroup
+	wg.Add(nWorkers)
+	for i := 0; i < nWorkers; i++ {
+		go func(worker int) {
+			defer wg.Done()
+			for chunk := range chunks {
+				stats := counter.processChunk(chunk.data, chunk.offset, worker)
+				if counter.validation == ValidationFail && stats.invalidCount > 0 {
+					failed.Store(true)
+				}
+				statsLock.Lock()
+				chunkStatsByIndex[chunk.index] = stats
+				statsLock.Unlock()
+				select {
+				case buffers <- chunk.data:
+				default:
+				}
+			}
+		}(i)
+	}
+
+	readTime, readErr := counter.splitReader(reader, chunks, getBuffer, &failed)
+	close(chunks)
+	wg.Wait()
+	if err := counter.mergeShards(); err != nil {
+		return nil, err
+	}
+	if readErr != nil {
+		return nil, readErr
+	}
+
+	result := newIngestion(counter.validation)
+	result.ReadTime = readTime
+	result.setWorkers(nWorkers)
+	for i := 0; i < len(chunkStatsByIndex); i++ {
+		result.add(chunkStatsByIndex[i])
+	}
+	return result, nil
+}
+
+// splitReader reads the stream into buffers and sends chunks that end on a line boundary.
+// The partial line at the end of a buffer is carried over to the next one. The time spent reading is returned.
+func (counter *IPCounter) splitReader(reader io.Reader, chunks chan<- readerChunk, getBuffer func() []byte, failed *atomic.Bool) (time.Duration, error) {
+	var readTime time.Duration
+	var offset int64
+	var index int
+	buf := getBuffer()
+	for !failed.Load() {
+		readStart := time.Now()
+		n, err := io.ReadFull(reader, buf[len(buf):cap(buf)])
+		readTime += time.Since(readStart)
+		buf = buf[:len(buf)+n]
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			if len(buf) > 0 {
+				chunks <- readerChunk{data: buf, offset: offset, index: index}
+			}
+			return readTime, nil
+		}
+		if err != nil {
+			return readTime, fmt.Errorf("failed to read: %w", err)
+		}
+
+		lastNewline := bytes.LastIndexByte(buf, '\n')
+		if lastNewline == -1 {
+			// The line doesn't fit into the buffer, grow it
+			grown := make([]byte, len(buf), 2*cap(buf))
+			copy(grown, buf)
+			buf = grown
+			continue
+		}
+
+		next := append(getBuffer(), buf[lastNewline+1:]...)
+		chunks <- readerChunk{data: buf[:lastNewline+1], offset: offset, index: index}
+		offset += int64(lastNewline + 1)
+		index++
+		buf = next
+	}
+	return readTime, nil
+}
+
+// processFilePasses processes the file once per partition of a PartitionedIPMap, other counters read it once.
+// The lines are reported by the first pass, the other passes only add their bytes and times.
+func (counter *IPCounter) processFilePasses(file *os.File, fileSize int64, windowSize int) (*ingestion, error) {
+	partitioned, ok := counter.ipMap.(PartitionedIPMap)
+	if !ok || partitioned.Partitions() == 1 {
+		return counter.processFile(file, fileSize, windowSize)
+	}
+	if partitioned.Partition() != 0 {
+		return nil, fmt.Errorf("%T counts a single file, its partitions were already counted", counter.ipMap)
+	}
+	if counter.frequencyMap != nil {
+		return nil, fmt.Errorf("frequencies can't be counted with %T, every pass would add the IPs again", counter.ipMap)
+	}
+
+	result, err := counter.processFile(file, fileSize, windowSize)
+	for err == nil && !result.failed() && partitioned.Partition() < partitioned.Partitions()-1 {
+		partitioned.NextPartition()
+		var pass *ingestion
+		if pass, err = counter.processFile(file, fileSize, windowSize); err == nil {
+			result.addPass(pass)
+		}
+	}
+	return result, err
+}
+
+// checkSinglePass refuses a partitioned counter when the input can only be read once, it would count a single partition
+func (counter *IPCounter) checkSinglePass() error {
+	if partitioned, ok := counter.ipMap.(PartitionedIPMap); ok && partitioned.Partitions() > 1 {
+		return fmt.Errorf("%T reads the input once per partition, only uncompressed regular files can be counted", counter.ipMap)
+	}
+	return nil
+}
+
+// processFile walks the file in page-aligned mmap windows, so the mapped address space stays bounded.
+// The partial line at the end of a window is carried over to the next one.
+func (counter *IPCounter) processFile(file *os.File, fileSize int64, windowSize int) (*ingestion, error) {
+	result := newIngestion(counter.validation)
+	var carry []byte
+	for offset := int64(0); offset < fileSize && !result.failed(); offset += int64(windowSize) {
+		length := windowSize
+		if remaining := fileSize - offset; remaining < int64(length) {
+			length = int(remaining)
+		}
+
+		var err error
+		isLast := offset+int64(length) == fileSize
+		carry, err = counter.processWindow(file, offset, length, carry, isLast, result)
+		if err != nil {
+			_ = counter.mergeShards()
+			return nil, err
+		}
+	}
+	if err := counter.mergeShards(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ProcessFileChunk processes a chunk of the file that starts and ends on a line boundary
 func (counter *IPCounter) ProcessFileChunk(file *os.File, fileChunkOffset int64, fileChunkLength int) error {
+	if err := counter.checkSinglePass(); err != nil {
+		return err
+	}
+	result := newIngestion(counter.validation)
+	_, err := counter.processWindow(file, fileChunkOffset, fileChunkLength, nil, true, result)
+	if mergeErr := counter.mergeShards(); err == nil {
+		err = mergeErr
+	}
+	if err != nil {
+		return err
+	}
+	return result.err()
+}
+
+// processWindow maps a window of the file and processes all complete lines in it.
+// The carry is the partial line left over from the previous window, the new partial line is returned.
+func (counter *IPCounter) processWindow(file *os.File, offset int64, length int, carry []byte, isLast bool, result *ingestion) (_ []byte, err error) {
 	// Install a page fault handler, so that I/O errors against the
 	// memory map (e.g., due to disk failure) don't cause us to
 	// crash.
@@ -65,22 +376,67 @@ func (counter *IPCounter) ProcessFileChunk(file *os.File, fileChunkOffset int64,
 		debug.SetPanicOnFault(prevPanicOnFault)
 		if recover() != nil {
 			log.Print("Page fault occurred while reading from memory map")
+			err = fmt.Errorf("page fault occurred while reading from memory map at offset %d", offset)
 		}
 	}()
 
-	data, err := syscall.Mmap(int(file.Fd()), fileChunkOffset, fileChunkLength, syscall.PROT_READ, syscall.MAP_SHARED)
+	mmapStart := time.Now()
+	mapped, err := syscall.Mmap(int(file.Fd()), offset, length, syscall.PROT_READ, syscall.MAP_SHARED)
+	result.MmapTime += time.Since(mmapStart)
 	if err != nil {
-		return fmt.Errorf("failed to mmap file: %v", err)
+		return nil, fmt.Errorf("failed to mmap file: %v", err)
 	}
 	defer func() {
-		if err := syscall.Munmap(data); err != nil {
+		munmapStart := time.Now()
+		if err := syscall.Munmap(mapped); err != nil {
 			log.Printf("failed to munmap: %v", err)
 		}
+		result.MmapTime += time.Since(munmapStart)
 	}()
+	data := mapped
 
+	// Complete the line that started in the previous window
+	if len(carry) > 0 {
+		newlinePosition := bytes.IndexByte(data, '\n')
+		if newlinePosition == -1 && !isLast {
+			return append(carry, data...), nil
+		}
+		if newlinePosition == -1 {
+			newlinePosition = len(data) - 1
+		}
+		carryOffset := offset - int64(len(carry))
+		carry = append(carry, data[:newlinePosition+1]...)
+		counter.prepareShards(1)
+		result.add(counter.processChunk(carry, carryOffset, 0))
+		if result.failed() {
+			return nil, nil
+		}
+		carry = carry[:0]
+		offset += int64(newlinePosition + 1)
+		data = data[newlinePosition+1:]
+	}
+
+	// Keep the trailing partial line for the next window, it must be copied before munmap
+	if !isLast {
+		lastNewline := bytes.LastIndexByte(data, '\n')
+		carry = append(carry, data[lastNewline+1:]...)
+		data = data[:lastNewline+1]
+	}
+
+	chunkStatsList := counter.processData(data, offset)
+	result.setWorkers(len(chunkStatsList))
+	for _, stats := range chunkStatsList {
+		result.add(stats)
+	}
+	return carry, nil
+}
+
+// processData processes the data, splitting it into chunks for the workers in parallel mode.
+// The statistics of the chunks are returned in input order.
+func (counter *IPCounter) processData(data []byte, dataOffset int64) []chunkStats {
 	// Process all data in a single chunk
 	if !counter.useParallel {
-		return counter.processChunk(data)
+		return []chunkStats{counter.processChunk(data, dataOffset, 0)}
 	}
 
 	// Split data into chunkEndPositions for parallel processing
@@ -105,72 +461,228 @@ func (counter *IPCounter) ProcessFileChunk(file *os.File, fileChunkOffset int64,
 		}
 	}
 
+	counter.prepareShards(len(chunkEndPositions))
 	var wg sync.WaitGroup
 	wg.Add(len(chunkEndPositions))
-	errChan := make(chan error, chunkCount)
+	chunkStatsList := make([]chunkStats, len(chunkEndPositions))
 
 	chunkStart := 0
-	for _, chunkEnd := range chunkEndPositions {
-		go func(chunk []byte) {
+	for i, chunkEnd := range chunkEndPositions {
+		go func(i int, chunk []byte, chunkOffset int64) {
 			defer wg.Done()
-			if chunkErr := counter.processChunk(chunk); chunkErr != nil {
-				errChan <- chunkErr
-			}
-		}(data[chunkStart:chunkEnd])
+			chunkStatsList[i] = counter.processChunk(chunk, chunkOffset, i)
+		}(i, data[chunkStart:chunkEnd], dataOffset+int64(chunkStart))
 		chunkStart = chunkEnd
 	}
 
 	wg.Wait()
-	close(errChan)
-	for err = range errChan {
-		if err != nil {
-			// TODO: merge errors
-			return err
-		}
-	}
-	return nil
+	return chunkStatsList
 }
 
-// processChunk processes a chunk of data and counts IPs
-func (counter *IPCounter) processChunk(data []byte) error {
+// processChunk processes a chunk of data and counts IPs, dataOffset is the offset of the chunk in the input.
+// The worker selects the shard the IPs are added to in parallel mode.
+func (counter *IPCounter) processChunk(data []byte, dataOffset int64, worker int) chunkStats {
+	start := time.Now()
+	stats := chunkStats{bytes: int64(len(data))}
 	ipBatch := make([]uint32, 0, IPBatchSize)
+	var ipv6Batch []IPv6
 
+	lineOffset := dataOffset
 	for len(data) > 0 {
 		endOfLine := bytes.IndexByte(data, '\n')
 		if endOfLine == -1 {
 			endOfLine = len(data)
 		}
+		line := data[:endOfLine]
 
-		ip := parseIP(data[:endOfLine])
-		ipBatch = append(ipBatch, ip)
+		stats.lines++
+		var err error
+		switch {
+		case counter.ipv6Map != nil && bytes.IndexByte(line, ':') != -1:
+			// IPv6 lines are always parsed strictly, there is no meaningful way to count a broken address
+			var ipv6 IPv6
+			if ipv6, err = parseIPv6(line); err == nil {
+				if ip, ok := ipv6.To4(); ok {
+					ipBatch = append(ipBatch, ip)
+				} else {
+					ipv6Batch = append(ipv6Batch, ipv6)
+				}
+			}
+		case counter.validation == ValidationNone:
+			ipBatch = append(ipBatch, parseIP(line))
+		default:
+			var ip uint32
+			if ip, err = parseIPStrict(line); err == nil {
+				ipBatch = append(ipBatch, ip)
+			}
+		}
+		if err != nil {
+			stats.addInvalid(line, lineOffset, err)
+			if counter.validation == ValidationFail {
+				break
+			}
+		}
 		if len(ipBatch) == IPBatchSize {
-			counter.addIPBatch(ipBatch)
+			counter.flushIPBatch(ipBatch, worker, &stats)
 			ipBatch = ipBatch[:0]
 		}
+		if len(ipv6Batch) == IPBatchSize {
+			counter.flushIPv6Batch(ipv6Batch, worker, &stats)
+			ipv6Batch = ipv6Batch[:0]
+		}
 
 		if endOfLine == len(data) {
 			break
 		}
 		data = data[endOfLine+1:]
+		lineOffset += int64(endOfLine + 1)
 	}
 
 	if len(ipBatch) > 0 {
-		counter.addIPBatch(ipBatch)
+		counter.flushIPBatch(ipBatch, worker, &stats)
+	}
+	if len(ipv6Batch) > 0 {
+		counter.flushIPv6Batch(ipv6Batch, worker, &stats)
+	}
+	stats.parseTime = time.Since(start) - stats.countTime
+	return stats
+}
+
+// flushIPBatch adds the batch to the counter and records the time spent
+func (counter *IPCounter) flushIPBatch(ips []uint32, worker int, stats *chunkStats) {
+	start := time.Now()
+	counter.addIPBatch(ips, worker)
+	stats.countTime += time.Since(start)
+	stats.valid += uint64(len(ips))
+}
+
+// addIPBatch adds a batch of IPs to the counter. In parallel mode the IPs go to a counter safe for concurrent use
+// without locking, to the shard of the worker or to a counter that synchronizes itself.
+// Only other counters are locked as a whole.
+// The batch is hashed in place.
+func (counter *IPCounter) addIPBatch(ips []uint32, worker int) {
+	if counter.frequencyMap != nil {
+		counter.addFrequencyBatch(ips, worker)
+	}
+	if _, ok := counter.hasher.(IdentityHasher); !ok {
+		for i, ip := range ips {
+			ips[i] = counter.hasher.Hash(ip)
+		}
+	}
+
+	mp := counter.ipMap
+	if counter.useParallel {
+		if _, ok := mp.(ConcurrentIPMap); ok {
+			for _, ip := range ips {
+				mp.Add(ip)
+			}
+			return
+		}
+		if batchAdder, ok := mp.(BatchAdder); ok {
+			batchAdder.AddBatch(ips)
+			return
+		}
+		if counter.shards != nil {
+			mp = counter.shards[worker]
+		} else {
+			counter.lock.Lock()
+			defer counter.lock.Unlock()
+		}
+	}
+	for _, ip := range ips {
+		mp.Add(ip)
+	}
+}
+
+// prepareShards makes sure there is a private fork of the counter, of the frequency counter and of the IPv6 counter
+// for each of n workers.
+// Counters that aren't Forkable are shared by the workers.
+func (counter *IPCounter) prepareShards(n int) {
+	if !counter.useParallel {
+		return
+	}
+	if forkable, ok := counter.ipMap.(Forkable); ok {
+		for len(counter.shards) < n {
+			counter.shards = append(counter.shards, forkable.Fork())
+		}
+	}
+	if forkable, ok := counter.frequencyMap.(ForkableFrequencyMap); ok {
+		for len(counter.frequencies) < n {
+			counter.frequencies = append(counter.frequencies, forkable.ForkFrequencyMap())
+		}
+	}
+	if forkable, ok := counter.ipv6Map.(ForkableIPv6Map); ok {
+		for len(counter.ipv6Shards) < n {
+			fork := forkable.Fork()
+			if fork == nil {
+				break
+			}
+			counter.ipv6Shards = append(counter.ipv6Shards, fork)
+		}
+	}
+}
+
+// mergeShards merges the forks of the workers into the counters, new forks are created for the next run
+func (counter *IPCounter) mergeShards() error {
+	shards, frequencies, ipv6Shards := counter.shards, counter.frequencies, counter.ipv6Shards
+	counter.shards, counter.frequencies, counter.ipv6Shards = nil, nil, nil
+	for _, shard := range shards {
+		if err := counter.ipMap.(Mergeable).Merge(shard); err != nil {
+			return fmt.Errorf("failed to merge worker counter: %w", err)
+		}
+	}
+	for _, fork := range frequencies {
+		if err := counter.frequencyMap.(ForkableFrequencyMap).MergeFrequencyMap(fork); err != nil {
+			return fmt.Errorf("failed to merge worker frequency counter: %w", err)
+		}
+	}
+	for _, fork := range ipv6Shards {
+		if err := counter.ipv6Map.(ForkableIPv6Map).Merge(fork); err != nil {
+			return fmt.Errorf("failed to merge worker IPv6 counter: %w", err)
+		}
 	}
 	return nil
 }
 
-// addIPBatch adds a batch of IPs to the counter
-func (counter *IPCounter) addIPBatch(ips []uint32) {
+// addFrequencyBatch adds a batch of IPs to the frequency counter, in parallel mode to the fork of the worker
+// or under the lock when the frequency counter can't be forked
+func (counter *IPCounter) addFrequencyBatch(ips []uint32, worker int) {
+	mp := counter.frequencyMap
 	if counter.useParallel {
-		counter.lock.Lock()
-		defer counter.lock.Unlock()
+		if counter.frequencies != nil {
+			mp = counter.frequencies[worker]
+		} else {
+			counter.frequencyLock.Lock()
+			defer counter.frequencyLock.Unlock()
+		}
 	}
 	for _, ip := range ips {
-		if counter.useHashFunc {
-			ip = fnv1a.HashUint32(ip)
+		mp.Add(ip)
+	}
+}
+
+// flushIPv6Batch adds the batch to the IPv6 counter and records the time spent
+func (counter *IPCounter) flushIPv6Batch(ips []IPv6, worker int, stats *chunkStats) {
+	start := time.Now()
+	counter.addIPv6Batch(ips, worker)
+	stats.countTime += time.Since(start)
+	stats.valid += uint64(len(ips))
+}
+
+// addIPv6Batch adds a batch of IPv6 addresses to the IPv6 counter, in parallel mode to the fork of the worker
+// or under the IPv6 lock when the IPv6 counter can't be forked
+func (counter *IPCounter) addIPv6Batch(ips []IPv6, worker int) {
+	mp := counter.ipv6Map
+	if counter.useParallel {
+		if counter.ipv6Shards != nil {
+			mp = counter.ipv6Shards[worker]
+		} else {
+			counter.ipv6Lock.Lock()
+			defer counter.ipv6Lock.Unlock()
 		}
-		counter.ipMap.Add(ip)
+	}
+	for _, ip := range ips {
+		mp.Add(ip)
 	}
 }
 
@@ -190,8 +702,20 @@ func parseIP(data []byte) uint32 {
 	return (ip << 8) | uint32(octet)
 }
 
+// FormatIP returns the dotted decimal form of a uint32 IP
+func FormatIP(ip uint32) string {
+	b := make([]byte, 0, len("255.255.255.255"))
+	for shift := 24; shift >= 0; shift -= 8 {
+		if shift != 24 {
+			b = append(b, '.')
+		}
+		b = strconv.AppendUint(b, uint64(ip>>shift&0xFF), 10)
+	}
+	return string(b)
+}
+
 func getChunkSize(dataLength int) (int, int) {
-	nChunks := runtime.NumCPU()
+	nChunks := runtime.GOMAXPROCS(0)
 	chunkSize := dataLength / nChunks
 	if chunkSize == 0 {
 		chunkSize = dataLength
@@ -6,7 +6,7 @@ import (
 
 func BenchmarkSeqBitMapCounter(b *testing.B) {
 	mp, _ := NewIPBitMap()
-	ic := NewIPCounter(mp, false, false)
+	ic := NewIPCounter(mp, false, nil)
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -16,7 +16,7 @@ func BenchmarkSeqBitMapCounter(b *testing.B) {
 
 func BenchmarkParallelBitMapCounter(b *testing.B) {
 	mp, _ := NewIPBitMap()
-	ic := NewIPCounter(mp, true, false)
+	ic := NewIPCounter(mp, true, nil)
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -26,7 +26,7 @@ func BenchmarkParallelBitMapCounter(b *testing.B) {
 
 func BenchmarkParallelHyperLogLog(b *testing.B) {
 	mp, _ := NewHyperLogLog(14)
-	ic := NewIPCounter(mp, true, true)
+	ic := NewIPCounter(mp, true, FNV1aHasher{})
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -36,7 +36,7 @@ func BenchmarkParallelHyperLogLog(b *testing.B) {
 
 func BenchmarkParallelHyperLogLogPlus(b *testing.B) {
 	mp, _ := NewHyperLogLogPlus(14)
-	ic := NewIPCounter(mp, true, true)
+	ic := NewIPCounter(mp, true, FNV1aHasher{})
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -46,10 +46,39 @@ func BenchmarkParallelHyperLogLogPlus(b *testing.B) {
 
 func BenchmarkParallelHyperLogLogPlusBitMpa(b *testing.B) {
 	mp, _ := NewHyperLogLogPlusBitMap(14)
-	ic := NewIPCounter(mp, true, true)
+	ic := NewIPCounter(mp, true, FNV1aHasher{})
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		_, _ = ic.CountIPFromFile("./ipsbig")
 	}
 }
+
+// BenchmarkParallelScaling compares the counters in parallel mode, run with -cpu 1,2,4,8 to see the scaling.
+// The number of workers follows GOMAXPROCS.
+func BenchmarkParallelScaling(b *testing.B) {
+	counters := []struct {
+		name  string
+		newFn func() (IPMap, error)
+	}{
+		{"BitMap", NewIPBitMap},
+		{"HyperLogLog", func() (IPMap, error) { return NewHyperLogLog(14) }},
+		{"HyperLogLogPlus", func() (IPMap, error) { return NewHyperLogLogPlus(14) }},
+		{"HyperLogLogPlusBitMap", func() (IPMap, error) { return NewHyperLogLogPlusBitMap(14) }},
+	}
+
+	for _, c := range counters {
+		b.Run(c.name, func(b *testing.B) {
+			mp, err := c.newFn()
+			if err != nil {
+				b.Fatal(err)
+			}
+			ic := NewIPCounter(mp, true, FNV1aHasher{})
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_, _ = ic.CountIPFromFile("./ipsbig")
+			}
+		})
+	}
+}
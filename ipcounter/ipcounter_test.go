@@ -1,7 +1,20 @@
 package ipcounter
 
 import (
+	"awesomeProject/ipcounter/counters/bitmap"
+	"awesomeProject/ipcounter/counters/header"
+	"awesomeProject/ipcounter/utils/fnv1a"
+	"bytes"
+	"compress/gzip"
+	"encoding"
+	"errors"
+	"fmt"
+	"math"
 	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -57,15 +70,15 @@ func TestCountIPFromFile(t *testing.T) {
 
 			// Create counter and count IPs
 			mockMap := NewMockIPMap()
-			counter := NewIPCounter(mockMap, false, true)
-			count, err := counter.CountIPFromFile(tmpfile.Name())
+			counter := NewIPCounter(mockMap, false, FNV1aHasher{})
+			result, err := counter.CountIPFromFile(tmpfile.Name())
 
 			// Check results
 			if err != nil {
 				t.Fatalf("Unexpected error: %v", err)
 			}
-			if count != tc.expected {
-				t.Errorf("Expected count %d, got %d", tc.expected, count)
+			if result.Count != tc.expected {
+				t.Errorf("Expected count %d, got %d", tc.expected, result.Count)
 			}
 		})
 	}
@@ -91,13 +104,13 @@ func TestParseIP(t *testing.T) {
 
 func TestProcessChunk(t *testing.T) {
 	mockMap := NewMockIPMap()
-	counter := NewIPCounter(mockMap, false, true)
+	counter := NewIPCounter(mockMap, false, FNV1aHasher{})
 
 	chunk := []byte("192.168.0.1\n10.0.0.1\n192.168.0.1\n")
-	err := counter.processChunk(chunk)
+	stats := counter.processChunk(chunk, 0, 0)
 
-	if err != nil {
-		t.Fatalf("Unexpected error: %v", err)
+	if stats.lines != 3 {
+		t.Fatalf("Expected 3 lines, got %d", stats.lines)
 	}
 
 	if mockMap.Count() != 2 {
@@ -107,12 +120,1259 @@ func TestProcessChunk(t *testing.T) {
 
 func TestAddIPBatch(t *testing.T) {
 	mockMap := NewMockIPMap()
-	counter := NewIPCounter(mockMap, false, true)
+	counter := NewIPCounter(mockMap, false, FNV1aHasher{})
 
 	ips := []uint32{3232235521, 167772161, 3232235521} // 192.168.0.1, 10.0.0.1, 192.168.0.1
-	counter.addIPBatch(ips)
+	counter.addIPBatch(ips, 0)
 
 	if mockMap.Count() != 2 {
 		t.Errorf("Expected 2 unique IPs, got %d", mockMap.Count())
 	}
 }
+
+func TestCountIPFromReader(t *testing.T) {
+	testCases := []struct {
+		name        string
+		content     string
+		chunkSize   int
+		useParallel bool
+		expected    uint64
+	}{
+		{"Empty", "", 16, false, 0},
+		{"Single IP without newline", "192.168.0.1", 16, false, 1},
+		{"Multiple IPs", "192.168.0.1\n10.0.0.1\n192.168.0.1\n", 16, false, 2},
+		{"Line longer than chunk", "192.168.0.1\n10.0.0.1\n", 4, false, 2},
+		{"Parallel", "192.168.0.1\n10.0.0.1\n172.16.0.1\n10.0.0.1\n1.2.3.4\n", 12, true, 4},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockMap := NewMockIPMap()
+			counter := NewIPCounter(mockMap, tc.useParallel, nil)
+			if _, err := counter.processReader(strings.NewReader(tc.content), tc.chunkSize); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if count := mockMap.Count(); count != tc.expected {
+				t.Errorf("Expected count %d, got %d", tc.expected, count)
+			}
+			if _, exists := mockMap.ips[parseIP([]byte("192.168.0.1"))]; tc.expected > 0 && !exists {
+				t.Errorf("Expected 192.168.0.1 to be counted")
+			}
+		})
+	}
+}
+
+func TestProcessFileWindows(t *testing.T) {
+	// Lines of different length, so they cross the window boundaries at different positions
+	var content strings.Builder
+	expected := make(map[uint32]struct{})
+	for i := 0; i < 5000; i++ {
+		ip := fmt.Sprintf("%d.%d.%d.%d", i%256, (i*7)%256, (i*13)%256, i%3)
+		content.WriteString(ip + "\n")
+		expected[parseIP([]byte(ip))] = struct{}{}
+	}
+
+	tmpfile, err := os.CreateTemp("", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tmpfile.Name())
+	if _, err := tmpfile.WriteString(content.String()); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, useParallel := range []bool{false, true} {
+		t.Run(fmt.Sprintf("Parallel %v", useParallel), func(t *testing.T) {
+			mockMap := NewMockIPMap()
+			counter := NewIPCounter(mockMap, useParallel, nil)
+			if _, err := counter.processFile(tmpfile, int64(content.Len()), os.Getpagesize()); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if count := mockMap.Count(); count != uint64(len(expected)) {
+				t.Errorf("Expected count %d, got %d", len(expected), count)
+			}
+			for ip := range mockMap.ips {
+				if _, exists := expected[ip]; !exists {
+					t.Errorf("Unexpected IP %d, a line was split at a window boundary", ip)
+				}
+			}
+		})
+	}
+}
+
+func TestCountIPFromCompressedFile(t *testing.T) {
+	// The fixtures contain the first 3000 lines of ipsbig
+	var gzipped bytes.Buffer
+	plain, err := os.ReadFile("./ipsbig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gzipWriter := gzip.NewWriter(&gzipped)
+	if _, err := gzipWriter.Write(plain); err != nil {
+		t.Fatal(err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	gzipFile := filepath.Join(t.TempDir(), "ips.gz")
+	if err := os.WriteFile(gzipFile, gzipped.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name     string
+		fileName string
+	}{
+		{"Plain", "./ipsbig"},
+		{"Gzip", gzipFile},
+		{"Bzip2", "./testdata/ips.bz2"},
+		{"Zstd", "./testdata/ips.zst"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockMap := NewMockIPMap()
+			counter := NewIPCounter(mockMap, true, nil)
+			result, err := counter.CountIPFromFile(tc.fileName)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result.Count != 1000 {
+				t.Errorf("Expected count %d, got %d", 1000, result.Count)
+			}
+		})
+	}
+}
+
+func TestParseIPStrict(t *testing.T) {
+	testCases := []struct {
+		input       string
+		expected    uint32
+		expectedErr error
+	}{
+		{"192.168.0.1", 3232235521, nil},
+		{"0.0.0.0", 0, nil},
+		{"255.255.255.255", 4294967295, nil},
+		{"10.0.0.1\r", 167772161, nil},
+		{"", 0, ErrEmptyLine},
+		{"abc", 0, ErrInvalidCharacter},
+		{" 1.2.3.4", 0, ErrInvalidCharacter},
+		{"1.2.3.4 ", 0, ErrInvalidCharacter},
+		{"300.1.1.1", 0, ErrInvalidOctet},
+		{"0001.1.1.1", 0, ErrInvalidOctet},
+		{"1.2.3", 0, ErrOctetCount},
+		{"1.2.3.4.5", 0, ErrOctetCount},
+		{"1..3.4", 0, ErrEmptyOctet},
+		{"1.2.3.", 0, ErrEmptyOctet},
+		{"2001:db8::1", 0, ErrIPv6Address},
+		{"::ffff:1.2.3.4", 0, ErrIPv6Address},
+	}
+
+	for _, tc := range testCases {
+		result, err := parseIPStrict([]byte(tc.input))
+		if !errors.Is(err, tc.expectedErr) {
+			t.Errorf("For input %q, expected error %v, got %v", tc.input, tc.expectedErr, err)
+		}
+		if result != tc.expected {
+			t.Errorf("For input %q, expected %d, got %d", tc.input, tc.expected, result)
+		}
+	}
+}
+
+func TestValidationPolicy(t *testing.T) {
+	content := "192.168.0.1\nabc\n10.0.0.1\n300.1.1.1\n\n1.2.3.4\n"
+
+	testCases := []struct {
+		name          string
+		policy        ValidationPolicy
+		expected      uint64
+		expectedLines []uint64
+	}{
+		{"None", ValidationNone, 6, nil},
+		{"Fail", ValidationFail, 0, []uint64{2}},
+		{"Skip", ValidationSkip, 3, nil},
+		{"Collect", ValidationCollect, 3, []uint64{2, 4, 5}},
+	}
+
+	for _, tc := range testCases {
+		for _, useParallel := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s parallel %v", tc.name, useParallel), func(t *testing.T) {
+				mockMap := NewMockIPMap()
+				counter := NewIPCounter(mockMap, useParallel, nil)
+				counter.SetValidationPolicy(tc.policy)
+				result, err := counter.CountIPFromReader(strings.NewReader(content))
+				if count := mockMap.Count(); result != nil && count != tc.expected {
+					t.Errorf("Expected count %d, got %d", tc.expected, count)
+				}
+				if (result == nil) != (tc.policy == ValidationFail) {
+					t.Errorf("Expected the result only if counting didn't fail, got %v", result)
+				}
+
+				var validationErr *ValidationError
+				if tc.expectedLines == nil {
+					if err != nil {
+						t.Fatalf("Unexpected error: %v", err)
+					}
+					return
+				}
+				if !errors.As(err, &validationErr) {
+					t.Fatalf("Expected validation error, got %v", err)
+				}
+				if validationErr.Count != uint64(len(tc.expectedLines)) {
+					t.Errorf("Expected %d invalid lines, got %d", len(tc.expectedLines), validationErr.Count)
+				}
+				for i, line := range validationErr.Lines {
+					if line.Line != tc.expectedLines[i] {
+						t.Errorf("Expected invalid line %d, got %d", tc.expectedLines[i], line.Line)
+					}
+				}
+				if offset := validationErr.Lines[0].Offset; offset != 12 {
+					t.Errorf("Expected offset 12, got %d", offset)
+				}
+			})
+		}
+	}
+}
+
+func TestValidationIPv6WithoutIPv6Map(t *testing.T) {
+	counter := NewIPCounter(NewMockIPMap(), false, nil)
+	counter.SetValidationPolicy(ValidationCollect)
+	_, err := counter.CountIPFromReader(strings.NewReader("10.0.0.1\n2001:db8::1\n"))
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Count != 1 {
+		t.Fatalf("Expected one invalid line, got %v", err)
+	}
+	if !errors.Is(validationErr.Lines[0].Err, ErrIPv6Address) {
+		t.Errorf("Expected %v, got %v", ErrIPv6Address, validationErr.Lines[0].Err)
+	}
+
+	counter = NewIPCounter(NewMockIPMap(), false, nil)
+	counter.SetValidationPolicy(ValidationCollect)
+	counter.SetIPv6Map(NewIPv6Set())
+	if _, err := counter.CountIPFromReader(strings.NewReader("10.0.0.1\n2001:db8::1\n")); err != nil {
+		t.Errorf("Unexpected error with an IPv6 counter: %v", err)
+	}
+}
+
+func TestValidationLineNumbers(t *testing.T) {
+	// Invalid lines in different windows and chunks must get absolute line numbers and offsets
+	var content strings.Builder
+	var expectedLines []uint64
+	var expectedOffsets []int64
+	for i := 1; i <= 5000; i++ {
+		if i%777 == 0 {
+			expectedLines = append(expectedLines, uint64(i))
+			expectedOffsets = append(expectedOffsets, int64(content.Len()))
+			content.WriteString("invalid\n")
+			continue
+		}
+		content.WriteString(fmt.Sprintf("10.0.%d.%d\n", i/256, i%256))
+	}
+
+	tmpfile, err := os.CreateTemp("", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tmpfile.Name())
+	if _, err := tmpfile.WriteString(content.String()); err != nil {
+		t.Fatal(err)
+	}
+
+	checkErr := func(t *testing.T, result *ingestion, err error) {
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		err = result.err()
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("Expected validation error, got %v", err)
+		}
+		if len(validationErr.Lines) != len(expectedLines) {
+			t.Fatalf("Expected %d invalid lines, got %d", len(expectedLines), len(validationErr.Lines))
+		}
+		for i, line := range validationErr.Lines {
+			if line.Line != expectedLines[i] || line.Offset != expectedOffsets[i] {
+				t.Errorf("Expected line %d at offset %d, got line %d at offset %d",
+					expectedLines[i], expectedOffsets[i], line.Line, line.Offset)
+			}
+		}
+	}
+
+	for _, useParallel := range []bool{false, true} {
+		t.Run(fmt.Sprintf("File parallel %v", useParallel), func(t *testing.T) {
+			counter := NewIPCounter(NewMockIPMap(), useParallel, nil)
+			counter.SetValidationPolicy(ValidationCollect)
+			result, err := counter.processFile(tmpfile, int64(content.Len()), os.Getpagesize())
+			checkErr(t, result, err)
+		})
+		t.Run(fmt.Sprintf("Reader parallel %v", useParallel), func(t *testing.T) {
+			counter := NewIPCounter(NewMockIPMap(), useParallel, nil)
+			counter.SetValidationPolicy(ValidationCollect)
+			result, err := counter.processReader(strings.NewReader(content.String()), 1000)
+			checkErr(t, result, err)
+		})
+	}
+}
+
+func TestCountIPResult(t *testing.T) {
+	content := "192.168.0.1\nabc\n10.0.0.1\n192.168.0.1\n"
+
+	mockMap := NewMockIPMap()
+	counter := NewIPCounter(mockMap, false, nil)
+	counter.SetValidationPolicy(ValidationSkip)
+	result, err := counter.CountIPFromReader(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := Result{Count: 2, CountIPv4: 2, Lines: 4, ValidIPs: 3, InvalidLines: 1, Bytes: int64(len(content)), Chunks: 1, Workers: 1, Passes: 1}
+	actual := *result
+	actual.MmapTime, actual.ReadTime, actual.ParseTime, actual.CountTime, actual.Elapsed = 0, 0, 0, 0, 0
+	if actual != expected {
+		t.Errorf("Expected result %v, got %v", &expected, &actual)
+	}
+	if result.Elapsed <= 0 {
+		t.Errorf("Expected elapsed time to be measured")
+	}
+}
+
+func TestParseIPv6(t *testing.T) {
+	testCases := []struct {
+		input       string
+		expected    IPv6
+		expectedErr error
+	}{
+		{"2001:db8:0:0:0:0:0:1", IPv6{0x20, 0x01, 0x0d, 0xb8, 15: 1}, nil},
+		{"2001:DB8::1", IPv6{0x20, 0x01, 0x0d, 0xb8, 15: 1}, nil},
+		{"::", IPv6{}, nil},
+		{"::1", IPv6{15: 1}, nil},
+		{"fe80::", IPv6{0xfe, 0x80}, nil},
+		{"1:2:3:4:5:6:7::", IPv6{1: 1, 3: 2, 5: 3, 7: 4, 9: 5, 11: 6, 13: 7}, nil},
+		{"::ffff:10.0.0.1", IPv6{10: 0xff, 11: 0xff, 12: 10, 15: 1}, nil},
+		{"1:2:3:4:5:6:1.2.3.4", IPv6{1: 1, 3: 2, 5: 3, 7: 4, 9: 5, 11: 6, 12: 1, 13: 2, 14: 3, 15: 4}, nil},
+		{"::1\r", IPv6{15: 1}, nil},
+		{"", IPv6{}, ErrEmptyLine},
+		{"1:2:3:4:5:6:7", IPv6{}, ErrGroupCount},
+		{"1:2:3:4:5:6:7:8:9", IPv6{}, ErrGroupCount},
+		{"1:2:3:4::5:6:7:8", IPv6{}, ErrGroupCount},
+		{"1::2::3", IPv6{}, ErrMultipleEllipsis},
+		{"12345::", IPv6{}, ErrInvalidGroup},
+		{":1:2", IPv6{}, ErrInvalidGroup},
+		{"1:", IPv6{}, ErrInvalidGroup},
+		{"fe80::1%eth0", IPv6{}, ErrInvalidCharacter},
+		{"g::", IPv6{}, ErrInvalidGroup},
+		{"1:2:3:4:5:1.2.3.4", IPv6{}, ErrGroupCount},
+		{"::ffff:300.0.0.1", IPv6{}, ErrInvalidOctet},
+	}
+
+	for _, tc := range testCases {
+		result, err := parseIPv6([]byte(tc.input))
+		if !errors.Is(err, tc.expectedErr) {
+			t.Errorf("For input %q, expected error %v, got %v", tc.input, tc.expectedErr, err)
+		}
+		if result != tc.expected {
+			t.Errorf("For input %q, expected %x, got %x", tc.input, tc.expected, result)
+		}
+	}
+}
+
+func TestCountIPv6(t *testing.T) {
+	content := "192.168.0.1\n2001:db8::1\n2001:db8:0::1\n::ffff:192.168.0.1\n::1\n10.0.0.1\n::ffff:1.2.3.4\n1::2::3\n"
+
+	for _, policy := range []ValidationPolicy{ValidationNone, ValidationSkip} {
+		counter := NewIPCounter(NewMockIPMap(), false, nil)
+		counter.SetValidationPolicy(policy)
+		counter.SetIPv6Map(NewIPv6Set())
+		result, err := counter.CountIPFromReader(strings.NewReader(content))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.CountIPv4 != 3 || result.CountIPv6 != 2 || result.Count != 5 {
+			t.Errorf("Expected 3 IPv4 and 2 IPv6 addresses, got %v", result)
+		}
+		if result.ValidIPs != 7 || result.InvalidLines != 1 {
+			t.Errorf("Expected 7 valid and 1 invalid line, got %v", result)
+		}
+	}
+
+	counter := NewIPCounter(NewMockIPMap(), false, nil)
+	counter.SetValidationPolicy(ValidationFail)
+	if _, err := counter.CountIPFromReader(strings.NewReader(content)); err == nil {
+		t.Errorf("Expected IPv6 lines to be rejected without an IPv6 map")
+	}
+}
+
+func TestCountIPv6Parallel(t *testing.T) {
+	// Force several workers on machines with few cores
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+
+	var content strings.Builder
+	for i := 0; i < 50000; i++ {
+		fmt.Fprintf(&content, "10.0.0.%d\n2001:db8::%x\n", i%200, i%3000)
+	}
+
+	set, _ := NewSet()
+	ipv6Set := NewIPv6Set()
+	counter := NewIPCounter(set, true, nil)
+	counter.SetIPv6Map(ipv6Set)
+	if _, err := counter.processReader(strings.NewReader(content.String()), 4096); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if set.Count() != 200 || ipv6Set.Count() != 3000 {
+		t.Errorf("Expected 200 IPv4 and 3000 IPv6 addresses, got %d and %d", set.Count(), ipv6Set.Count())
+	}
+	if counter.ipv6Shards != nil {
+		t.Errorf("Expected the IPv6 forks to be merged after the run")
+	}
+
+	// The HyperLogLog++ sketch is forked, the concurrent sketch can't be and is shared under the IPv6 lock
+	hyperLogLogPlus, _ := NewHyperLogLogPlus(14)
+	concurrent, _ := NewConcurrentHyperLogLog(14)
+	for _, sketch := range []HashIPMap{hyperLogLogPlus, concurrent} {
+		ipv6Sketch := NewIPv6Sketch(sketch)
+		counter := NewIPCounter(NewMockIPMap(), true, nil)
+		counter.SetIPv6Map(ipv6Sketch)
+		if _, err := counter.processReader(strings.NewReader(content.String()), 4096); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if count := ipv6Sketch.Count(); math.Abs(float64(count)-3000) > 60 {
+			t.Errorf("Expected about 3000 IPv6 addresses with %T, got %d", sketch, count)
+		}
+	}
+}
+
+func TestIPv6Sketch(t *testing.T) {
+	sketch, err := NewHyperLogLog(14)
+	if err != nil {
+		t.Fatalf("Failed to create sketch: %v", err)
+	}
+	ipv6Map := NewIPv6Sketch(sketch)
+	const n = 100000
+	for i := 0; i < 2*n; i++ {
+		ipv6Map.Add(IPv6{0: 0x20, 1: 0x01, 12: byte(i % n >> 24), 13: byte(i % n >> 16), 14: byte(i % n >> 8), 15: byte(i % n)})
+	}
+	count := float64(ipv6Map.Count())
+	if relative := math.Abs(count-n) / n; relative > 0.05 {
+		t.Errorf("Expected about %d unique addresses, got %.0f", n, count)
+	}
+}
+
+func TestMergeIPMaps(t *testing.T) {
+	testCases := []struct {
+		name                string
+		mp, other, expected IPMap
+	}{
+		{"Set", must(NewSet()), must(NewSet()), must(NewSet())},
+		{"Roaring", must(NewIPRoaring()), must(NewIPRoaring()), must(NewIPRoaring())},
+		{"HyperLogLog", must(NewHyperLogLog(14)), must(NewHyperLogLog(14)), must(NewHyperLogLog(14))},
+		{"HyperLogLogPlus", must(NewHyperLogLogPlus(14)), must(NewHyperLogLogPlus(14)), must(NewHyperLogLogPlus(14))},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mp, other, expected := tc.mp, tc.other, tc.expected
+			for ip := uint32(0); ip < 3000; ip++ {
+				mp.Add(fnv1a.HashUint32(ip))
+				expected.Add(fnv1a.HashUint32(ip))
+			}
+			for ip := uint32(2000); ip < 5000; ip++ {
+				other.Add(fnv1a.HashUint32(ip))
+				expected.Add(fnv1a.HashUint32(ip))
+			}
+
+			mergeable, ok := mp.(Mergeable)
+			if !ok {
+				t.Fatalf("Expected %T to implement Mergeable", mp)
+			}
+			if err := mergeable.Merge(other); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if mp.Count() != expected.Count() {
+				t.Errorf("Expected count %d after merge, got %d", expected.Count(), mp.Count())
+			}
+			if err := mergeable.Merge(NewMockIPMap()); err == nil {
+				t.Errorf("Expected an error when merging a different counter type")
+			}
+		})
+	}
+}
+
+func TestParallelShards(t *testing.T) {
+	// Force several workers on machines with few cores
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+
+	testCases := []struct {
+		name               string
+		fileMap, readerMap IPMap
+	}{
+		{"Set", must(NewSet()), must(NewSet())},
+		{"HyperLogLogPlus sparse", must(NewHyperLogLogPlus(14)), must(NewHyperLogLogPlus(14))},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			counter := NewIPCounter(tc.fileMap, true, nil)
+			result, err := counter.CountIPFromFile("./ipsbig")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result.Count != 1000 {
+				t.Errorf("Expected count 1000 from file, got %d", result.Count)
+			}
+			if counter.shards != nil {
+				t.Errorf("Expected shards to be merged after the run")
+			}
+
+			file, err := os.Open("./ipsbig")
+			if err != nil {
+				t.Fatalf("Failed to open file: %v", err)
+			}
+			defer file.Close()
+			counter = NewIPCounter(tc.readerMap, true, nil)
+			if _, err := counter.processReader(file, 4096); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if count := counter.ipMap.Count(); count != 1000 {
+				t.Errorf("Expected count 1000 from reader, got %d", count)
+			}
+		})
+	}
+}
+
+func TestUnmarshalIPMap(t *testing.T) {
+	mp, _ := NewHyperLogLogPlus(14)
+	counter := NewIPCounter(mp, false, FNV1aHasher{})
+	if _, err := counter.CountIPFromReader(strings.NewReader("10.0.0.1\n10.0.0.2\n")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	data, err := mp.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+
+	saved, err := UnmarshalIPMap(data)
+	if err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	if hll, ok := saved.(*IPHyperLogLogPlus); !ok || hll.HashFunc().ID != header.HashFNV1a {
+		t.Errorf("Expected a HyperLogLogPlus with the fnv1a hash, got %T", saved)
+	}
+
+	// Sketches filled with a different hash function can't be merged
+	otherHash, _ := NewHyperLogLogPlus(14)
+	if err := NewIPCounter(otherHash, false, Murmur3Hasher{Seed: 1}).Merge(saved); err == nil {
+		t.Errorf("Expected an error when merging a sketch of a different hash function")
+	}
+
+	other, _ := NewHyperLogLogPlus(14)
+	counter = NewIPCounter(other, false, FNV1aHasher{})
+	if err := counter.Merge(saved); err != nil {
+		t.Fatalf("Failed to merge: %v", err)
+	}
+	result, err := counter.CountIPFromReader(strings.NewReader("10.0.0.2\n10.0.0.3\n"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Count != 3 {
+		t.Errorf("Expected count 3 after merging the saved counter, got %d", result.Count)
+	}
+
+	if _, err := UnmarshalIPMap([]byte("garbage")); err == nil {
+		t.Errorf("Expected an error for invalid data")
+	}
+}
+
+func TestHashers(t *testing.T) {
+	for _, name := range []string{"none", "fnv1a", "fmix32", "murmur3"} {
+		t.Run(name, func(t *testing.T) {
+			hasher, err := ParseHasher(name, 42)
+			if err != nil {
+				t.Fatalf("ParseHasher() error = %v", err)
+			}
+			if hasher.HashFunc().ID.String() != name {
+				t.Errorf("Expected the %s hash function, got %v", name, hasher.HashFunc())
+			}
+			if bijection := name == "none" || name == "fmix32"; IsBijection(hasher) != bijection {
+				t.Errorf("IsBijection() = %v, want %v", IsBijection(hasher), bijection)
+			}
+
+			mp, _ := NewHyperLogLogPlus(14)
+			counter := NewIPCounter(mp, true, hasher)
+			result, err := counter.CountIPFromFile("ipsbig")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result.Count != 1000 {
+				t.Errorf("Expected count 1000, got %d", result.Count)
+			}
+			if mp.(*IPHyperLogLogPlus).HashFunc() != hasher.HashFunc() {
+				t.Errorf("Expected the sketch to record %v, got %v", hasher.HashFunc(), mp.(*IPHyperLogLogPlus).HashFunc())
+			}
+		})
+	}
+
+	if _, err := ParseHasher("sha1", 0); err == nil {
+		t.Errorf("Expected an error for an unknown hash function")
+	}
+}
+
+func TestIPFrequency(t *testing.T) {
+	var input strings.Builder
+	for i := 0; i < 1000; i++ {
+		input.WriteString("10.0.0.1\n")
+		if i%2 == 0 {
+			input.WriteString("192.168.1.1\n")
+		}
+		fmt.Fprintf(&input, "172.16.%d.%d\n", i/256, i%256)
+	}
+
+	for _, useParallel := range []bool{false, true} {
+		t.Run(fmt.Sprintf("Parallel%v", useParallel), func(t *testing.T) {
+			mp, _ := NewIPFrequency()
+			counter := NewIPCounter(mp, useParallel, nil)
+			result, err := counter.CountIPFromReader(strings.NewReader(input.String()))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result.Count != 1002 {
+				t.Errorf("Expected count 1002, got %d", result.Count)
+			}
+
+			frequency := counter.IPMap().(*IPFrequency)
+			top := frequency.Top(3)
+			expected := []string{"10.0.0.1 1000", "192.168.1.1 500", "172.16.0.0 1"}
+			if len(top) != len(expected) {
+				t.Fatalf("Expected %d IPs, got %v", len(expected), top)
+			}
+			for i := range expected {
+				if top[i].String() != expected[i] {
+					t.Errorf("Expected %q at %d, got %q", expected[i], i, top[i])
+				}
+			}
+			if frequency.Total() != 2500 {
+				t.Errorf("Expected 2500 IPs in total, got %d", frequency.Total())
+			}
+
+			histogram := frequency.Histogram()
+			if histogram[0].Keys != 1000 || histogram[4].Keys != 2 {
+				t.Errorf("Expected 1000 IPs seen once and 2 IPs seen 101-1000 times, got %d and %d", histogram[0].Keys, histogram[4].Keys)
+			}
+		})
+	}
+}
+
+func TestFormatIP(t *testing.T) {
+	testCases := map[uint32]string{
+		0:          "0.0.0.0",
+		0x0A000001: "10.0.0.1",
+		0xFFFFFFFF: "255.255.255.255",
+		0xC0A80164: "192.168.1.100",
+	}
+	for ip, expected := range testCases {
+		if got := FormatIP(ip); got != expected {
+			t.Errorf("FormatIP(%#x) = %q, expected %q", ip, got, expected)
+		}
+	}
+}
+
+func TestFrequencyMap(t *testing.T) {
+	// Force several workers on machines with few cores
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+
+	var input strings.Builder
+	for i := 0; i < 5000; i++ {
+		fmt.Fprintf(&input, "10.0.%d.%d\n", i/256, i%256)
+		if i%5 == 0 {
+			input.WriteString("192.168.1.1\n")
+		}
+		if i%10 == 0 {
+			input.WriteString("192.168.1.2\n")
+		}
+	}
+
+	exact, _ := NewIPFrequency()
+	spaceSaving, _ := NewSpaceSaving(100)
+	countMin, _ := NewCountMin(0.001, 0.01, 100)
+
+	testCases := []struct {
+		name         string
+		frequencyMap FrequencyMap
+	}{
+		{"Exact", exact.(FrequencyMap)},
+		{"SpaceSaving", spaceSaving},
+		{"CountMin", countMin},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mp, _ := NewHyperLogLogPlus(14)
+			counter := NewIPCounter(mp, true, Fmix32Hasher{})
+			frequencyMap := tc.frequencyMap
+			counter.SetFrequencyMap(frequencyMap)
+			result, err := counter.CountIPFromReader(strings.NewReader(input.String()))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result.Count < 4950 || result.Count > 5050 {
+				t.Errorf("Expected a count close to 5002, got %d", result.Count)
+			}
+			if frequencyMap.Total() != 6500 {
+				t.Errorf("Expected 6500 IPs in total, got %d", frequencyMap.Total())
+			}
+			if counter.frequencies != nil {
+				t.Errorf("Expected the frequency forks to be merged after the run")
+			}
+
+			// The IPs are reported before they are hashed
+			expected := []IPCount{{IP: 0xC0A80101, Count: 1000}, {IP: 0xC0A80102, Count: 500}}
+			top := frequencyMap.Top(2)
+			for i, ipCount := range top {
+				if ipCount.IP != expected[i].IP || ipCount.Count < expected[i].Count ||
+					ipCount.Count-ipCount.Error > expected[i].Count {
+					t.Errorf("Expected %v at %d, got %v", expected[i], i, ipCount)
+				}
+			}
+		})
+	}
+}
+
+func TestAggregateCIDRs(t *testing.T) {
+	testCases := []struct {
+		name     string
+		ips      []uint32
+		expected []string
+	}{
+		{"Empty", nil, nil},
+		{"Single IP", []uint32{0x0A000001}, []string{"10.0.0.1/32"}},
+		{"Aligned block", seqIPs(0x0A000000, 256), []string{"10.0.0.0/24"}},
+		{"Unaligned run", seqIPs(0x0A000001, 6), []string{"10.0.0.1/32", "10.0.0.2/31", "10.0.0.4/31", "10.0.0.6/32"}},
+		{"Separate runs", append(seqIPs(0x0A000000, 4), seqIPs(0x0A000010, 2)...), []string{"10.0.0.0/30", "10.0.0.16/31"}},
+		{"End of the address space", seqIPs(0xFFFFFFFE, 2), []string{"255.255.255.254/31"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var cidrs []string
+			for cidr := range AggregateCIDRs(slices.Values(tc.ips)) {
+				cidrs = append(cidrs, cidr.String())
+			}
+			if !slices.Equal(cidrs, tc.expected) {
+				t.Errorf("Expected %v, got %v", tc.expected, cidrs)
+			}
+		})
+	}
+
+	// The whole address space is a single prefix
+	var cidrs []CIDR
+	coverRange(0, math.MaxUint32, func(cidr CIDR) bool {
+		cidrs = append(cidrs, cidr)
+		return true
+	})
+	if len(cidrs) != 1 || cidrs[0].String() != "0.0.0.0/0" || cidrs[0].Size() != 1<<32 {
+		t.Errorf("Expected 0.0.0.0/0, got %v", cidrs)
+	}
+}
+
+// must returns the counter of a constructor called with arguments that can't fail
+func must[T any](mp T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+	return mp
+}
+
+func seqIPs(first uint32, n int) []uint32 {
+	ips := make([]uint32, n)
+	for i := range ips {
+		ips[i] = first + uint32(i)
+	}
+	return ips
+}
+
+func TestSortedIPs(t *testing.T) {
+	input := "10.0.0.3\n10.0.0.1\n192.168.0.1\n10.0.0.2\n10.0.0.1\n10.0.0.0\n"
+	expectedIPs := "10.0.0.0\n10.0.0.1\n10.0.0.2\n10.0.0.3\n192.168.0.1\n"
+	expectedCIDRs := "10.0.0.0/30\n192.168.0.1/32\n"
+
+	testCases := []struct {
+		name string
+		mp   IPMap
+	}{
+		{"Set", must(NewSet())},
+		{"Frequency", must(NewIPFrequency())},
+		{"Prefixes", must(NewIPPrefixes(16))},
+		{"Roaring", must(NewIPRoaring())},
+		// The bitmap is paged, the IPs only allocate 2 pages
+		{"BitMap", must(NewIPBitMap())},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			counter := NewIPCounter(tc.mp, true, nil)
+			if _, err := counter.CountIPFromReader(strings.NewReader(input)); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			sorted, ok := counter.IPMap().(SortedIPMap)
+			if !ok {
+				t.Fatalf("Expected %T to implement SortedIPMap", counter.IPMap())
+			}
+
+			var ips, cidrs strings.Builder
+			if err := WriteIPs(&ips, sorted.SortedIPs()); err != nil {
+				t.Fatalf("WriteIPs() error = %v", err)
+			}
+			if err := WriteCIDRs(&cidrs, sorted.SortedIPs()); err != nil {
+				t.Fatalf("WriteCIDRs() error = %v", err)
+			}
+			if ips.String() != expectedIPs {
+				t.Errorf("Expected IPs %q, got %q", expectedIPs, ips.String())
+			}
+			if cidrs.String() != expectedCIDRs {
+				t.Errorf("Expected CIDRs %q, got %q", expectedCIDRs, cidrs.String())
+			}
+		})
+	}
+}
+
+func TestCompareIPMaps(t *testing.T) {
+	testCases := []struct {
+		name      string
+		a, b      IPMap
+		exact     bool
+		tolerance float64 // Relative to the union
+	}{
+		{"BitMap", &IPBitMap{bm: must(bitmap.New(1 << 16))}, &IPBitMap{bm: must(bitmap.New(1 << 16))}, true, 0},
+		{"Roaring", must(NewIPRoaring()), must(NewIPRoaring()), true, 0},
+		{"Set", must(NewSet()), must(NewSet()), true, 0},
+		{"Frequency", must(NewIPFrequency()), must(NewIPFrequency()), true, 0},
+		{"HyperLogLog", must(NewHyperLogLog(14)), must(NewHyperLogLog(14)), false, 0.03},
+		{"HyperLogLogPlus", must(NewHyperLogLogPlus(14)), must(NewHyperLogLogPlus(14)), false, 0.03},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a, b := tc.a, tc.b
+			for ip := uint32(0); ip < 30000; ip++ {
+				a.Add(ip)
+			}
+			for ip := uint32(20000); ip < 50000; ip++ {
+				b.Add(ip)
+			}
+			countA, countB := a.Count(), b.Count()
+
+			counts, err := CompareIPMaps(a, b)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if counts.Exact != tc.exact {
+				t.Errorf("Expected exact %v, got %v", tc.exact, counts.Exact)
+			}
+			expected := SetCounts{A: 30000, B: 30000, Union: 50000, Intersection: 10000, OnlyA: 20000, OnlyB: 20000, Exact: tc.exact}
+			if tc.exact && counts != expected {
+				t.Errorf("Expected %+v, got %+v", expected, counts)
+			}
+			maxError := tc.tolerance * 50000
+			for _, c := range []struct {
+				name        string
+				got, wanted uint64
+			}{
+				{"union", counts.Union, expected.Union},
+				{"intersection", counts.Intersection, expected.Intersection},
+				{"only A", counts.OnlyA, expected.OnlyA},
+				{"only B", counts.OnlyB, expected.OnlyB},
+			} {
+				if math.Abs(float64(c.got)-float64(c.wanted)) > maxError {
+					t.Errorf("Expected %s %d, got %d", c.name, c.wanted, c.got)
+				}
+			}
+			if jaccard := counts.Jaccard(); math.Abs(jaccard-0.2) > 0.05 {
+				t.Errorf("Expected Jaccard similarity 0.2, got %v", jaccard)
+			}
+			if a.Count() != countA || b.Count() != countB {
+				t.Errorf("Comparing must not modify the counters, got counts %d and %d", a.Count(), b.Count())
+			}
+		})
+	}
+
+	set, _ := NewSet()
+	hyperLogLog, _ := NewHyperLogLog(14)
+	if _, err := CompareIPMaps(hyperLogLog, set); err == nil {
+		t.Errorf("Expected an error when comparing different counter types")
+	}
+
+	// Exact counters of different types look up the IPs of one in the other
+	exactTypes := []struct {
+		name string
+		a, b IPMap
+	}{
+		{"BitMap", &IPBitMap{bm: must(bitmap.New(1 << 16))}, &IPBitMap{bm: must(bitmap.New(1 << 16))}},
+		{"Roaring", must(NewIPRoaring()), must(NewIPRoaring())},
+		{"Set", must(NewSet()), must(NewSet())},
+		{"Frequency", must(NewIPFrequency()), must(NewIPFrequency())},
+	}
+	for _, exactType := range exactTypes {
+		for ip := uint32(0); ip < 3000; ip++ {
+			exactType.a.Add(ip)
+		}
+		for ip := uint32(2000); ip < 5000; ip++ {
+			exactType.b.Add(ip)
+		}
+	}
+	for _, typeA := range exactTypes {
+		for _, typeB := range exactTypes {
+			counts, err := CompareIPMaps(typeA.a, typeB.b)
+			if err != nil {
+				t.Fatalf("Unexpected error comparing %s with %s: %v", typeA.name, typeB.name, err)
+			}
+			expected := SetCounts{A: 3000, B: 3000, Union: 5000, Intersection: 1000, OnlyA: 2000, OnlyB: 2000, Exact: true}
+			if counts != expected {
+				t.Errorf("Comparing %s with %s: expected %+v, got %+v", typeA.name, typeB.name, expected, counts)
+			}
+		}
+	}
+
+	fmix32 := header.HashFunc{ID: header.HashFmix32}
+	hashedBitMap := &IPBitMap{bm: must(bitmap.New(1 << 16))}
+	hashedSet, _ := NewSet()
+	hashedBitMap.SetHashFunc(fmix32)
+	hashedSet.(hashFuncSetter).SetHashFunc(fmix32)
+	if _, err := CompareIPMaps(hashedBitMap, hashedSet); err != nil {
+		t.Errorf("Unexpected error comparing counters of the same hash function: %v", err)
+	}
+	if _, err := CompareIPMaps(hashedBitMap, set); err == nil {
+		t.Errorf("Expected an error when comparing counters of different hash functions")
+	}
+	if _, err := CompareIPMaps(must(NewIPFrequency()), hashedSet); err == nil {
+		t.Errorf("Expected an error when comparing counters of different hash functions")
+	}
+}
+
+func TestIPPrefixes(t *testing.T) {
+	mp, err := NewIPPrefixes(8, 24)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	counter := NewIPCounter(mp, true, nil)
+	input := "10.0.0.1\n10.0.0.2\n10.0.0.1\n10.0.1.1\n172.16.5.4\n"
+	if _, err := counter.CountIPFromReader(strings.NewReader(input)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	prefixes := counter.IPMap().(*IPPrefixes)
+	if prefixes.Count() != 4 {
+		t.Errorf("Expected 4 unique IPs, got %d", prefixes.Count())
+	}
+	if count, _ := prefixes.Prefixes(24); count != 3 {
+		t.Errorf("Expected 3 distinct /24s, got %d", count)
+	}
+	top, err := prefixes.TopPrefixes(8, 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(top) != 1 || top[0].String() != "10.0.0.0/8: 3 unique hosts" {
+		t.Errorf("Expected 10.0.0.0/8 with 3 unique hosts on top, got %v", top)
+	}
+	if _, err := prefixes.TopPrefixes(16, 1); err == nil {
+		t.Errorf("Expected an error for a prefix length that isn't tracked")
+	}
+}
+
+func TestIPRoaring(t *testing.T) {
+	mp, _ := NewIPRoaring()
+	counter := NewIPCounter(mp, true, nil)
+	result, err := counter.CountIPFromFile("ipsbig")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Count != 1000 {
+		t.Errorf("Expected 1000 unique IPs, got %d", result.Count)
+	}
+
+	data, err := mp.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	saved, err := UnmarshalIPMap(data)
+	if err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	counts, err := CompareIPMaps(mp, saved)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if counts.Intersection != 1000 || counts.Union != 1000 {
+		t.Errorf("Expected the saved counter to hold the same 1000 IPs, got %+v", counts)
+	}
+}
+
+func TestIPBitMapCountCIDR(t *testing.T) {
+	mp, _ := NewIPBitMap()
+	counter := NewIPCounter(mp, true, nil)
+	input := "10.0.0.1\n10.0.0.255\n10.0.1.0\n10.1.0.0\n255.255.255.254\n"
+	if _, err := counter.CountIPFromReader(strings.NewReader(input)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	bitMap := mp.(*IPBitMap)
+	for _, tc := range []struct {
+		cidr     CIDR
+		expected uint64
+	}{
+		{CIDR{IP: 10 << 24, Bits: 24}, 2},
+		{CIDR{IP: 10 << 24, Bits: 16}, 3},
+		{CIDR{IP: 10 << 24, Bits: 8}, 4},
+		{CIDR{IP: 10<<24 | 1, Bits: 32}, 1},
+		{CIDR{IP: 11 << 24, Bits: 8}, 0},
+		{CIDR{IP: 255 << 24, Bits: 8}, 1},
+		{CIDR{IP: 0, Bits: 0}, 5},
+	} {
+		if count := bitMap.CountCIDR(tc.cidr); count != tc.expected {
+			t.Errorf("Expected %d IPs in %v, got %d", tc.expected, tc.cidr, count)
+		}
+	}
+}
+
+func TestIPAtomicBitMap(t *testing.T) {
+	mp, _ := NewIPAtomicBitMap()
+	if _, ok := mp.(ConcurrentIPMap); !ok {
+		t.Fatalf("Expected %T to implement ConcurrentIPMap", mp)
+	}
+	counter := NewIPCounter(mp, true, nil)
+	result, err := counter.CountIPFromFile("ipsbig")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Count != 1000 {
+		t.Errorf("Expected 1000 unique IPs, got %d", result.Count)
+	}
+
+	// Saved atomic bitmaps are restored as bitmaps, which merge back into atomic ones
+	data, err := mp.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	saved, err := UnmarshalIPMap(data)
+	if err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	if _, ok := saved.(*IPBitMap); !ok {
+		t.Errorf("Expected an IPBitMap, got %T", saved)
+	}
+	counts, err := CompareIPMaps(mp, saved)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !counts.Exact || counts.Intersection != 1000 || counts.Union != 1000 {
+		t.Errorf("Expected the saved counter to hold the same 1000 IPs, got %+v", counts)
+	}
+
+	hashed, _ := NewIPAtomicBitMap()
+	hashed.(hashFuncSetter).SetHashFunc(header.HashFunc{ID: header.HashFmix32})
+	if hashFuncOf(hashed).ID != header.HashFmix32 {
+		t.Errorf("Expected the fmix32 hash function, got %v", hashFuncOf(hashed))
+	}
+	if _, err := CompareIPMaps(hashed, saved); err == nil {
+		t.Errorf("Expected an error when comparing counters of different hash functions")
+	}
+	if _, err := CompareIPMaps(saved, hashed); err == nil {
+		t.Errorf("Expected an error when comparing counters of different hash functions")
+	}
+
+	other, _ := NewIPAtomicBitMap()
+	other.Add(1)
+	if err := NewIPCounter(other, true, nil).Merge(saved); err != nil {
+		t.Fatalf("Failed to merge: %v", err)
+	}
+	if err := other.(Mergeable).Merge(mp); err != nil {
+		t.Fatalf("Failed to merge: %v", err)
+	}
+	if other.Count() != 1001 {
+		t.Errorf("Expected 1001 unique IPs after merging, got %d", other.Count())
+	}
+}
+
+func TestIPConcurrentHyperLogLog(t *testing.T) {
+	mp, _ := NewConcurrentHyperLogLog(14)
+	if _, ok := mp.(ConcurrentIPMap); !ok {
+		t.Fatalf("Expected %T to implement ConcurrentIPMap", mp)
+	}
+	if _, ok := mp.(Forkable); ok {
+		t.Errorf("Expected %T not to be sharded", mp)
+	}
+	counter := NewIPCounter(mp, true, nil)
+	result, err := counter.CountIPFromFile("ipsbig")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	sequential, _ := NewHyperLogLog(14)
+	if _, err := NewIPCounter(sequential, false, nil).CountIPFromFile("ipsbig"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Count != sequential.Count() {
+		t.Errorf("Expected the count of a HyperLogLog, %d, got %d", sequential.Count(), result.Count)
+	}
+
+	// Saved concurrent sketches are restored as HyperLogLog sketches, which merge back into concurrent ones
+	data, err := mp.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	saved, err := UnmarshalIPMap(data)
+	if err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	if _, ok := saved.(*IPHyperLogLog); !ok {
+		t.Errorf("Expected an IPHyperLogLog, got %T", saved)
+	}
+	counts, err := CompareIPMaps(mp, saved)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if counts.Union != result.Count {
+		t.Errorf("Expected the saved sketch to hold the same IPs, got %+v", counts)
+	}
+
+	other, _ := NewConcurrentHyperLogLog(14)
+	if err := NewIPCounter(other, true, nil).Merge(saved); err != nil {
+		t.Fatalf("Failed to merge: %v", err)
+	}
+	if err := other.(Mergeable).Merge(mp); err != nil {
+		t.Fatalf("Failed to merge: %v", err)
+	}
+	if other.Count() != result.Count {
+		t.Errorf("Expected %d unique IPs after merging, got %d", result.Count, other.Count())
+	}
+}
+
+func TestIPPartitionedBitMap(t *testing.T) {
+	testCases := []struct {
+		partitions int
+		hash       string
+		parallel   bool
+	}{
+		{1, "none", true},
+		{4, "none", true},
+		{16, "fmix32", true},
+		{256, "none", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("Partitions%d", tc.partitions), func(t *testing.T) {
+			mp, err := NewIPPartitionedBitMap(tc.partitions)
+			if err != nil {
+				t.Fatalf("NewIPPartitionedBitMap() error = %v", err)
+			}
+			if _, ok := mp.(ConcurrentIPMap); !ok {
+				t.Fatalf("Expected %T to implement ConcurrentIPMap", mp)
+			}
+			hasher, _ := ParseHasher(tc.hash, 0)
+			counter := NewIPCounter(mp, tc.parallel, hasher)
+			result, err := counter.CountIPFromFile("ipsbig")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result.Count != 1000 || result.Passes != tc.partitions {
+				t.Errorf("Expected 1000 unique IPs in %d passes, got %d in %d", tc.partitions, result.Count, result.Passes)
+			}
+			// The lines are only counted by the first pass
+			if result.Lines != 35000 || result.ValidIPs != 35000 {
+				t.Errorf("Expected 35000 lines, got %d", result.Lines)
+			}
+		})
+	}
+
+	for _, partitions := range []int{0, 3, MaxPartitions * 2} {
+		if _, err := NewIPPartitionedBitMap(partitions); err == nil {
+			t.Errorf("Expected an error for %d partitions", partitions)
+		}
+	}
+}
+
+func TestIPPartitionedBitMapAddressSpaceEnds(t *testing.T) {
+	// The first and the last IP of the address space and of the partitions, 255.255.255.255 is the last bit of the bitmap
+	input := "0.0.0.0\n1.2.3.4\n63.255.255.255\n64.0.0.0\n255.255.255.254\n255.255.255.255\n255.255.255.255\n0.0.0.0\n"
+	path := filepath.Join(t.TempDir(), "ips")
+	if err := os.WriteFile(path, []byte(input), 0o644); err != nil {
+		t.Fatalf("Failed to write the input: %v", err)
+	}
+
+	partitioned := func(partitions int) func() (IPMap, error) {
+		return func() (IPMap, error) { return NewIPPartitionedBitMap(partitions) }
+	}
+	testCases := []struct {
+		name  string
+		newFn func() (IPMap, error)
+	}{
+		{"BitMap", NewIPBitMap},
+		{"AtomicBitMap", NewIPAtomicBitMap},
+		{"Roaring", NewIPRoaring},
+		{"Partitioned1", partitioned(1)},
+		{"Partitioned4", partitioned(4)},
+		{"Partitioned256", partitioned(256)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mp, err := tc.newFn()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			result, err := NewIPCounter(mp, true, nil).CountIPFromFile(path)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result.Count != 6 {
+				t.Errorf("Expected 6 unique IPs, got %d", result.Count)
+			}
+		})
+	}
+}
+
+func TestIPPartitionedBitMapSinglePass(t *testing.T) {
+	mp, _ := NewIPPartitionedBitMap(4)
+	counter := NewIPCounter(mp, true, nil)
+	if _, err := counter.CountIPFromReader(strings.NewReader("10.0.0.1\n")); err == nil {
+		t.Errorf("Expected an error when counting a stream")
+	}
+	if _, err := counter.CountIPFromFile("ipsbig"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := counter.CountIPFromFile("ipsbig"); err == nil {
+		t.Errorf("Expected an error when counting a second file")
+	}
+
+	mp, _ = NewIPPartitionedBitMap(4)
+	counter = NewIPCounter(mp, true, nil)
+	frequencyMap, _ := NewSpaceSaving(10)
+	counter.SetFrequencyMap(frequencyMap)
+	if _, err := counter.CountIPFromFile("ipsbig"); err == nil {
+		t.Errorf("Expected an error when counting frequencies")
+	}
+}
+
+func TestPartitionsForMemory(t *testing.T) {
+	testCases := []struct {
+		maxMemory  int64
+		partitions int
+		wantErr    bool
+	}{
+		{1 << 30, 1, false},
+		{512 << 20, 1, false},
+		{512<<20 - 1, 2, false},
+		{100 << 20, 8, false},
+		{bitmap.PageSize, MaxPartitions, false},
+		{bitmap.PageSize - 1, 0, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("Memory%d", tc.maxMemory), func(t *testing.T) {
+			partitions, err := PartitionsForMemory(tc.maxMemory)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("PartitionsForMemory(%d) error = %v, wantErr %v", tc.maxMemory, err, tc.wantErr)
+			}
+			if partitions != tc.partitions {
+				t.Errorf("PartitionsForMemory(%d) = %d, want %d", tc.maxMemory, partitions, tc.partitions)
+			}
+		})
+	}
+}
@@ -2,9 +2,18 @@ package ipcounter
 
 import (
 	"awesomeProject/ipcounter/counters/bitmap"
+	"awesomeProject/ipcounter/counters/countmin"
+	"awesomeProject/ipcounter/counters/frequency"
+	"awesomeProject/ipcounter/counters/header"
 	"awesomeProject/ipcounter/counters/hyperloglog"
 	"awesomeProject/ipcounter/counters/hyperloglogplus"
 	"awesomeProject/ipcounter/counters/hyperloglogplusbitmap"
+	"awesomeProject/ipcounter/counters/prefix"
+	"awesomeProject/ipcounter/counters/roaring"
+	"awesomeProject/ipcounter/counters/spacesaving"
+	"encoding"
+	"fmt"
+	"math/bits"
 )
 
 type IPMap interface {
@@ -12,8 +21,16 @@ type IPMap interface {
 	Count() uint64
 }
 
+// Mergeable is implemented by counters that can add the IPs of another counter of the same type,
+// so that separate counters can be filled in parallel and combined at the end
+type Mergeable interface {
+	Merge(other IPMap) error
+}
+
+// NewIPBitMap creates an exact counter on a paged bitmap of the address space,
+// only the 64 KB pages of the regions with an IP are allocated
 func NewIPBitMap() (IPMap, error) {
-	bm, err := bitmap.New(bitmap.MaxSize)
+	bm, err := bitmap.NewPaged(bitmap.MaxSize)
 	if err != nil {
 		return nil, err
 	}
@@ -22,20 +39,193 @@ func NewIPBitMap() (IPMap, error) {
 	}, nil
 }
 
+// NewIPAtomicBitMap creates an exact counter on a paged bitmap of the address space filled without locks
+func NewIPAtomicBitMap() (IPMap, error) {
+	bm, err := bitmap.NewAtomic(bitmap.MaxSize)
+	if err != nil {
+		return nil, err
+	}
+	return &IPAtomicBitMap{
+		bm: bm,
+	}, nil
+}
+
+// NewIPPartitionedBitMap creates an exact counter on a bitmap of 2^32/partitions bits, a file is read once per partition.
+// The number of partitions must be a power of two up to MaxPartitions, see PartitionsForMemory.
+func NewIPPartitionedBitMap(partitions int) (IPMap, error) {
+	if partitions < 1 || partitions > MaxPartitions || partitions&(partitions-1) != 0 {
+		return nil, fmt.Errorf("invalid number of partitions: %d, must be a power of two between 1 and %d", partitions, MaxPartitions)
+	}
+	shift := uint8(32 - bits.TrailingZeros(uint(partitions)))
+	bm, err := bitmap.NewAtomic(uint64(1) << shift)
+	if err != nil {
+		return nil, err
+	}
+	return &IPPartitionedBitMap{
+		bm:    bm,
+		shift: shift,
+	}, nil
+}
+
+// NewIPRoaring creates an exact counter on a compressed bitmap, it takes memory in proportion to the IPs
+func NewIPRoaring() (IPMap, error) {
+	return &IPRoaring{
+		rb: roaring.New(),
+	}, nil
+}
+
 func NewSet() (IPMap, error) {
 	return &IPSet{
-		set: make(map[uint32]struct{}, bitmap.MaxSize),
+		set: make(map[uint32]struct{}),
+	}, nil
+}
+
+// NewIPFrequency creates an exact counter of the occurrences of each IP
+func NewIPFrequency() (IPMap, error) {
+	return &IPFrequency{
+		table: frequency.New(1 << 16),
 	}, nil
 }
 
-func NewHyperLogLog(precision uint8) (IPMap, error) {
-	return hyperloglog.New(precision)
+// NewIPPrefixes creates an exact counter also counting the unique hosts of every prefix of the lengths,
+// each between 8 and 24
+func NewIPPrefixes(lengths ...uint8) (IPMap, error) {
+	aggregator, err := prefix.New(lengths...)
+	if err != nil {
+		return nil, err
+	}
+	return &IPPrefixes{aggregator: aggregator}, nil
+}
+
+// NewSpaceSaving creates an approximate frequency counter monitoring the k most frequent IPs
+func NewSpaceSaving(k int) (FrequencyMap, error) {
+	summary, err := spacesaving.New(k)
+	if err != nil {
+		return nil, err
+	}
+	return &IPSpaceSaving{summary}, nil
+}
+
+// NewCountMin creates an approximate frequency counter overestimating by at most epsilon*N with probability 1-delta,
+// it tracks the k most frequent IPs
+func NewCountMin(epsilon, delta float64, k int) (FrequencyMap, error) {
+	sketch, err := countmin.New(epsilon, delta, k)
+	if err != nil {
+		return nil, err
+	}
+	return &IPCountMin{sketch: sketch, epsilon: epsilon, delta: delta, k: k}, nil
 }
 
-func NewHyperLogLogPlus(precision uint8) (IPMap, error) {
-	return hyperloglogplus.New(precision)
+func NewHyperLogLog(precision uint8) (HashIPMap, error) {
+	hll, err := hyperloglog.New(precision)
+	if err != nil {
+		return nil, err
+	}
+	return &IPHyperLogLog{hll}, nil
 }
 
-func NewHyperLogLogPlusBitMap(precision uint8) (IPMap, error) {
-	return hyperloglogplusbitmap.New(precision)
+// NewConcurrentHyperLogLog creates a HyperLogLog sketch filled without locks
+func NewConcurrentHyperLogLog(precision uint8) (HashIPMap, error) {
+	hll, err := hyperloglog.NewConcurrent(precision)
+	if err != nil {
+		return nil, err
+	}
+	return &IPConcurrentHyperLogLog{hll}, nil
+}
+
+func NewHyperLogLogPlus(precision uint8) (HashIPMap, error) {
+	hll, err := hyperloglogplus.New(precision)
+	if err != nil {
+		return nil, err
+	}
+	return &IPHyperLogLogPlus{hll}, nil
+}
+
+func NewHyperLogLogPlusBitMap(precision uint8) (HashIPMap, error) {
+	hll, err := hyperloglogplusbitmap.New(precision)
+	if err != nil {
+		return nil, err
+	}
+	return &IPHyperLogLogPlusBitMap{hll}, nil
+}
+
+// Forkable is implemented by counters that are cheap to create. In parallel mode each worker fills
+// its own empty fork without locking, the forks are merged into the counter at the end.
+type Forkable interface {
+	Mergeable
+	Fork() IPMap
+}
+
+// BatchAdder is implemented by counters that can be filled by several workers at once
+// and synchronize internally, e.g. by locking only a part of the counter
+type BatchAdder interface {
+	AddBatch(ips []uint32)
+}
+
+// hashFuncSetter is implemented by counters that record the hash function of the IPs for serialization
+type hashFuncSetter interface {
+	SetHashFunc(hashFunc header.HashFunc)
+}
+
+// hashFuncGetter is implemented by counters that record the hash function of the IPs
+type hashFuncGetter interface {
+	HashFunc() header.HashFunc
+}
+
+// hashFuncOf returns the hash function recorded by the counter, the counters that don't record one hold the IPs as is
+func hashFuncOf(mp IPMap) header.HashFunc {
+	if getter, ok := mp.(hashFuncGetter); ok {
+		return getter.HashFunc()
+	}
+	return header.HashFunc{}
+}
+
+// UnmarshalIPMap restores a counter saved with MarshalBinary, the type is read from the header
+func UnmarshalIPMap(data []byte) (IPMap, error) {
+	h, _, err := header.Peek(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var mp interface {
+		IPMap
+		encoding.BinaryUnmarshaler
+	}
+	switch h.Type {
+	case header.TypeBitMap:
+		mp = &IPBitMap{}
+	case header.TypeRoaring:
+		mp = &IPRoaring{}
+	case header.TypeHyperLogLog:
+		mp = &IPHyperLogLog{&hyperloglog.HyperLogLog{}}
+	case header.TypeHyperLogLogPlus:
+		mp = &IPHyperLogLogPlus{&hyperloglogplus.HyperLogLogPlus{}}
+	case header.TypeHyperLogLogPlusBitMap:
+		mp = &IPHyperLogLogPlusBitMap{&hyperloglogplusbitmap.HyperLogLogPlusBitMap{}}
+	default:
+		return nil, fmt.Errorf("unknown counter type: %v", h.Type)
+	}
+	if err := mp.UnmarshalBinary(data); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %v: %w", h.Type, err)
+	}
+	return mp, nil
+}
+
+// ErrorEstimator is implemented by approximate counters that know the relative standard error of their count
+type ErrorEstimator interface {
+	StandardError() float64
+}
+
+// NewIPv6Set creates an exact IPv6 counter
+func NewIPv6Set() IPv6Map {
+	return &IPv6Set{
+		set: make(map[IPv6]struct{}),
+	}
+}
+
+// NewIPv6Sketch creates an approximate IPv6 counter on top of a sketch from the HyperLogLog family
+func NewIPv6Sketch(sketch HashIPMap) IPv6Map {
+	return &IPv6Sketch{
+		sketch: sketch,
+	}
 }
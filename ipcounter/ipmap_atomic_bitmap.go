@@ -0,0 +1,72 @@
+package ipcounter
+
+import (
+	"awesomeProject/ipcounter/counters/bitmap"
+	"awesomeProject/ipcounter/counters/header"
+	"fmt"
+	"iter"
+)
+
+// ConcurrentIPMap is implemented by counters whose Add is safe for concurrent use without locking,
+// in parallel mode the workers add their batches to them directly
+type ConcurrentIPMap interface {
+	IPMap
+	// ConcurrentAdd marks Add as safe for concurrent use
+	ConcurrentAdd()
+}
+
+// IPAtomicBitMap is an exact counter like IPBitMap whose IPs are set with atomic operations,
+// the workers fill it without any lock
+type IPAtomicBitMap struct {
+	bm *bitmap.Atomic
+}
+
+func (m *IPAtomicBitMap) Add(ip uint32) {
+	m.bm.SetBit(ip)
+}
+
+func (m *IPAtomicBitMap) ConcurrentAdd() {}
+
+// Count counts the set bits of the allocated pages
+func (m *IPAtomicBitMap) Count() uint64 {
+	return m.bm.Count()
+}
+
+// Merge adds the IPs of another atomic bitmap or of a bitmap, e.g. one restored with UnmarshalIPMap
+func (m *IPAtomicBitMap) Merge(other IPMap) error {
+	switch o := other.(type) {
+	case *IPAtomicBitMap:
+		return m.bm.Merge(o.bm.BitMap())
+	case *IPBitMap:
+		return m.bm.Merge(o.bm)
+	}
+	return fmt.Errorf("can't merge %T into %T", other, m)
+}
+
+// Contains reports whether the IP was seen
+func (m *IPAtomicBitMap) Contains(ip uint32) bool {
+	return m.bm.GetBit(ip)
+}
+
+// SortedIPs lists the IPs of a copy of the bitmap
+func (m *IPAtomicBitMap) SortedIPs() iter.Seq[uint32] {
+	return m.snapshot().SortedIPs()
+}
+
+func (m *IPAtomicBitMap) SetHashFunc(hashFunc header.HashFunc) {
+	m.bm.SetHashFunc(hashFunc)
+}
+
+func (m *IPAtomicBitMap) HashFunc() header.HashFunc {
+	return m.bm.HashFunc()
+}
+
+// MarshalBinary saves the IPs as a bitmap, they are restored as an IPBitMap
+func (m *IPAtomicBitMap) MarshalBinary() ([]byte, error) {
+	return m.bm.BitMap().MarshalBinary()
+}
+
+// snapshot copies the bitmap to an IPBitMap
+func (m *IPAtomicBitMap) snapshot() *IPBitMap {
+	return &IPBitMap{bm: m.bm.BitMap()}
+}
@@ -33,6 +33,24 @@ func BenchmarkBitMapCount(b *testing.B) {
 	}
 }
 
+func BenchmarkRoaringAdd(b *testing.B) {
+	sizes := []int{100, 1000, 10000, 100000, 1000000}
+
+	for _, size := range sizes {
+		b.Run(fmt.Sprintf("Roaring-Size-%d", size), func(b *testing.B) {
+			hashes := generateRandomHashes(size)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				rb, _ := NewIPRoaring()
+				for _, hash := range hashes {
+					rb.Add(hash)
+				}
+			}
+		})
+	}
+}
+
 func BenchmarkHyperLogLogCount(b *testing.B) {
 	sizes := []int{100, 1000, 10000, 100000, 1000000}
 
@@ -2,16 +2,72 @@ package ipcounter
 
 import (
 	"awesomeProject/ipcounter/counters/bitmap"
+	"awesomeProject/ipcounter/counters/header"
+	"fmt"
+	"sync"
 )
 
+// bitMapRangeBits The number of top IP bits selecting the range of the bitmap locked by AddBatch
+const bitMapRangeBits = 8
+
 type IPBitMap struct {
-	bm *bitmap.BitMap
+	bm    *bitmap.BitMap
+	locks [1 << bitMapRangeBits]sync.Mutex
 }
 
 func (m *IPBitMap) Add(ip uint32) {
 	m.bm.SetBit(ip, true)
 }
 
+// AddBatch adds IPs concurrently with other workers. The ranges of the bitmap don't share bytes or pages,
+// so only the range of each IP is locked.
+func (m *IPBitMap) AddBatch(ips []uint32) {
+	for _, ip := range ips {
+		lock := &m.locks[ip>>(32-bitMapRangeBits)]
+		lock.Lock()
+		m.bm.SetBit(ip, true)
+		lock.Unlock()
+	}
+}
+
 func (m *IPBitMap) Count() uint64 {
 	return m.bm.Count()
 }
+
+// CountCIDR returns the number of unique IPs in the prefix, from the ranks of its first and last IPs
+func (m *IPBitMap) CountCIDR(c CIDR) uint64 {
+	count := m.bm.Rank(c.IP + uint32(c.Size()-1))
+	if c.IP > 0 {
+		count -= m.bm.Rank(c.IP - 1)
+	}
+	return count
+}
+
+func (m *IPBitMap) Merge(other IPMap) error {
+	o, ok := other.(*IPBitMap)
+	if !ok {
+		return fmt.Errorf("can't merge %T into %T", other, m)
+	}
+	return m.bm.Merge(o.bm)
+}
+
+func (m *IPBitMap) SetHashFunc(hashFunc header.HashFunc) {
+	m.bm.SetHashFunc(hashFunc)
+}
+
+func (m *IPBitMap) HashFunc() header.HashFunc {
+	return m.bm.HashFunc()
+}
+
+func (m *IPBitMap) MarshalBinary() ([]byte, error) {
+	return m.bm.MarshalBinary()
+}
+
+func (m *IPBitMap) UnmarshalBinary(data []byte) error {
+	bm := &bitmap.BitMap{}
+	if err := bm.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	m.bm = bm
+	return nil
+}
@@ -0,0 +1,43 @@
+package ipcounter
+
+import (
+	"awesomeProject/ipcounter/counters/hyperloglog"
+	"fmt"
+)
+
+// IPConcurrentHyperLogLog is a HyperLogLog sketch like IPHyperLogLog whose registers are raised with atomic operations,
+// the workers fill it without any lock
+type IPConcurrentHyperLogLog struct {
+	*hyperloglog.Concurrent
+}
+
+func (m *IPConcurrentHyperLogLog) Add(ip uint32) {
+	m.Concurrent.Add(hashIP(ip))
+}
+
+func (m *IPConcurrentHyperLogLog) AddHash(hash uint64) {
+	m.Concurrent.Add(hash)
+}
+
+func (m *IPConcurrentHyperLogLog) ConcurrentAdd() {}
+
+// Merge adds another concurrent sketch or a HyperLogLog sketch, e.g. one restored with UnmarshalIPMap
+func (m *IPConcurrentHyperLogLog) Merge(other IPMap) error {
+	switch o := other.(type) {
+	case *IPConcurrentHyperLogLog:
+		return m.Concurrent.Merge(o.HyperLogLog())
+	case *IPHyperLogLog:
+		return m.Concurrent.Merge(o.HyperLogLog)
+	}
+	return fmt.Errorf("can't merge %T into %T", other, m)
+}
+
+// MarshalBinary saves the sketch as a HyperLogLog, it is restored as an IPHyperLogLog
+func (m *IPConcurrentHyperLogLog) MarshalBinary() ([]byte, error) {
+	return m.HyperLogLog().MarshalBinary()
+}
+
+// snapshot copies the sketch to an IPHyperLogLog
+func (m *IPConcurrentHyperLogLog) snapshot() *IPHyperLogLog {
+	return &IPHyperLogLog{m.HyperLogLog()}
+}
@@ -0,0 +1,179 @@
+package ipcounter
+
+import (
+	"awesomeProject/ipcounter/counters/countmin"
+	"awesomeProject/ipcounter/counters/frequency"
+	"awesomeProject/ipcounter/counters/spacesaving"
+	"fmt"
+)
+
+// FrequencyMap counts how often each IP is seen and reports the most frequent IPs
+type FrequencyMap interface {
+	Add(ip uint32)
+	// Top returns the n IPs seen most often, by decreasing count
+	Top(n int) []IPCount
+	// Total returns the number of IPs seen, duplicates included
+	Total() uint64
+}
+
+// ForkableFrequencyMap is implemented by frequency counters that can be forked like Forkable counters,
+// in parallel mode each worker fills its own fork without locking and the forks are merged at the end
+type ForkableFrequencyMap interface {
+	FrequencyMap
+	ForkFrequencyMap() FrequencyMap
+	MergeFrequencyMap(other FrequencyMap) error
+}
+
+// IPCount is an IP and the number of times it was seen
+type IPCount struct {
+	IP    uint32
+	Count uint64
+	Error uint64 // Maximum overestimation of an approximate count, the true count is between Count-Error and Count
+}
+
+func (c IPCount) String() string {
+	if c.Error != 0 {
+		return fmt.Sprintf("%s %d (overestimated by at most %d)", FormatIP(c.IP), c.Count, c.Error)
+	}
+	return fmt.Sprintf("%s %d", FormatIP(c.IP), c.Count)
+}
+
+// IPFrequency counts the occurrences of each IP exactly, memory grows with the number of unique IPs.
+// The IPs must not be hashed, or the reported IPs are the hashes.
+type IPFrequency struct {
+	table *frequency.Table
+}
+
+func (m *IPFrequency) Add(ip uint32) {
+	m.table.Add(ip)
+}
+
+// Count returns the number of unique IPs
+func (m *IPFrequency) Count() uint64 {
+	return uint64(m.table.Len())
+}
+
+// Total returns the number of IPs seen, duplicates included
+func (m *IPFrequency) Total() uint64 {
+	return m.table.Total()
+}
+
+// Top returns the n IPs seen most often, by decreasing count
+func (m *IPFrequency) Top(n int) []IPCount {
+	entries := m.table.Top(n)
+	top := make([]IPCount, len(entries))
+	for i, entry := range entries {
+		top[i] = IPCount{IP: entry.Key, Count: entry.Count}
+	}
+	return top
+}
+
+// Histogram returns how many IPs were seen once, twice, 3-10 times and so on
+func (m *IPFrequency) Histogram() []frequency.HistogramBucket {
+	return m.table.Histogram()
+}
+
+func (m *IPFrequency) Merge(other IPMap) error {
+	o, ok := other.(*IPFrequency)
+	if !ok {
+		return fmt.Errorf("can't merge %T into %T", other, m)
+	}
+	m.table.Merge(o.table)
+	return nil
+}
+
+func (m *IPFrequency) Fork() IPMap {
+	return &IPFrequency{table: frequency.New(0)}
+}
+
+func (m *IPFrequency) ForkFrequencyMap() FrequencyMap {
+	return &IPFrequency{table: frequency.New(0)}
+}
+
+func (m *IPFrequency) MergeFrequencyMap(other FrequencyMap) error {
+	o, ok := other.(*IPFrequency)
+	if !ok {
+		return fmt.Errorf("can't merge %T into %T", other, m)
+	}
+	return m.Merge(o)
+}
+
+// IPSpaceSaving finds the most frequent IPs with the Space-Saving summary of k counters.
+// Every IP seen more than N/k times is reported, with a count overestimated by at most N/k.
+type IPSpaceSaving struct {
+	summary *spacesaving.Summary
+}
+
+func (m *IPSpaceSaving) Add(ip uint32) {
+	m.summary.Add(ip)
+}
+
+func (m *IPSpaceSaving) Top(n int) []IPCount {
+	return ipCounts(m.summary.Top(n))
+}
+
+func (m *IPSpaceSaving) Total() uint64 {
+	return m.summary.Total()
+}
+
+func (m *IPSpaceSaving) ForkFrequencyMap() FrequencyMap {
+	summary, _ := spacesaving.New(m.summary.Capacity())
+	return &IPSpaceSaving{summary}
+}
+
+// MergeFrequencyMap adds the other summary, the error of the counts grows to at most N/k of the merged total
+func (m *IPSpaceSaving) MergeFrequencyMap(other FrequencyMap) error {
+	o, ok := other.(*IPSpaceSaving)
+	if !ok {
+		return fmt.Errorf("can't merge %T into %T", other, m)
+	}
+	return m.summary.Merge(o.summary)
+}
+
+// IPCountMin estimates the frequency of the IPs with a Count-Min sketch and tracks the most frequent ones.
+// A count is overestimated by at most epsilon*N with probability 1-delta.
+type IPCountMin struct {
+	sketch  *countmin.Sketch
+	epsilon float64
+	delta   float64
+	k       int
+}
+
+func (m *IPCountMin) Add(ip uint32) {
+	m.sketch.Add(ip)
+}
+
+func (m *IPCountMin) Top(n int) []IPCount {
+	return ipCounts(m.sketch.Top(n))
+}
+
+func (m *IPCountMin) Total() uint64 {
+	return m.sketch.Total()
+}
+
+func (m *IPCountMin) ForkFrequencyMap() FrequencyMap {
+	sketch, _ := countmin.New(m.epsilon, m.delta, m.k)
+	return &IPCountMin{sketch: sketch, epsilon: m.epsilon, delta: m.delta, k: m.k}
+}
+
+func (m *IPCountMin) MergeFrequencyMap(other FrequencyMap) error {
+	o, ok := other.(*IPCountMin)
+	if !ok {
+		return fmt.Errorf("can't merge %T into %T", other, m)
+	}
+	return m.sketch.Merge(o.sketch)
+}
+
+// Estimate returns the estimated count of the IP, never lower than the true count
+func (m *IPCountMin) Estimate(ip uint32) uint64 {
+	return m.sketch.Estimate(ip)
+}
+
+// ipCounts converts the entries of a heavy hitters sketch, countmin.Entry is the same type as spacesaving.Entry
+func ipCounts(entries []spacesaving.Entry) []IPCount {
+	counts := make([]IPCount, len(entries))
+	for i, entry := range entries {
+		counts[i] = IPCount{IP: entry.Key, Count: entry.Count, Error: entry.Error}
+	}
+	return counts
+}
@@ -0,0 +1,100 @@
+package ipcounter
+
+import (
+	"awesomeProject/ipcounter/counters/hyperloglog"
+	"awesomeProject/ipcounter/counters/hyperloglogplus"
+	"awesomeProject/ipcounter/counters/hyperloglogplusbitmap"
+	"awesomeProject/ipcounter/utils/murmur3"
+	"fmt"
+)
+
+// HashIPMap is implemented by the sketches of the HyperLogLog family, they count 64-bit hashes.
+// Add hashes an IP with hashIP, AddHash takes a hash computed by the caller, e.g. of an IPv6 address.
+type HashIPMap interface {
+	IPMap
+	AddHash(hash uint64)
+}
+
+// hashIP spreads an IP over 64 bits with the murmur3 finalizer, it is a bijection so distinct IPs never collide.
+// FNV-1a of the 4 bytes leaves the register bits of close IPs nearly equal.
+func hashIP(ip uint32) uint64 {
+	return murmur3.Fmix64(uint64(ip))
+}
+
+type IPHyperLogLog struct {
+	*hyperloglog.HyperLogLog
+}
+
+func (m *IPHyperLogLog) Add(ip uint32) {
+	m.HyperLogLog.Add(hashIP(ip))
+}
+
+func (m *IPHyperLogLog) AddHash(hash uint64) {
+	m.HyperLogLog.Add(hash)
+}
+
+func (m *IPHyperLogLog) Merge(other IPMap) error {
+	o, ok := other.(*IPHyperLogLog)
+	if !ok {
+		return fmt.Errorf("can't merge %T into %T", other, m)
+	}
+	return m.HyperLogLog.Merge(o.HyperLogLog)
+}
+
+func (m *IPHyperLogLog) Fork() IPMap {
+	hll, _ := hyperloglog.New(m.Precision())
+	hll.SetHashFunc(m.HashFunc())
+	return &IPHyperLogLog{hll}
+}
+
+type IPHyperLogLogPlus struct {
+	*hyperloglogplus.HyperLogLogPlus
+}
+
+func (m *IPHyperLogLogPlus) Add(ip uint32) {
+	m.HyperLogLogPlus.Add(hashIP(ip))
+}
+
+func (m *IPHyperLogLogPlus) AddHash(hash uint64) {
+	m.HyperLogLogPlus.Add(hash)
+}
+
+func (m *IPHyperLogLogPlus) Merge(other IPMap) error {
+	o, ok := other.(*IPHyperLogLogPlus)
+	if !ok {
+		return fmt.Errorf("can't merge %T into %T", other, m)
+	}
+	return m.HyperLogLogPlus.Merge(o.HyperLogLogPlus)
+}
+
+func (m *IPHyperLogLogPlus) Fork() IPMap {
+	hll, _ := hyperloglogplus.New(m.Precision())
+	hll.SetHashFunc(m.HashFunc())
+	return &IPHyperLogLogPlus{hll}
+}
+
+type IPHyperLogLogPlusBitMap struct {
+	*hyperloglogplusbitmap.HyperLogLogPlusBitMap
+}
+
+func (m *IPHyperLogLogPlusBitMap) Add(ip uint32) {
+	m.HyperLogLogPlusBitMap.Add(hashIP(ip))
+}
+
+func (m *IPHyperLogLogPlusBitMap) AddHash(hash uint64) {
+	m.HyperLogLogPlusBitMap.Add(hash)
+}
+
+func (m *IPHyperLogLogPlusBitMap) Merge(other IPMap) error {
+	o, ok := other.(*IPHyperLogLogPlusBitMap)
+	if !ok {
+		return fmt.Errorf("can't merge %T into %T", other, m)
+	}
+	return m.HyperLogLogPlusBitMap.Merge(o.HyperLogLogPlusBitMap)
+}
+
+func (m *IPHyperLogLogPlusBitMap) Fork() IPMap {
+	hll, _ := hyperloglogplusbitmap.New(m.Precision())
+	hll.SetHashFunc(m.HashFunc())
+	return &IPHyperLogLogPlusBitMap{hll}
+}
@@ -0,0 +1,93 @@
+package ipcounter
+
+import (
+	"awesomeProject/ipcounter/utils/murmur3"
+	"fmt"
+)
+
+// IPv6Map counts unique IPv6 addresses
+type IPv6Map interface {
+	Add(ip IPv6)
+	Count() uint64
+}
+
+// ForkableIPv6Map is implemented by IPv6 counters that can be forked like Forkable counters,
+// in parallel mode each worker fills its own fork without locking and the forks are merged at the end.
+// Fork returns nil if the counter can't be forked, the workers then share it under a lock.
+type ForkableIPv6Map interface {
+	IPv6Map
+	Fork() IPv6Map
+	Merge(other IPv6Map) error
+}
+
+// IPv6Set is an exact set of IPv6 addresses, memory grows with the number of unique addresses
+type IPv6Set struct {
+	set map[IPv6]struct{}
+}
+
+func (m *IPv6Set) Add(ip IPv6) {
+	m.set[ip] = struct{}{}
+}
+
+func (m *IPv6Set) Count() uint64 {
+	return uint64(len(m.set))
+}
+
+func (m *IPv6Set) Fork() IPv6Map {
+	return &IPv6Set{set: make(map[IPv6]struct{})}
+}
+
+func (m *IPv6Set) Merge(other IPv6Map) error {
+	o, ok := other.(*IPv6Set)
+	if !ok {
+		return fmt.Errorf("can't merge %T into %T", other, m)
+	}
+	for ip := range o.set {
+		m.set[ip] = struct{}{}
+	}
+	return nil
+}
+
+// IPv6Sketch hashes IPv6 addresses to 64 bits and adds them to an approximate counter, e.g. a HyperLogLog
+type IPv6Sketch struct {
+	sketch HashIPMap
+}
+
+// Add uses murmur3, FNV-1a doesn't mix the last bytes of a 16-byte key into the register index well enough
+func (m *IPv6Sketch) Add(ip IPv6) {
+	h1, _ := murmur3.Sum128(ip[:])
+	m.sketch.AddHash(h1)
+}
+
+func (m *IPv6Sketch) Count() uint64 {
+	return m.sketch.Count()
+}
+
+// Fork returns nil if the sketch isn't Forkable
+func (m *IPv6Sketch) Fork() IPv6Map {
+	forkable, ok := m.sketch.(Forkable)
+	if !ok {
+		return nil
+	}
+	return &IPv6Sketch{sketch: forkable.Fork().(HashIPMap)}
+}
+
+func (m *IPv6Sketch) Merge(other IPv6Map) error {
+	o, ok := other.(*IPv6Sketch)
+	if !ok {
+		return fmt.Errorf("can't merge %T into %T", other, m)
+	}
+	mergeable, ok := m.sketch.(Mergeable)
+	if !ok {
+		return fmt.Errorf("can't merge into %T", m.sketch)
+	}
+	return mergeable.Merge(o.sketch)
+}
+
+// StandardError returns the relative standard error of the wrapped sketch, 0 if it is unknown
+func (m *IPv6Sketch) StandardError() float64 {
+	if estimator, ok := m.sketch.(ErrorEstimator); ok {
+		return estimator.StandardError()
+	}
+	return 0
+}
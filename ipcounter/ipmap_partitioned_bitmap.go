@@ -0,0 +1,81 @@
+package ipcounter
+
+import (
+	"awesomeProject/ipcounter/counters/bitmap"
+	"awesomeProject/ipcounter/counters/header"
+	"fmt"
+)
+
+// bitMapBytes is the size of a bitmap of the whole address space, 512 MB
+const bitMapBytes = 1 << 32 / 8
+
+// MaxPartitions is the largest number of partitions of IPPartitionedBitMap, its bitmap takes a single page
+const MaxPartitions = bitMapBytes / bitmap.PageSize
+
+// PartitionedIPMap is implemented by exact counters that hold a single partition of the address space at a time.
+// CountIPFromFile reads the file once per partition and the counts of the partitions are summed,
+// so such a counter counts a single file.
+type PartitionedIPMap interface {
+	IPMap
+	// Partitions returns the number of partitions, the number of passes over the file
+	Partitions() int
+	// Partition returns the partition counted
+	Partition() int
+	// NextPartition keeps the count of the partition and starts counting the next one
+	NextPartition()
+}
+
+// IPPartitionedBitMap is an exact counter in a bitmap of 2^32/partitions bits: it only adds the IPs whose top bits
+// select the partition counted, the IPs of the other partitions are added in the other passes.
+// The bitmap is filled without locks like IPAtomicBitMap.
+type IPPartitionedBitMap struct {
+	bm        *bitmap.Atomic
+	shift     uint8  // Number of low bits of the IPs set in the bitmap
+	partition uint32 // Partition counted, the top bits of its IPs
+	counted   uint64 // Unique IPs of the previous partitions
+}
+
+func (m *IPPartitionedBitMap) Add(ip uint32) {
+	if ip>>m.shift == m.partition {
+		m.bm.SetBit(ip - m.partition<<m.shift)
+	}
+}
+
+func (m *IPPartitionedBitMap) ConcurrentAdd() {}
+
+// Count returns the unique IPs of the partitions counted so far
+func (m *IPPartitionedBitMap) Count() uint64 {
+	return m.counted + m.bm.Count()
+}
+
+func (m *IPPartitionedBitMap) Partitions() int {
+	return 1 << (32 - m.shift)
+}
+
+func (m *IPPartitionedBitMap) Partition() int {
+	return int(m.partition)
+}
+
+// NextPartition adds the IPs of the bitmap to the count and clears it for the next partition
+func (m *IPPartitionedBitMap) NextPartition() {
+	m.counted += m.bm.Count()
+	m.bm.Clear()
+	m.partition++
+}
+
+func (m *IPPartitionedBitMap) SetHashFunc(hashFunc header.HashFunc) {
+	m.bm.SetHashFunc(hashFunc)
+}
+
+// PartitionsForMemory returns the smallest number of partitions whose bitmap fits in maxMemory bytes,
+// 1 if a bitmap of the whole address space fits
+func PartitionsForMemory(maxMemory int64) (int, error) {
+	if maxMemory < bitmap.PageSize {
+		return 0, fmt.Errorf("a memory limit of %d bytes is too low, at least %d bytes are needed", maxMemory, bitmap.PageSize)
+	}
+	partitions := 1
+	for int64(bitMapBytes/partitions) > maxMemory {
+		partitions *= 2
+	}
+	return partitions, nil
+}
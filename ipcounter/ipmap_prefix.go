@@ -0,0 +1,91 @@
+package ipcounter
+
+import (
+	"awesomeProject/ipcounter/counters/prefix"
+	"fmt"
+	"iter"
+	"sync"
+)
+
+// PrefixCount is a network prefix and its number of unique hosts
+type PrefixCount struct {
+	CIDR
+	Hosts uint64
+}
+
+func (p PrefixCount) String() string {
+	return fmt.Sprintf("%v: %d unique hosts", p.CIDR, p.Hosts)
+}
+
+// IPPrefixes counts the unique IPs exactly and breaks them down by network prefix, e.g. /8, /16 and /24
+type IPPrefixes struct {
+	aggregator *prefix.Aggregator
+	locks      [1 << prefix.MinLength]sync.Mutex
+}
+
+func (m *IPPrefixes) Add(ip uint32) {
+	m.aggregator.Add(ip)
+}
+
+// AddBatch adds IPs concurrently with other workers. The prefixes don't span several /8s,
+// so only the /8 of each IP is locked.
+func (m *IPPrefixes) AddBatch(ips []uint32) {
+	for _, ip := range ips {
+		lock := &m.locks[ip>>(32-prefix.MinLength)]
+		lock.Lock()
+		m.aggregator.Add(ip)
+		lock.Unlock()
+	}
+}
+
+func (m *IPPrefixes) Count() uint64 {
+	return m.aggregator.Count()
+}
+
+func (m *IPPrefixes) Merge(other IPMap) error {
+	o, ok := other.(*IPPrefixes)
+	if !ok {
+		return fmt.Errorf("can't merge %T into %T", other, m)
+	}
+	return m.aggregator.Merge(o.aggregator)
+}
+
+// Contains reports whether the IP was seen
+func (m *IPPrefixes) Contains(ip uint32) bool {
+	return m.aggregator.Contains(ip)
+}
+
+// SortedIPs lists the unique IPs in increasing order
+func (m *IPPrefixes) SortedIPs() iter.Seq[uint32] {
+	return func(yield func(uint32) bool) {
+		it := m.aggregator.Hosts().Iterator()
+		for ip, ok := it.Next(); ok; ip, ok = it.Next() {
+			if !yield(ip) {
+				return
+			}
+		}
+	}
+}
+
+// Lengths returns the tracked prefix lengths in increasing order
+func (m *IPPrefixes) Lengths() []uint8 {
+	return m.aggregator.Lengths()
+}
+
+// Prefixes returns the number of distinct prefixes of the length seen
+func (m *IPPrefixes) Prefixes(length uint8) (uint64, error) {
+	return m.aggregator.Prefixes(length)
+}
+
+// TopPrefixes returns the n prefixes of the length with the most unique hosts
+func (m *IPPrefixes) TopPrefixes(length uint8, n int) ([]PrefixCount, error) {
+	top, err := m.aggregator.Top(length, n)
+	if err != nil {
+		return nil, err
+	}
+	counts := make([]PrefixCount, len(top))
+	for i, p := range top {
+		counts[i] = PrefixCount{CIDR: CIDR{IP: p.IP, Bits: p.Length}, Hosts: p.Hosts}
+	}
+	return counts, nil
+}
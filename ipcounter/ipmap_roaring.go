@@ -0,0 +1,68 @@
+package ipcounter
+
+import (
+	"awesomeProject/ipcounter/counters/header"
+	"awesomeProject/ipcounter/counters/roaring"
+	"fmt"
+	"iter"
+)
+
+// IPRoaring is an exact counter like IPBitMap, its memory is proportional to the IPs instead of the address space
+type IPRoaring struct {
+	rb *roaring.Bitmap
+}
+
+func (m *IPRoaring) Add(ip uint32) {
+	m.rb.Add(ip)
+}
+
+func (m *IPRoaring) Count() uint64 {
+	return m.rb.Count()
+}
+
+func (m *IPRoaring) Merge(other IPMap) error {
+	o, ok := other.(*IPRoaring)
+	if !ok {
+		return fmt.Errorf("can't merge %T into %T", other, m)
+	}
+	return m.rb.Merge(o.rb)
+}
+
+func (m *IPRoaring) Fork() IPMap {
+	rb := roaring.New()
+	rb.SetHashFunc(m.rb.HashFunc())
+	return &IPRoaring{rb}
+}
+
+// Contains reports whether the IP was seen
+func (m *IPRoaring) Contains(ip uint32) bool {
+	return m.rb.Contains(ip)
+}
+
+// SortedIPs lists the IPs of the bitmap, the bitmap is already sorted
+func (m *IPRoaring) SortedIPs() iter.Seq[uint32] {
+	return m.rb.Values()
+}
+
+func (m *IPRoaring) SetHashFunc(hashFunc header.HashFunc) {
+	m.rb.SetHashFunc(hashFunc)
+}
+
+func (m *IPRoaring) HashFunc() header.HashFunc {
+	return m.rb.HashFunc()
+}
+
+// MarshalBinary saves the containers in their smallest representation
+func (m *IPRoaring) MarshalBinary() ([]byte, error) {
+	m.rb.RunOptimize()
+	return m.rb.MarshalBinary()
+}
+
+func (m *IPRoaring) UnmarshalBinary(data []byte) error {
+	rb := roaring.New()
+	if err := rb.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	m.rb = rb
+	return nil
+}
@@ -1,7 +1,13 @@
 package ipcounter
 
+import (
+	"awesomeProject/ipcounter/counters/header"
+	"fmt"
+)
+
 type IPSet struct {
-	set map[uint32]struct{}
+	set      map[uint32]struct{}
+	hashFunc header.HashFunc
 }
 
 func (m *IPSet) Add(ip uint32) {
@@ -11,3 +17,29 @@ func (m *IPSet) Add(ip uint32) {
 func (m *IPSet) Count() uint64 {
 	return uint64(len(m.set))
 }
+
+func (m *IPSet) Merge(other IPMap) error {
+	o, ok := other.(*IPSet)
+	if !ok {
+		return fmt.Errorf("can't merge %T into %T", other, m)
+	}
+	if m.hashFunc != o.hashFunc {
+		return fmt.Errorf("can't merge sets of different hash functions: %v and %v", m.hashFunc, o.hashFunc)
+	}
+	for ip := range o.set {
+		m.set[ip] = struct{}{}
+	}
+	return nil
+}
+
+func (m *IPSet) Fork() IPMap {
+	return &IPSet{set: make(map[uint32]struct{}), hashFunc: m.hashFunc}
+}
+
+func (m *IPSet) SetHashFunc(hashFunc header.HashFunc) {
+	m.hashFunc = hashFunc
+}
+
+func (m *IPSet) HashFunc() header.HashFunc {
+	return m.hashFunc
+}
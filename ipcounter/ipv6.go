@@ -0,0 +1,133 @@
+package ipcounter
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+var (
+	ErrInvalidGroup     = errors.New("IPv6 group must have 1 to 4 hex digits")
+	ErrGroupCount       = errors.New("wrong number of IPv6 groups")
+	ErrMultipleEllipsis = errors.New("multiple \"::\" in IPv6 address")
+)
+
+// IPv6 is a 128-bit IPv6 address in network byte order
+type IPv6 [16]byte
+
+// ipv4MappedPrefix is the prefix of IPv4-mapped IPv6 addresses (::ffff:a.b.c.d)
+var ipv4MappedPrefix = [12]byte{10: 0xff, 11: 0xff}
+
+// To4 returns the IPv4 address of an IPv4-mapped address
+func (ip IPv6) To4() (uint32, bool) {
+	if [12]byte(ip[:12]) != ipv4MappedPrefix {
+		return 0, false
+	}
+	return binary.BigEndian.Uint32(ip[12:]), true
+}
+
+// parseIPv6 parses an IPv6 address, including the compressed "::" form and a trailing dotted IPv4 part.
+// A trailing carriage return is allowed for files with Windows line endings.
+func parseIPv6(data []byte) (IPv6, error) {
+	var ip IPv6
+	if len(data) > 0 && data[len(data)-1] == '\r' {
+		data = data[:len(data)-1]
+	}
+	if len(data) == 0 {
+		return ip, ErrEmptyLine
+	}
+
+	// The position of "::" in the address, the groups after it are moved to the end
+	ellipsis := -1
+	if len(data) >= 2 && data[0] == ':' && data[1] == ':' {
+		ellipsis = 0
+		data = data[2:]
+	}
+
+	i := 0
+	for i < len(ip) && len(data) > 0 {
+		var group uint32
+		digits := 0
+		for digits < len(data) {
+			value, ok := hexValue(data[digits])
+			if !ok {
+				break
+			}
+			group = group<<4 | value
+			digits++
+			if digits > 4 {
+				return IPv6{}, ErrInvalidGroup
+			}
+		}
+		if digits == 0 {
+			return IPv6{}, ErrInvalidGroup
+		}
+
+		// Trailing IPv4 part, e.g. ::ffff:10.0.0.1
+		if digits < len(data) && data[digits] == '.' {
+			if i > len(ip)-4 || (ellipsis < 0 && i != len(ip)-4) {
+				return IPv6{}, ErrGroupCount
+			}
+			ipv4, err := parseIPStrict(data)
+			if err != nil {
+				return IPv6{}, err
+			}
+			binary.BigEndian.PutUint32(ip[i:], ipv4)
+			i += 4
+			data = nil
+			break
+		}
+
+		ip[i] = byte(group >> 8)
+		ip[i+1] = byte(group)
+		i += 2
+		data = data[digits:]
+		if len(data) == 0 {
+			break
+		}
+
+		if data[0] != ':' {
+			return IPv6{}, ErrInvalidCharacter
+		}
+		if len(data) == 1 {
+			return IPv6{}, ErrInvalidGroup
+		}
+		data = data[1:]
+		if data[0] == ':' {
+			if ellipsis >= 0 {
+				return IPv6{}, ErrMultipleEllipsis
+			}
+			ellipsis = i
+			data = data[1:]
+		}
+	}
+	if len(data) != 0 {
+		return IPv6{}, ErrGroupCount
+	}
+
+	// Expand "::", it stands for at least one zero group
+	if i < len(ip) {
+		if ellipsis < 0 {
+			return IPv6{}, ErrGroupCount
+		}
+		shift := len(ip) - i
+		copy(ip[ellipsis+shift:], ip[ellipsis:i])
+		for j := ellipsis; j < ellipsis+shift; j++ {
+			ip[j] = 0
+		}
+	} else if ellipsis >= 0 {
+		return IPv6{}, ErrGroupCount
+	}
+	return ip, nil
+}
+
+func hexValue(b byte) (uint32, bool) {
+	switch {
+	case b >= '0' && b <= '9':
+		return uint32(b - '0'), true
+	case b >= 'a' && b <= 'f':
+		return uint32(b-'a') + 10, true
+	case b >= 'A' && b <= 'F':
+		return uint32(b-'A') + 10, true
+	}
+	return 0, false
+}
@@ -0,0 +1,148 @@
+package ipcounter
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Result describes a single counting run
+type Result struct {
+	Count          uint64        // Number of unique IPs in the counter after the run, IPv4 and IPv6 combined
+	CountIPv4      uint64        // Number of unique IPv4 addresses, including IPv4-mapped IPv6 addresses
+	CountIPv6      uint64        // Number of unique IPv6 addresses, 0 unless IPv6 counting is enabled
+	Lines          uint64        // Total number of lines seen
+	ValidIPs       uint64        // Lines counted as IPs
+	InvalidLines   uint64        // Lines rejected by the strict parser
+	Bytes          int64         // Bytes of text processed, after decompression
+	Chunks         int           // Number of chunks processed
+	Workers        int           // Maximum number of worker goroutines
+	Passes         int           // Number of passes over the input, one per partition of a PartitionedIPMap
+	MmapTime       time.Duration // Time spent mapping and unmapping the file
+	ReadTime       time.Duration // Time spent reading and decompressing a stream
+	ParseTime      time.Duration // Time spent parsing lines, summed over the workers
+	CountTime      time.Duration // Time spent adding IPs to the counter and computing the count, summed over the workers
+	Elapsed        time.Duration // Wall time of the run
+	EstimatedError float64       // Relative standard error of the count, 0 for exact counters
+}
+
+func (r *Result) String() string {
+	return fmt.Sprintf("count=%d ipv4=%d ipv6=%d lines=%d valid=%d invalid=%d bytes=%d chunks=%d workers=%d passes=%d "+
+		"mmap=%v read=%v parse=%v count=%v elapsed=%v error=%.4f",
+		r.Count, r.CountIPv4, r.CountIPv6, r.Lines, r.ValidIPs, r.InvalidLines, r.Bytes, r.Chunks, r.Workers, r.Passes,
+		r.MmapTime, r.ReadTime, r.ParseTime, r.CountTime, r.Elapsed, r.EstimatedError)
+}
+
+// standardError returns the relative standard error of an approximate counter, 0 for exact counters
+func standardError(mp any) float64 {
+	if estimator, ok := mp.(ErrorEstimator); ok {
+		return estimator.StandardError()
+	}
+	return 0
+}
+
+// combinedError returns the relative standard error of the sum of two independent counts
+func combinedError(count1 uint64, error1 float64, count2 uint64, error2 float64) float64 {
+	total := float64(count1 + count2)
+	if total == 0 {
+		return math.Max(error1, error2)
+	}
+	deviation1 := error1 * float64(count1)
+	deviation2 := error2 * float64(count2)
+	return math.Sqrt(deviation1*deviation1+deviation2*deviation2) / total
+}
+
+// chunkStats describes the lines processed in a chunk, line numbers of invalid lines are relative to the chunk
+type chunkStats struct {
+	lines        uint64
+	valid        uint64
+	bytes        int64
+	invalid      []InvalidLine
+	invalidCount uint64
+	parseTime    time.Duration
+	countTime    time.Duration
+}
+
+func (stats *chunkStats) addInvalid(line []byte, offset int64, err error) {
+	stats.invalidCount++
+	if len(stats.invalid) == MaxReportedLines {
+		return
+	}
+	if len(line) > maxReportedLineLength {
+		line = line[:maxReportedLineLength]
+	}
+	stats.invalid = append(stats.invalid, InvalidLine{
+		Line:   stats.lines,
+		Offset: offset,
+		Text:   string(line),
+		Err:    err,
+	})
+}
+
+// ingestion accumulates the statistics of the chunks in input order
+type ingestion struct {
+	Result
+	policy  ValidationPolicy
+	invalid ValidationError
+}
+
+func newIngestion(policy ValidationPolicy) *ingestion {
+	return &ingestion{Result: Result{Passes: 1}, policy: policy}
+}
+
+func (in *ingestion) add(stats chunkStats) {
+	for _, line := range stats.invalid {
+		if len(in.invalid.Lines) == MaxReportedLines {
+			break
+		}
+		line.Line += in.Lines
+		in.invalid.Lines = append(in.invalid.Lines, line)
+	}
+	in.invalid.Count += stats.invalidCount
+	in.Lines += stats.lines
+	in.ValidIPs += stats.valid
+	in.InvalidLines += stats.invalidCount
+	in.Bytes += stats.bytes
+	in.Chunks++
+	in.ParseTime += stats.parseTime
+	in.CountTime += stats.countTime
+}
+
+// addPass adds another pass over the same input, only its bytes and times are added since its lines were already counted
+func (in *ingestion) addPass(pass *ingestion) {
+	in.Passes++
+	in.Bytes += pass.Bytes
+	in.MmapTime += pass.MmapTime
+	in.ReadTime += pass.ReadTime
+	in.ParseTime += pass.ParseTime
+	in.CountTime += pass.CountTime
+	in.setWorkers(pass.Workers)
+}
+
+// setWorkers records the number of workers that processed the data at the same time
+func (in *ingestion) setWorkers(workers int) {
+	if workers > in.Workers {
+		in.Workers = workers
+	}
+}
+
+// failed reports whether counting must stop
+func (in *ingestion) failed() bool {
+	return in.policy == ValidationFail && in.invalid.Count > 0
+}
+
+// err returns the validation error according to the policy
+func (in *ingestion) err() error {
+	if in.invalid.Count == 0 {
+		return nil
+	}
+	switch in.policy {
+	case ValidationFail:
+		// Other chunks may have been processed after the first invalid line, only the first one is reported
+		return &ValidationError{Lines: in.invalid.Lines[:1], Count: 1}
+	case ValidationCollect:
+		invalid := in.invalid
+		return &invalid
+	}
+	return nil
+}
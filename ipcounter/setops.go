@@ -0,0 +1,143 @@
+package ipcounter
+
+import "fmt"
+
+// MembershipIPMap is implemented by exact counters that can tell whether they contain an IP
+type MembershipIPMap interface {
+	SortedIPMap
+	Contains(ip uint32) bool
+}
+
+// SetCounts are the number of unique IPs of two counters A and B and of their combinations
+type SetCounts struct {
+	A            uint64
+	B            uint64
+	Union        uint64 // A ∪ B
+	Intersection uint64 // A ∩ B
+	OnlyA        uint64 // A \ B
+	OnlyB        uint64 // B \ A
+	Exact        bool   // The counts of sketches are estimates
+}
+
+// SymmetricDifference returns the number of IPs in exactly one of the counters, A ⊕ B
+func (c SetCounts) SymmetricDifference() uint64 {
+	return c.OnlyA + c.OnlyB
+}
+
+// Jaccard returns the Jaccard similarity |A ∩ B| / |A ∪ B|, 0 when both counters are empty
+func (c SetCounts) Jaccard() float64 {
+	if c.Union == 0 {
+		return 0
+	}
+	return float64(c.Intersection) / float64(c.Union)
+}
+
+// snapshot copies the counters filled without locks to their plain type: an atomic bitmap to a bitmap
+// and a concurrent HyperLogLog to a HyperLogLog
+func snapshot(mp IPMap) IPMap {
+	switch m := mp.(type) {
+	case *IPAtomicBitMap:
+		return m.snapshot()
+	case *IPConcurrentHyperLogLog:
+		return m.snapshot()
+	}
+	return mp
+}
+
+// CompareIPMaps counts the union, the intersection and the differences of two counters of the same type
+// or of two exact counters, filled with the same hash function.
+// Bitmaps combine their words and roaring bitmaps their containers, the other exact counters, of the same type or not,
+// look up the IPs of one counter in the other.
+// Sketches only count a union: their intersection is estimated by inclusion–exclusion, |A|+|B|-|A ∪ B|,
+// so its absolute error is the error of the union and it is unreliable when the intersection is small.
+// Neither counter is modified.
+func CompareIPMaps(a, b IPMap) (SetCounts, error) {
+	a, b = snapshot(a), snapshot(b)
+	counts := SetCounts{A: a.Count(), B: b.Count()}
+
+	if bitMapA, ok := a.(*IPBitMap); ok {
+		if bitMapB, ok := b.(*IPBitMap); ok {
+			intersection, err := bitMapA.bm.AndCount(bitMapB.bm)
+			if err != nil {
+				return SetCounts{}, err
+			}
+			counts.Intersection = intersection
+			counts.Exact = true
+			return counts.withIntersection(), nil
+		}
+	}
+
+	if roaringA, ok := a.(*IPRoaring); ok {
+		if roaringB, ok := b.(*IPRoaring); ok {
+			intersection, err := roaringA.rb.AndCount(roaringB.rb)
+			if err != nil {
+				return SetCounts{}, err
+			}
+			counts.Intersection = intersection
+			counts.Exact = true
+			return counts.withIntersection(), nil
+		}
+	}
+
+	if exactA, ok := a.(MembershipIPMap); ok {
+		exactB, ok := b.(MembershipIPMap)
+		if !ok {
+			return SetCounts{}, fmt.Errorf("can't compare %T with %T", a, b)
+		}
+		// The same IP hashed by different functions is a different value in each counter
+		if hashA, hashB := hashFuncOf(a), hashFuncOf(b); hashA != hashB {
+			return SetCounts{}, fmt.Errorf("can't compare counters of different hash functions: %v and %v", hashA, hashB)
+		}
+		// Look up the IPs of the smaller counter
+		if counts.A > counts.B {
+			exactA, exactB = exactB, exactA
+		}
+		for ip := range exactA.SortedIPs() {
+			if exactB.Contains(ip) {
+				counts.Intersection++
+			}
+		}
+		counts.Exact = true
+		return counts.withIntersection(), nil
+	}
+
+	forkable, ok := a.(Forkable)
+	if !ok {
+		return SetCounts{}, fmt.Errorf("can't compare %T", a)
+	}
+	union := forkable.Fork()
+	if err := union.(Mergeable).Merge(a); err != nil {
+		return SetCounts{}, err
+	}
+	if err := union.(Mergeable).Merge(b); err != nil {
+		return SetCounts{}, err
+	}
+	// The estimates may not be consistent with each other, the intersection is clamped to a possible size
+	intersection := int64(counts.A) + int64(counts.B) - int64(union.Count())
+	counts.Intersection = uint64(min(max(intersection, 0), int64(min(counts.A, counts.B))))
+	return counts.withIntersection(), nil
+}
+
+// withIntersection derives the union and the differences from the sizes and the intersection
+func (c SetCounts) withIntersection() SetCounts {
+	c.Union = c.A + c.B - c.Intersection
+	c.OnlyA = c.A - c.Intersection
+	c.OnlyB = c.B - c.Intersection
+	return c
+}
+
+// Contains reports whether the IP is set in the bitmap
+func (m *IPBitMap) Contains(ip uint32) bool {
+	return m.bm.GetBit(ip)
+}
+
+// Contains reports whether the IP is in the set
+func (m *IPSet) Contains(ip uint32) bool {
+	_, ok := m.set[ip]
+	return ok
+}
+
+// Contains reports whether the IP was seen
+func (m *IPFrequency) Contains(ip uint32) bool {
+	return m.table.Get(ip) > 0
+}
@@ -0,0 +1,101 @@
+package zstd
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+var errCorrupted = errors.New("zstd: corrupted input")
+
+// loadLE64 loads up to 8 bytes starting at offset as a little-endian value, missing bytes are zero
+func loadLE64(data []byte, offset int) uint64 {
+	if offset+8 <= len(data) {
+		return binary.LittleEndian.Uint64(data[offset:])
+	}
+	var value uint64
+	for i := offset; i < len(data); i++ {
+		value |= uint64(data[i]) << (8 * (i - offset))
+	}
+	return value
+}
+
+// forwardBitReader reads bits from the least significant bit of the first byte upwards.
+// It is used for FSE table descriptions.
+type forwardBitReader struct {
+	data   []byte
+	bitPos int
+}
+
+func (br *forwardBitReader) peek(n uint8) uint64 {
+	return (loadLE64(br.data, br.bitPos>>3) >> (br.bitPos & 7)) & (1<<n - 1)
+}
+
+func (br *forwardBitReader) skip(n uint8) {
+	br.bitPos += int(n)
+}
+
+func (br *forwardBitReader) overflow() bool {
+	return br.bitPos > len(br.data)*8
+}
+
+// bytesRead returns the number of bytes touched by the reader, the last one may be partially used
+func (br *forwardBitReader) bytesRead() int {
+	return (br.bitPos + 7) >> 3
+}
+
+// backwardBitReader reads bits from the end of the stream towards its start, as used by
+// Huffman and FSE encoded streams. The stream ends with a marker bit set to 1.
+type backwardBitReader struct {
+	data   []byte
+	bitPos int // Number of unread bits, the next bit read is bitPos-1
+}
+
+func newBackwardBitReader(data []byte) (*backwardBitReader, error) {
+	if len(data) == 0 || data[len(data)-1] == 0 {
+		return nil, errCorrupted
+	}
+	lastByte := data[len(data)-1]
+	highBit := 7
+	for lastByte>>highBit == 0 {
+		highBit--
+	}
+	return &backwardBitReader{
+		data:   data,
+		bitPos: (len(data)-1)*8 + highBit,
+	}, nil
+}
+
+// peek returns the next n bits (n <= 56) without consuming them, bits beyond the start of the stream are zero
+func (br *backwardBitReader) peek(n uint8) uint64 {
+	if n == 0 {
+		return 0
+	}
+	start := br.bitPos - int(n)
+	if start >= 0 {
+		return (loadLE64(br.data, start>>3) >> (start & 7)) & (1<<n - 1)
+	}
+	available := int(n) + start
+	if available <= 0 {
+		return 0
+	}
+	return (loadLE64(br.data, 0) & (1<<available - 1)) << -start
+}
+
+func (br *backwardBitReader) read(n uint8) uint64 {
+	value := br.peek(n)
+	br.bitPos -= int(n)
+	return value
+}
+
+func (br *backwardBitReader) skip(n uint8) {
+	br.bitPos -= int(n)
+}
+
+// overflow reports whether more bits were consumed than the stream contains
+func (br *backwardBitReader) overflow() bool {
+	return br.bitPos < 0
+}
+
+func (br *backwardBitReader) finished() bool {
+	return br.bitPos == 0
+}
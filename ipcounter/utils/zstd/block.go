@@ -0,0 +1,377 @@
+package zstd
+
+import "encoding/binary"
+
+const (
+	maxBlockSize = 128 << 10
+
+	literalsTypeRaw        = 0
+	literalsTypeRLE        = 1
+	literalsTypeCompressed = 2
+	literalsTypeTreeless   = 3
+
+	compressionModePredefined = 0
+	compressionModeRLE        = 1
+	compressionModeFSE        = 2
+	compressionModeRepeat     = 3
+
+	maxLiteralsLengthCode = 35
+	maxMatchLengthCode    = 52
+	maxOffsetCode         = 31
+)
+
+// Predefined distributions, RFC 8878 section 3.1.1.3.2.2
+var (
+	predefinedLiteralsLength = mustBuildFSETable([]int16{
+		4, 3, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 1, 1, 1,
+		2, 2, 2, 2, 2, 2, 2, 2, 2, 3, 2, 1, 1, 1, 1, 1,
+		-1, -1, -1, -1,
+	}, 6)
+	predefinedMatchLength = mustBuildFSETable([]int16{
+		1, 4, 3, 2, 2, 2, 2, 2, 2, 1, 1, 1, 1, 1, 1, 1,
+		1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
+		1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, -1, -1,
+		-1, -1, -1, -1, -1,
+	}, 6)
+	predefinedOffset = mustBuildFSETable([]int16{
+		1, 1, 1, 1, 1, 1, 2, 2, 2, 1, 1, 1, 1, 1, 1, 1,
+		1, 1, 1, 1, 1, 1, 1, 1, -1, -1, -1, -1, -1,
+	}, 5)
+)
+
+// Baselines and extra bits of the literals length and match length codes
+var (
+	literalsLengthBaseline = [maxLiteralsLengthCode + 1]uint32{
+		0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15,
+		16, 18, 20, 22, 24, 28, 32, 40, 48, 64, 128, 256, 512, 1024, 2048, 4096,
+		8192, 16384, 32768, 65536,
+	}
+	literalsLengthBits = [maxLiteralsLengthCode + 1]uint8{
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		1, 1, 1, 1, 2, 2, 3, 3, 4, 6, 7, 8, 9, 10, 11, 12,
+		13, 14, 15, 16,
+	}
+	matchLengthBaseline = [maxMatchLengthCode + 1]uint32{
+		3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18,
+		19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32, 33, 34,
+		35, 37, 39, 41, 43, 47, 51, 59, 67, 83, 99, 131, 259, 515, 1027, 2051,
+		4099, 8195, 16387, 32771, 65539,
+	}
+	matchLengthBits = [maxMatchLengthCode + 1]uint8{
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		1, 1, 1, 1, 2, 2, 3, 3, 4, 4, 5, 7, 8, 9, 10, 11,
+		12, 13, 14, 15, 16,
+	}
+)
+
+func mustBuildFSETable(normCounts []int16, accuracyLog uint8) *fseTable {
+	table, err := buildFSETable(normCounts, accuracyLog)
+	if err != nil {
+		panic(err)
+	}
+	return table
+}
+
+// blockDecoder keeps the state shared between the compressed blocks of a frame
+type blockDecoder struct {
+	huffman        *huffmanTable
+	literalsLength *fseTable
+	offset         *fseTable
+	matchLength    *fseTable
+	repeatOffsets  [3]uint32
+	literals       []byte
+}
+
+func (d *blockDecoder) reset() {
+	d.huffman = nil
+	d.literalsLength = nil
+	d.offset = nil
+	d.matchLength = nil
+	d.repeatOffsets = [3]uint32{1, 4, 8}
+}
+
+// decodeCompressedBlock decodes a compressed block and appends the result to the history
+func (d *blockDecoder) decodeCompressedBlock(data []byte, history []byte) ([]byte, error) {
+	literals, literalsSize, err := d.decodeLiterals(data)
+	if err != nil {
+		return nil, err
+	}
+	return d.decodeSequences(data[literalsSize:], literals, history)
+}
+
+// decodeLiterals decodes the literals section and returns the literals and the section size
+func (d *blockDecoder) decodeLiterals(data []byte) ([]byte, int, error) {
+	if len(data) == 0 {
+		return nil, 0, errCorrupted
+	}
+	literalsType := data[0] & 3
+	sizeFormat := (data[0] >> 2) & 3
+
+	switch literalsType {
+	case literalsTypeRaw, literalsTypeRLE:
+		var regeneratedSize, headerSize int
+		switch sizeFormat {
+		case 0, 2:
+			regeneratedSize, headerSize = int(data[0]>>3), 1
+		case 1:
+			if len(data) < 2 {
+				return nil, 0, errCorrupted
+			}
+			regeneratedSize, headerSize = int(data[0]>>4)|int(data[1])<<4, 2
+		case 3:
+			if len(data) < 3 {
+				return nil, 0, errCorrupted
+			}
+			regeneratedSize, headerSize = int(data[0]>>4)|int(data[1])<<4|int(data[2])<<12, 3
+		}
+		if regeneratedSize > maxBlockSize {
+			return nil, 0, errCorrupted
+		}
+		if literalsType == literalsTypeRaw {
+			if len(data) < headerSize+regeneratedSize {
+				return nil, 0, errCorrupted
+			}
+			return data[headerSize : headerSize+regeneratedSize], headerSize + regeneratedSize, nil
+		}
+		if len(data) < headerSize+1 {
+			return nil, 0, errCorrupted
+		}
+		literals := d.literalsBuffer(regeneratedSize)
+		for i := range literals {
+			literals[i] = data[headerSize]
+		}
+		return literals, headerSize + 1, nil
+	}
+
+	// Compressed and treeless literals
+	headerSize, sizeBits, numStreams := 3, uint(10), 4
+	switch sizeFormat {
+	case 0:
+		numStreams = 1
+	case 2:
+		headerSize, sizeBits = 4, 14
+	case 3:
+		headerSize, sizeBits = 5, 18
+	}
+	if len(data) < headerSize {
+		return nil, 0, errCorrupted
+	}
+	header := loadLE64(data[:headerSize], 0)
+	regeneratedSize := int((header >> 4) & (1<<sizeBits - 1))
+	compressedSize := int((header >> (4 + sizeBits)) & (1<<sizeBits - 1))
+	if regeneratedSize > maxBlockSize || len(data) < headerSize+compressedSize {
+		return nil, 0, errCorrupted
+	}
+	compressed := data[headerSize : headerSize+compressedSize]
+
+	if literalsType == literalsTypeCompressed {
+		table, treeSize, err := readHuffmanTable(compressed)
+		if err != nil {
+			return nil, 0, err
+		}
+		d.huffman = table
+		compressed = compressed[treeSize:]
+	} else if d.huffman == nil {
+		return nil, 0, errCorrupted
+	}
+
+	literals := d.literalsBuffer(regeneratedSize)
+	if numStreams == 1 {
+		if err := d.huffman.decodeStream(compressed, literals); err != nil {
+			return nil, 0, err
+		}
+		return literals, headerSize + compressedSize, nil
+	}
+
+	// Four streams with a jump table of the first three stream sizes
+	if len(compressed) < 6 {
+		return nil, 0, errCorrupted
+	}
+	streamSizes := [4]int{
+		int(binary.LittleEndian.Uint16(compressed[0:])),
+		int(binary.LittleEndian.Uint16(compressed[2:])),
+		int(binary.LittleEndian.Uint16(compressed[4:])),
+	}
+	compressed = compressed[6:]
+	streamSizes[3] = len(compressed) - streamSizes[0] - streamSizes[1] - streamSizes[2]
+	if streamSizes[3] < 0 {
+		return nil, 0, errCorrupted
+	}
+	segmentSize := (regeneratedSize + 3) / 4
+	if 3*segmentSize > regeneratedSize {
+		return nil, 0, errCorrupted
+	}
+	out := literals
+	for i, streamSize := range streamSizes {
+		size := segmentSize
+		if i == 3 {
+			size = len(out)
+		}
+		if err := d.huffman.decodeStream(compressed[:streamSize], out[:size]); err != nil {
+			return nil, 0, err
+		}
+		compressed = compressed[streamSize:]
+		out = out[size:]
+	}
+	return literals, headerSize + compressedSize, nil
+}
+
+func (d *blockDecoder) literalsBuffer(size int) []byte {
+	if cap(d.literals) < size {
+		d.literals = make([]byte, size, maxBlockSize)
+	}
+	return d.literals[:size]
+}
+
+// decodeSequences decodes the sequences section and executes the sequences
+func (d *blockDecoder) decodeSequences(data []byte, literals []byte, history []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, errCorrupted
+	}
+	numSequences := int(data[0])
+	switch {
+	case numSequences == 0:
+		return append(history, literals...), nil
+	case numSequences < 128:
+		data = data[1:]
+	case numSequences < 255:
+		if len(data) < 2 {
+			return nil, errCorrupted
+		}
+		numSequences = (numSequences-128)<<8 + int(data[1])
+		data = data[2:]
+	default:
+		if len(data) < 3 {
+			return nil, errCorrupted
+		}
+		numSequences = int(data[1]) + int(data[2])<<8 + 0x7f00
+		data = data[3:]
+	}
+
+	if len(data) == 0 {
+		return nil, errCorrupted
+	}
+	modes := data[0]
+	data = data[1:]
+	var err error
+	if d.literalsLength, data, err = readSequenceTable(data, modes>>6, d.literalsLength, predefinedLiteralsLength, maxLiteralsLengthCode, 9); err != nil {
+		return nil, err
+	}
+	if d.offset, data, err = readSequenceTable(data, (modes>>4)&3, d.offset, predefinedOffset, maxOffsetCode, 8); err != nil {
+		return nil, err
+	}
+	if d.matchLength, data, err = readSequenceTable(data, (modes>>2)&3, d.matchLength, predefinedMatchLength, maxMatchLengthCode, 9); err != nil {
+		return nil, err
+	}
+
+	br, err := newBackwardBitReader(data)
+	if err != nil {
+		return nil, err
+	}
+	var literalsLengthState, offsetState, matchLengthState fseState
+	literalsLengthState.init(br, d.literalsLength)
+	offsetState.init(br, d.offset)
+	matchLengthState.init(br, d.matchLength)
+
+	for i := 0; i < numSequences; i++ {
+		offsetCode := offsetState.symbol()
+		matchLengthCode := matchLengthState.symbol()
+		literalsLengthCode := literalsLengthState.symbol()
+		if offsetCode > maxOffsetCode || matchLengthCode > maxMatchLengthCode || literalsLengthCode > maxLiteralsLengthCode {
+			return nil, errCorrupted
+		}
+
+		offsetValue := uint32(1)<<offsetCode + uint32(br.read(offsetCode))
+		matchLength := matchLengthBaseline[matchLengthCode] + uint32(br.read(matchLengthBits[matchLengthCode]))
+		literalsLength := literalsLengthBaseline[literalsLengthCode] + uint32(br.read(literalsLengthBits[literalsLengthCode]))
+		offset := d.resolveOffset(offsetValue, literalsLength)
+
+		if i != numSequences-1 {
+			literalsLengthState.update(br)
+			matchLengthState.update(br)
+			offsetState.update(br)
+		}
+		if br.overflow() {
+			return nil, errCorrupted
+		}
+
+		if int(literalsLength) > len(literals) {
+			return nil, errCorrupted
+		}
+		history = append(history, literals[:literalsLength]...)
+		literals = literals[literalsLength:]
+
+		start := len(history) - int(offset)
+		if offset == 0 || start < 0 {
+			return nil, errCorrupted
+		}
+		if int(offset) >= int(matchLength) {
+			history = append(history, history[start:start+int(matchLength)]...)
+		} else {
+			// Overlapping match repeats the last offset bytes
+			for j := 0; j < int(matchLength); j++ {
+				history = append(history, history[start+j])
+			}
+		}
+	}
+	if !br.finished() {
+		return nil, errCorrupted
+	}
+	return append(history, literals...), nil
+}
+
+// resolveOffset converts an offset value to an actual offset and updates the repeat offsets
+func (d *blockDecoder) resolveOffset(offsetValue, literalsLength uint32) uint32 {
+	if offsetValue > 3 {
+		offset := offsetValue - 3
+		d.repeatOffsets = [3]uint32{offset, d.repeatOffsets[0], d.repeatOffsets[1]}
+		return offset
+	}
+
+	index := offsetValue - 1
+	if literalsLength == 0 {
+		index++
+	}
+	switch index {
+	case 0:
+		return d.repeatOffsets[0]
+	case 1:
+		offset := d.repeatOffsets[1]
+		d.repeatOffsets[1] = d.repeatOffsets[0]
+		d.repeatOffsets[0] = offset
+		return offset
+	case 2:
+		offset := d.repeatOffsets[2]
+		d.repeatOffsets = [3]uint32{offset, d.repeatOffsets[0], d.repeatOffsets[1]}
+		return offset
+	default:
+		offset := d.repeatOffsets[0] - 1
+		d.repeatOffsets = [3]uint32{offset, d.repeatOffsets[0], d.repeatOffsets[1]}
+		return offset
+	}
+}
+
+// readSequenceTable returns the decoding table for the given compression mode and the remaining data
+func readSequenceTable(data []byte, mode uint8, previous, predefined *fseTable, maxSymbol int, maxAccuracyLog uint8) (*fseTable, []byte, error) {
+	switch mode {
+	case compressionModePredefined:
+		return predefined, data, nil
+	case compressionModeRLE:
+		if len(data) == 0 || int(data[0]) > maxSymbol {
+			return nil, nil, errCorrupted
+		}
+		return rleFSETable(data[0]), data[1:], nil
+	case compressionModeFSE:
+		table, size, err := readFSETable(data, maxSymbol, maxAccuracyLog)
+		if err != nil {
+			return nil, nil, err
+		}
+		return table, data[size:], nil
+	default:
+		if previous == nil {
+			return nil, nil, errCorrupted
+		}
+		return previous, data, nil
+	}
+}
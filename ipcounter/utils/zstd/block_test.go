@@ -0,0 +1,69 @@
+package zstd
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBlockDecoder_ResolveOffset(t *testing.T) {
+	// RFC 8878 section 3.1.2.5, the repeat offsets start as 1, 4 and 8
+	tests := []struct {
+		name           string
+		offsetValue    uint32
+		literalsLength uint32
+		want           uint32
+		wantRepeat     [3]uint32
+	}{
+		{"New offset", 20, 1, 17, [3]uint32{17, 1, 4}},
+		{"Repeat 1", 1, 1, 1, [3]uint32{1, 4, 8}},
+		{"Repeat 2", 2, 1, 4, [3]uint32{4, 1, 8}},
+		{"Repeat 3", 3, 1, 8, [3]uint32{8, 1, 4}},
+		{"Repeat 2 without literals", 1, 0, 4, [3]uint32{4, 1, 8}},
+		{"Repeat 3 without literals", 2, 0, 8, [3]uint32{8, 1, 4}},
+		{"Repeat 1 minus 1 without literals", 3, 0, 0, [3]uint32{0, 1, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var d blockDecoder
+			d.reset()
+			if got := d.resolveOffset(tt.offsetValue, tt.literalsLength); got != tt.want {
+				t.Errorf("resolveOffset() = %d, want %d", got, tt.want)
+			}
+			if d.repeatOffsets != tt.wantRepeat {
+				t.Errorf("Repeat offsets = %v, want %v", d.repeatOffsets, tt.wantRepeat)
+			}
+		})
+	}
+}
+
+func TestBlockDecoder_RepeatWithoutPrevious(t *testing.T) {
+	var d blockDecoder
+	d.reset()
+
+	// Treeless literals of 1 byte in 1 byte, without a Huffman table from a previous block
+	treeless := []byte{literalsTypeTreeless | 1<<4, 1 << 6, 0, 0}
+	if _, _, err := d.decodeLiterals(treeless); !errors.Is(err, errCorrupted) {
+		t.Errorf("Expected %v for treeless literals, got %v", errCorrupted, err)
+	}
+
+	// One sequence with the literals length table repeated from a previous block
+	sequences := []byte{1, compressionModeRepeat << 6}
+	if _, err := d.decodeSequences(sequences, nil, nil); !errors.Is(err, errCorrupted) {
+		t.Errorf("Expected %v for a repeated sequence table, got %v", errCorrupted, err)
+	}
+}
+
+func TestBlockDecoder_RLELiterals(t *testing.T) {
+	var d blockDecoder
+	d.reset()
+
+	// RLE literals of 5 bytes and no sequences
+	history, err := d.decodeCompressedBlock([]byte{literalsTypeRLE | 5<<3, 'x', 0}, []byte("ab"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(history) != "abxxxxx" {
+		t.Errorf("Expected %q, got %q", "abxxxxx", history)
+	}
+}
@@ -0,0 +1,160 @@
+package zstd
+
+import "math/bits"
+
+// fseEntry is a single state of an FSE decoding table
+type fseEntry struct {
+	symbol   uint8
+	nbBits   uint8
+	newState uint16
+}
+
+// fseTable is an FSE decoding table with 2^accuracyLog states
+type fseTable struct {
+	accuracyLog uint8
+	entries     []fseEntry
+}
+
+// readFSETable parses an FSE table description and returns the table and the number of bytes used
+func readFSETable(data []byte, maxSymbol int, maxAccuracyLog uint8) (*fseTable, int, error) {
+	if len(data) == 0 {
+		return nil, 0, errCorrupted
+	}
+	br := &forwardBitReader{data: data}
+	accuracyLog := uint8(br.peek(4)) + 5
+	br.skip(4)
+	if accuracyLog > maxAccuracyLog {
+		return nil, 0, errCorrupted
+	}
+
+	normCounts := make([]int16, 0, maxSymbol+1)
+	remaining := (1 << accuracyLog) + 1
+	threshold := 1 << accuracyLog
+	nbBits := accuracyLog + 1
+	previousZero := false
+	for remaining > 1 && len(normCounts) <= maxSymbol {
+		if previousZero {
+			// Each 2-bit flag repeats zero probabilities, 3 means more flags follow
+			for {
+				repeat := int(br.peek(2))
+				br.skip(2)
+				for i := 0; i < repeat; i++ {
+					normCounts = append(normCounts, 0)
+				}
+				if repeat != 3 {
+					break
+				}
+			}
+			if len(normCounts) > maxSymbol || br.overflow() {
+				return nil, 0, errCorrupted
+			}
+		}
+
+		maxValue := 2*threshold - 1 - remaining
+		var count int
+		if low := int(br.peek(nbBits - 1)); low < maxValue {
+			count = low
+			br.skip(nbBits - 1)
+		} else {
+			count = int(br.peek(nbBits))
+			if count >= threshold {
+				count -= maxValue
+			}
+			br.skip(nbBits)
+		}
+		// Probability -1 means "less than 1", it still takes one state
+		count--
+		if count < 0 {
+			remaining += count
+		} else {
+			remaining -= count
+		}
+		normCounts = append(normCounts, int16(count))
+		previousZero = count == 0
+		for remaining < threshold {
+			nbBits--
+			threshold >>= 1
+		}
+	}
+	if remaining != 1 || br.overflow() {
+		return nil, 0, errCorrupted
+	}
+
+	table, err := buildFSETable(normCounts, accuracyLog)
+	if err != nil {
+		return nil, 0, err
+	}
+	return table, br.bytesRead(), nil
+}
+
+// buildFSETable spreads the symbols over the states as described in RFC 8878, section 4.1.1
+func buildFSETable(normCounts []int16, accuracyLog uint8) (*fseTable, error) {
+	tableSize := 1 << accuracyLog
+	entries := make([]fseEntry, tableSize)
+	symbolNext := make([]int, len(normCounts))
+
+	highThreshold := tableSize - 1
+	for symbol, count := range normCounts {
+		if count == -1 {
+			entries[highThreshold].symbol = uint8(symbol)
+			highThreshold--
+			symbolNext[symbol] = 1
+		} else {
+			symbolNext[symbol] = int(count)
+		}
+	}
+
+	step := (tableSize >> 1) + (tableSize >> 3) + 3
+	mask := tableSize - 1
+	position := 0
+	for symbol, count := range normCounts {
+		for i := 0; i < int(count); i++ {
+			entries[position].symbol = uint8(symbol)
+			position = (position + step) & mask
+			for position > highThreshold {
+				position = (position + step) & mask
+			}
+		}
+	}
+	if position != 0 {
+		return nil, errCorrupted
+	}
+
+	for i := range entries {
+		symbol := entries[i].symbol
+		nextState := symbolNext[symbol]
+		symbolNext[symbol]++
+		if nextState == 0 {
+			return nil, errCorrupted
+		}
+		nbBits := accuracyLog - uint8(bits.Len(uint(nextState))-1)
+		entries[i].nbBits = nbBits
+		entries[i].newState = uint16((nextState << nbBits) - tableSize)
+	}
+	return &fseTable{accuracyLog: accuracyLog, entries: entries}, nil
+}
+
+// rleFSETable returns a table that always decodes the same symbol and reads no bits
+func rleFSETable(symbol uint8) *fseTable {
+	return &fseTable{entries: []fseEntry{{symbol: symbol}}}
+}
+
+// fseState is the decoding state of a single FSE stream
+type fseState struct {
+	table *fseTable
+	state uint16
+}
+
+func (s *fseState) init(br *backwardBitReader, table *fseTable) {
+	s.table = table
+	s.state = uint16(br.read(table.accuracyLog))
+}
+
+func (s *fseState) symbol() uint8 {
+	return s.table.entries[s.state].symbol
+}
+
+func (s *fseState) update(br *backwardBitReader) {
+	entry := s.table.entries[s.state]
+	s.state = entry.newState + uint16(br.read(entry.nbBits))
+}
@@ -0,0 +1,166 @@
+package zstd
+
+import "math/bits"
+
+const (
+	maxHuffmanBits    = 11
+	maxHuffmanSymbols = 256
+	maxWeightsLog     = 6
+)
+
+type huffmanEntry struct {
+	symbol uint8
+	nbBits uint8
+}
+
+// huffmanTable is a single-symbol decoding table indexed by the next maxBits bits of the stream
+type huffmanTable struct {
+	maxBits uint8
+	entries []huffmanEntry
+}
+
+// readHuffmanTable parses a Huffman tree description and returns the table and the number of bytes used
+func readHuffmanTable(data []byte) (*huffmanTable, int, error) {
+	if len(data) == 0 {
+		return nil, 0, errCorrupted
+	}
+	headerByte := int(data[0])
+	var weights []uint8
+	var size int
+	if headerByte >= 128 {
+		// Direct representation, 4 bits per weight
+		numWeights := headerByte - 127
+		size = 1 + (numWeights+1)/2
+		if len(data) < size {
+			return nil, 0, errCorrupted
+		}
+		weights = make([]uint8, numWeights)
+		for i := range weights {
+			if i%2 == 0 {
+				weights[i] = data[1+i/2] >> 4
+			} else {
+				weights[i] = data[1+i/2] & 0xf
+			}
+		}
+	} else {
+		size = 1 + headerByte
+		if len(data) < size {
+			return nil, 0, errCorrupted
+		}
+		var err error
+		weights, err = decodeHuffmanWeights(data[1:size])
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	table, err := buildHuffmanTable(weights)
+	if err != nil {
+		return nil, 0, err
+	}
+	return table, size, nil
+}
+
+// decodeHuffmanWeights decodes FSE compressed weights, two interleaved states share one bitstream
+func decodeHuffmanWeights(data []byte) ([]uint8, error) {
+	table, tableSize, err := readFSETable(data, maxHuffmanSymbols-1, maxWeightsLog)
+	if err != nil {
+		return nil, err
+	}
+	br, err := newBackwardBitReader(data[tableSize:])
+	if err != nil {
+		return nil, err
+	}
+
+	var state1, state2 fseState
+	state1.init(br, table)
+	state2.init(br, table)
+	weights := make([]uint8, 0, maxHuffmanSymbols)
+	for {
+		if len(weights) > maxHuffmanSymbols-2 {
+			return nil, errCorrupted
+		}
+		weights = append(weights, state1.symbol())
+		state1.update(br)
+		if br.overflow() {
+			weights = append(weights, state2.symbol())
+			break
+		}
+		weights = append(weights, state2.symbol())
+		state2.update(br)
+		if br.overflow() {
+			weights = append(weights, state1.symbol())
+			break
+		}
+	}
+	return weights, nil
+}
+
+// buildHuffmanTable builds the decoding table, the weight of the last symbol is implied
+func buildHuffmanTable(weights []uint8) (*huffmanTable, error) {
+	if len(weights) == 0 || len(weights) >= maxHuffmanSymbols {
+		return nil, errCorrupted
+	}
+	var total uint32
+	for _, weight := range weights {
+		if weight > maxHuffmanBits {
+			return nil, errCorrupted
+		}
+		if weight > 0 {
+			total += 1 << (weight - 1)
+		}
+	}
+	if total == 0 {
+		return nil, errCorrupted
+	}
+
+	maxBits := uint8(bits.Len32(total))
+	leftover := uint32(1)<<maxBits - total
+	if maxBits > maxHuffmanBits || leftover&(leftover-1) != 0 {
+		return nil, errCorrupted
+	}
+	weights = append(weights, uint8(bits.Len32(leftover)))
+
+	// Codes are assigned in order of weight, lowest first, and by symbol within the same weight
+	var rankCount [maxHuffmanBits + 2]uint32
+	for _, weight := range weights {
+		rankCount[weight]++
+	}
+	var rankStart [maxHuffmanBits + 2]uint32
+	next := uint32(0)
+	for weight := 1; weight <= maxHuffmanBits+1; weight++ {
+		rankStart[weight] = next
+		next += rankCount[weight] << (weight - 1)
+	}
+
+	entries := make([]huffmanEntry, 1<<maxBits)
+	for symbol, weight := range weights {
+		if weight == 0 {
+			continue
+		}
+		length := uint32(1) << (weight - 1)
+		entry := huffmanEntry{symbol: uint8(symbol), nbBits: maxBits + 1 - weight}
+		for i := rankStart[weight]; i < rankStart[weight]+length; i++ {
+			entries[i] = entry
+		}
+		rankStart[weight] += length
+	}
+	return &huffmanTable{maxBits: maxBits, entries: entries}, nil
+}
+
+// decodeStream decodes exactly len(out) symbols from a single Huffman stream
+func (t *huffmanTable) decodeStream(data []byte, out []byte) error {
+	br, err := newBackwardBitReader(data)
+	if err != nil {
+		return err
+	}
+	for i := range out {
+		entry := t.entries[br.peek(t.maxBits)]
+		out[i] = entry.symbol
+		br.skip(entry.nbBits)
+	}
+	if !br.finished() {
+		return errCorrupted
+	}
+	return nil
+}
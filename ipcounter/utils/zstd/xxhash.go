@@ -0,0 +1,108 @@
+package zstd
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// XXH64 with seed 0, used for the optional content checksum of a frame
+const (
+	prime64_1 uint64 = 11400714785074694791
+	prime64_2 uint64 = 14029467366897019727
+	prime64_3 uint64 = 1609587929392839161
+	prime64_4 uint64 = 9650029242287828579
+	prime64_5 uint64 = 2870177450012600261
+)
+
+type xxhash64 struct {
+	v1, v2, v3, v4 uint64
+	total          uint64
+	buf            [32]byte
+	bufLen         int
+}
+
+func (x *xxhash64) reset() {
+	// The seed is 0, the additions wrap around
+	p1, p2 := prime64_1, prime64_2
+	x.v1 = p1 + p2
+	x.v2 = p2
+	x.v3 = 0
+	x.v4 = -p1
+	x.total = 0
+	x.bufLen = 0
+}
+
+func xxhashRound(acc, input uint64) uint64 {
+	acc += input * prime64_2
+	acc = bits.RotateLeft64(acc, 31)
+	return acc * prime64_1
+}
+
+func xxhashMergeRound(acc, value uint64) uint64 {
+	acc ^= xxhashRound(0, value)
+	return acc*prime64_1 + prime64_4
+}
+
+func (x *xxhash64) write(p []byte) {
+	x.total += uint64(len(p))
+	if x.bufLen > 0 {
+		n := copy(x.buf[x.bufLen:], p)
+		x.bufLen += n
+		p = p[n:]
+		if x.bufLen < len(x.buf) {
+			return
+		}
+		x.consume(x.buf[:])
+		x.bufLen = 0
+	}
+	for len(p) >= 32 {
+		x.consume(p[:32])
+		p = p[32:]
+	}
+	x.bufLen = copy(x.buf[:], p)
+}
+
+func (x *xxhash64) consume(block []byte) {
+	x.v1 = xxhashRound(x.v1, binary.LittleEndian.Uint64(block[0:]))
+	x.v2 = xxhashRound(x.v2, binary.LittleEndian.Uint64(block[8:]))
+	x.v3 = xxhashRound(x.v3, binary.LittleEndian.Uint64(block[16:]))
+	x.v4 = xxhashRound(x.v4, binary.LittleEndian.Uint64(block[24:]))
+}
+
+func (x *xxhash64) sum64() uint64 {
+	var h uint64
+	if x.total >= 32 {
+		h = bits.RotateLeft64(x.v1, 1) + bits.RotateLeft64(x.v2, 7) +
+			bits.RotateLeft64(x.v3, 12) + bits.RotateLeft64(x.v4, 18)
+		h = xxhashMergeRound(h, x.v1)
+		h = xxhashMergeRound(h, x.v2)
+		h = xxhashMergeRound(h, x.v3)
+		h = xxhashMergeRound(h, x.v4)
+	} else {
+		h = prime64_5
+	}
+	h += x.total
+
+	p := x.buf[:x.bufLen]
+	for len(p) >= 8 {
+		h ^= xxhashRound(0, binary.LittleEndian.Uint64(p))
+		h = bits.RotateLeft64(h, 27)*prime64_1 + prime64_4
+		p = p[8:]
+	}
+	if len(p) >= 4 {
+		h ^= uint64(binary.LittleEndian.Uint32(p)) * prime64_1
+		h = bits.RotateLeft64(h, 23)*prime64_2 + prime64_3
+		p = p[4:]
+	}
+	for _, b := range p {
+		h ^= uint64(b) * prime64_5
+		h = bits.RotateLeft64(h, 11) * prime64_1
+	}
+
+	h ^= h >> 33
+	h *= prime64_2
+	h ^= h >> 29
+	h *= prime64_3
+	h ^= h >> 32
+	return h
+}
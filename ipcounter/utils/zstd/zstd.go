@@ -0,0 +1,241 @@
+// Package zstd implements a streaming Zstandard decoder (RFC 8878).
+// Dictionaries are not supported.
+package zstd
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	// Magic is the little-endian magic number of a Zstandard frame
+	Magic uint32 = 0xFD2FB528
+
+	skippableMagic     uint32 = 0x184D2A50
+	skippableMagicMask uint32 = 0xFFFFFFF0
+
+	// maxWindowSize is the largest window the decoder accepts, the same default limit as the reference decoder
+	maxWindowSize = 1 << 27
+
+	blockTypeRaw        = 0
+	blockTypeRLE        = 1
+	blockTypeCompressed = 2
+)
+
+var (
+	errChecksum         = errors.New("zstd: checksum mismatch")
+	errDictionary       = errors.New("zstd: dictionaries are not supported")
+	errWindowTooLarge   = errors.New("zstd: window size is too large")
+	errInvalidMagic     = errors.New("zstd: invalid magic number")
+	errReservedBitSet   = errors.New("zstd: reserved bit is set")
+	errInvalidBlockType = errors.New("zstd: invalid block type")
+)
+
+// Reader decompresses a stream of concatenated Zstandard frames
+type Reader struct {
+	r *bufio.Reader
+	blockDecoder
+
+	history    []byte // Decoded data, at least the last windowSize bytes are kept for matches
+	out        []byte // Decoded data not returned to the caller yet
+	block      []byte // Compressed block buffer
+	windowSize int
+	inFrame    bool
+	lastBlock  bool
+	checksum   bool
+	hash       xxhash64
+	err        error
+}
+
+// NewReader creates a new Reader reading the given stream, the header of the first frame is read immediately
+func NewReader(r io.Reader) (*Reader, error) {
+	z := &Reader{
+		r:     bufio.NewReader(r),
+		block: make([]byte, maxBlockSize),
+	}
+	if err := z.readFrameHeader(); err != nil {
+		return nil, err
+	}
+	return z, nil
+}
+
+// Read implements io.Reader
+func (z *Reader) Read(p []byte) (int, error) {
+	for len(z.out) == 0 {
+		if z.err != nil {
+			return 0, z.err
+		}
+		z.err = z.decodeNext()
+	}
+	n := copy(p, z.out)
+	z.out = z.out[n:]
+	return n, nil
+}
+
+// decodeNext decodes the next block, or finishes the current frame and starts the next one
+func (z *Reader) decodeNext() error {
+	if !z.inFrame {
+		return z.readFrameHeader()
+	}
+	if z.lastBlock {
+		z.inFrame = false
+		return z.verifyChecksum()
+	}
+
+	var header [3]byte
+	if _, err := io.ReadFull(z.r, header[:]); err != nil {
+		return noEOF(err)
+	}
+	blockHeader := uint32(header[0]) | uint32(header[1])<<8 | uint32(header[2])<<16
+	z.lastBlock = blockHeader&1 == 1
+	blockType := (blockHeader >> 1) & 3
+	blockSize := int(blockHeader >> 3)
+	if blockSize > maxBlockSize {
+		return errCorrupted
+	}
+
+	// Drop the data that is out of the window, the pending output was fully consumed at this point
+	if len(z.history) > 2*z.windowSize+maxBlockSize {
+		kept := copy(z.history, z.history[len(z.history)-z.windowSize:])
+		z.history = z.history[:kept]
+	}
+	start := len(z.history)
+
+	switch blockType {
+	case blockTypeRaw:
+		z.history = append(z.history, make([]byte, blockSize)...)
+		if _, err := io.ReadFull(z.r, z.history[start:]); err != nil {
+			return noEOF(err)
+		}
+	case blockTypeRLE:
+		value, err := z.r.ReadByte()
+		if err != nil {
+			return noEOF(err)
+		}
+		for i := 0; i < blockSize; i++ {
+			z.history = append(z.history, value)
+		}
+	case blockTypeCompressed:
+		block := z.block[:blockSize]
+		if _, err := io.ReadFull(z.r, block); err != nil {
+			return noEOF(err)
+		}
+		history, err := z.decodeCompressedBlock(block, z.history)
+		if err != nil {
+			return err
+		}
+		z.history = history
+	default:
+		return errInvalidBlockType
+	}
+
+	z.out = z.history[start:]
+	if z.checksum {
+		z.hash.write(z.out)
+	}
+	return nil
+}
+
+// readFrameHeader reads the header of the next frame, skippable frames are ignored.
+// It returns io.EOF if the stream ends before a new frame.
+func (z *Reader) readFrameHeader() error {
+	var magic [4]byte
+	for {
+		if _, err := io.ReadFull(z.r, magic[:]); err != nil {
+			if err == io.ErrUnexpectedEOF {
+				return errCorrupted
+			}
+			return err
+		}
+		value := binary.LittleEndian.Uint32(magic[:])
+		if value == Magic {
+			break
+		}
+		if value&skippableMagicMask != skippableMagic {
+			return errInvalidMagic
+		}
+		if _, err := io.ReadFull(z.r, magic[:]); err != nil {
+			return noEOF(err)
+		}
+		if _, err := z.r.Discard(int(binary.LittleEndian.Uint32(magic[:]))); err != nil {
+			return noEOF(err)
+		}
+	}
+
+	descriptor, err := z.r.ReadByte()
+	if err != nil {
+		return noEOF(err)
+	}
+	contentSizeFlag := descriptor >> 6
+	singleSegment := descriptor&0x20 != 0
+	if descriptor&0x08 != 0 {
+		return errReservedBitSet
+	}
+	z.checksum = descriptor&0x04 != 0
+	dictionaryIDSize := [4]int{0, 1, 2, 4}[descriptor&3]
+	contentSizeSize := [4]int{0, 2, 4, 8}[contentSizeFlag]
+	if contentSizeFlag == 0 && singleSegment {
+		contentSizeSize = 1
+	}
+
+	var windowSize uint64
+	if !singleSegment {
+		windowDescriptor, err := z.r.ReadByte()
+		if err != nil {
+			return noEOF(err)
+		}
+		windowLog := 10 + uint(windowDescriptor>>3)
+		windowBase := uint64(1) << windowLog
+		windowSize = windowBase + (windowBase/8)*uint64(windowDescriptor&7)
+	}
+
+	var fields [12]byte
+	if _, err := io.ReadFull(z.r, fields[:dictionaryIDSize+contentSizeSize]); err != nil {
+		return noEOF(err)
+	}
+	if loadLE64(fields[:dictionaryIDSize], 0) != 0 {
+		return errDictionary
+	}
+	if singleSegment {
+		windowSize = loadLE64(fields[dictionaryIDSize:dictionaryIDSize+contentSizeSize], 0)
+		if contentSizeSize == 2 {
+			windowSize += 256
+		}
+	}
+	if windowSize > maxWindowSize {
+		return fmt.Errorf("%w: %d", errWindowTooLarge, windowSize)
+	}
+
+	z.windowSize = int(windowSize)
+	z.inFrame = true
+	z.lastBlock = false
+	z.history = z.history[:0]
+	z.hash.reset()
+	z.blockDecoder.reset()
+	return nil
+}
+
+func (z *Reader) verifyChecksum() error {
+	if !z.checksum {
+		return nil
+	}
+	var checksum [4]byte
+	if _, err := io.ReadFull(z.r, checksum[:]); err != nil {
+		return noEOF(err)
+	}
+	if binary.LittleEndian.Uint32(checksum[:]) != uint32(z.hash.sum64()) {
+		return errChecksum
+	}
+	return nil
+}
+
+// noEOF converts io.EOF to io.ErrUnexpectedEOF, the stream may only end between frames
+func noEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,195 @@
+package zstd
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+// testIPs returns the content of testdata/ips.zst, compressed with zstd -3 --target-compressed-block-size=1340.
+// The small blocks reuse the Huffman table of a previous block (treeless literals), the sequence tables
+// of a previous block (repeat mode) and the repeat offsets.
+func testIPs() []byte {
+	var b strings.Builder
+	state := uint32(1)
+	for i := 0; i < 4000; i++ {
+		state ^= state << 13
+		state ^= state >> 17
+		state ^= state << 5
+		ip := state % 64
+		fmt.Fprintf(&b, "10.%d.%d.%d\n", ip%4, ip*7%256, ip*31%256)
+	}
+	return []byte(b.String())
+}
+
+func readFixture(t *testing.T) []byte {
+	t.Helper()
+	data, err := os.ReadFile("testdata/ips.zst")
+	if err != nil {
+		t.Fatalf("Failed to read the fixture: %v", err)
+	}
+	return data
+}
+
+// frame builds a frame of a 1 KB window with the given blocks, the last block ends the frame
+func frame(checksum []byte, blocks ...[]byte) []byte {
+	data := binary.LittleEndian.AppendUint32(nil, Magic)
+	descriptor := byte(0)
+	if checksum != nil {
+		descriptor |= 0x04
+	}
+	data = append(data, descriptor, 0)
+	for _, block := range blocks {
+		data = append(data, block...)
+	}
+	return append(data, checksum...)
+}
+
+// block builds a block header followed by the content
+func block(last bool, blockType uint32, size int, content ...byte) []byte {
+	header := blockType<<1 | uint32(size)<<3
+	if last {
+		header |= 1
+	}
+	return append([]byte{byte(header), byte(header >> 8), byte(header >> 16)}, content...)
+}
+
+func decode(data []byte) ([]byte, error) {
+	r, err := NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+func TestReader_RawAndRLEBlocks(t *testing.T) {
+	data := frame(nil,
+		block(false, blockTypeRaw, 9, []byte("10.0.0.1\n")...),
+		block(false, blockTypeRLE, 4, '7'),
+		block(true, blockTypeRaw, 0),
+	)
+	got, err := decode(data)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(got) != "10.0.0.1\n7777" {
+		t.Errorf("Expected %q, got %q", "10.0.0.1\n7777", got)
+	}
+}
+
+func TestReader_CompressedBlocks(t *testing.T) {
+	got, err := decode(readFixture(t))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !bytes.Equal(got, testIPs()) {
+		t.Errorf("Decoded %d bytes, they don't match the %d bytes of the input", len(got), len(testIPs()))
+	}
+}
+
+func TestReader_MultipleFrames(t *testing.T) {
+	fixture := readFixture(t)
+	skippable := binary.LittleEndian.AppendUint32(nil, skippableMagic|7)
+	skippable = binary.LittleEndian.AppendUint32(skippable, 3)
+	skippable = append(skippable, "abc"...)
+
+	var data []byte
+	data = append(data, fixture...)
+	data = append(data, skippable...)
+	data = append(data, frame(nil, block(true, blockTypeRaw, 9, []byte("10.0.0.1\n")...))...)
+	data = append(data, fixture...)
+
+	got, err := decode(data)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := append(append(testIPs(), "10.0.0.1\n"...), testIPs()...)
+	if !bytes.Equal(got, want) {
+		t.Errorf("Decoded %d bytes, expected %d", len(got), len(want))
+	}
+
+	// A stream starting with a skippable frame
+	got, err = decode(append(skippable, fixture...))
+	if err != nil || !bytes.Equal(got, testIPs()) {
+		t.Errorf("Expected the fixture after a skippable frame, got %d bytes and error %v", len(got), err)
+	}
+}
+
+func TestReader_ChecksumMismatch(t *testing.T) {
+	data := readFixture(t)
+	data[len(data)-1] ^= 0xFF
+	if _, err := decode(data); !errors.Is(err, errChecksum) {
+		t.Errorf("Expected %v, got %v", errChecksum, err)
+	}
+
+	data = frame([]byte{1, 2, 3, 4}, block(true, blockTypeRaw, 3, 'a', 'b', 'c'))
+	if _, err := decode(data); !errors.Is(err, errChecksum) {
+		t.Errorf("Expected %v, got %v", errChecksum, err)
+	}
+}
+
+func TestReader_InvalidInput(t *testing.T) {
+	header := func(fields ...byte) []byte {
+		return append(binary.LittleEndian.AppendUint32(nil, Magic), fields...)
+	}
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"Empty", nil, io.EOF},
+		{"Truncated magic", header()[:2], errCorrupted},
+		{"Invalid magic", []byte{1, 2, 3, 4}, errInvalidMagic},
+		{"Reserved bit", header(0x08, 0), errReservedBitSet},
+		{"Dictionary", header(0x01, 0, 7), errDictionary},
+		{"Window too large", header(0, 0xF8), errWindowTooLarge},
+		{"Truncated frame header", header(0), io.ErrUnexpectedEOF},
+		{"No block", frame(nil), io.ErrUnexpectedEOF},
+		{"Reserved block type", frame(nil, block(true, 3, 0)), errInvalidBlockType},
+		{"Block too large", frame(nil, block(true, blockTypeRaw, maxBlockSize+1)), errCorrupted},
+		{"Truncated raw block", frame(nil, block(true, blockTypeRaw, 9, 'a')), io.ErrUnexpectedEOF},
+		{"Truncated RLE block", frame(nil, block(true, blockTypeRLE, 9)), io.ErrUnexpectedEOF},
+		{"Empty compressed block", frame(nil, block(true, blockTypeCompressed, 0)), errCorrupted},
+		{"Truncated checksum", frame([]byte{1, 2}, block(true, blockTypeRaw, 0)), io.ErrUnexpectedEOF},
+		{"Truncated skippable frame", append(binary.LittleEndian.AppendUint32(nil, skippableMagic), 9, 0, 0, 0, 'a'), io.ErrUnexpectedEOF},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decode(tt.data); !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestReader_TruncatedInput(t *testing.T) {
+	fixture := readFixture(t)
+	for n := range len(fixture) {
+		if _, err := decode(fixture[:n]); err == nil {
+			t.Fatalf("Expected an error for the input truncated to %d of %d bytes", n, len(fixture))
+		}
+	}
+}
+
+func TestReader_CorruptInput(t *testing.T) {
+	fixture := readFixture(t)
+	want := testIPs()
+	data := make([]byte, len(fixture))
+	for i := range fixture {
+		for _, mask := range []byte{0x01, 0x10, 0xFF} {
+			copy(data, fixture)
+			data[i] ^= mask
+			// Corruption must not panic, and corrupt data must be reported rather than returned
+			got, err := decode(data)
+			if err == nil && !bytes.Equal(got, want) {
+				t.Fatalf("Flipping %#x at byte %d returned corrupt data without an error", mask, i)
+			}
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package ipcounter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// ValidationPolicy defines how lines that are not valid IP addresses are handled
+type ValidationPolicy int
+
+const (
+	// ValidationNone parses lines without any checks, so invalid lines are counted as some IP
+	ValidationNone ValidationPolicy = iota
+	// ValidationFail stops counting at the first invalid line
+	ValidationFail
+	// ValidationSkip skips invalid lines
+	ValidationSkip
+	// ValidationCollect skips invalid lines and reports them in a *ValidationError together with the count
+	ValidationCollect
+)
+
+// MaxReportedLines The number of invalid lines kept in a ValidationError
+const MaxReportedLines = 100
+
+// maxReportedLineLength The number of bytes of an invalid line kept in the report
+const maxReportedLineLength = 64
+
+var (
+	ErrEmptyLine        = errors.New("empty line")
+	ErrInvalidCharacter = errors.New("invalid character")
+	ErrEmptyOctet       = errors.New("empty octet")
+	ErrInvalidOctet     = errors.New("octet must be a number from 0 to 255")
+	ErrOctetCount       = errors.New("wrong number of octets")
+	ErrIPv6Address      = errors.New("IPv6 address (use -ipv6)")
+)
+
+var validationPolicyNames = map[string]ValidationPolicy{
+	"none":    ValidationNone,
+	"fail":    ValidationFail,
+	"skip":    ValidationSkip,
+	"collect": ValidationCollect,
+}
+
+// ParseValidationPolicy returns the policy by its name: none, fail, skip or collect
+func ParseValidationPolicy(name string) (ValidationPolicy, error) {
+	policy, ok := validationPolicyNames[name]
+	if !ok {
+		return ValidationNone, fmt.Errorf("invalid validation policy: %s", name)
+	}
+	return policy, nil
+}
+
+// InvalidLine describes a line that is not a valid IP address
+type InvalidLine struct {
+	Line   uint64 // Line number, starting from 1
+	Offset int64  // Byte offset of the line in the input
+	Text   string // Content of the line, truncated to 64 bytes
+	Err    error
+}
+
+func (l InvalidLine) String() string {
+	return fmt.Sprintf("line %d (offset %d): %q: %v", l.Line, l.Offset, l.Text, l.Err)
+}
+
+// ValidationError is the summary of the invalid lines found in the input
+type ValidationError struct {
+	Lines []InvalidLine // The first MaxReportedLines invalid lines in input order
+	Count uint64        // Total number of invalid lines
+}
+
+func (e *ValidationError) Error() string {
+	if e.Count == 1 {
+		return fmt.Sprintf("invalid IP at %v", e.Lines[0])
+	}
+	return fmt.Sprintf("%d invalid lines, first at %v", e.Count, e.Lines[0])
+}
+
+// parseIPStrict converts a byte slice to a uint32 IP representation and rejects anything but a dotted quad,
+// IPv6 addresses with ErrIPv6Address.
+// A trailing carriage return is allowed for files with Windows line endings.
+func parseIPStrict(data []byte) (uint32, error) {
+	if len(data) > 0 && data[len(data)-1] == '\r' {
+		data = data[:len(data)-1]
+	}
+	if len(data) == 0 {
+		return 0, ErrEmptyLine
+	}
+	// Without an IPv6 counter IPv6 lines reach the IPv4 parser, their groups aren't octets
+	if bytes.IndexByte(data, ':') != -1 {
+		return 0, ErrIPv6Address
+	}
+
+	var ip uint32
+	var octet uint32
+	var digits, octets int
+	for _, b := range data {
+		switch {
+		case b >= '0' && b <= '9':
+			octet = octet*10 + uint32(b-'0')
+			digits++
+			if octet > 255 || digits > 3 {
+				return 0, ErrInvalidOctet
+			}
+		case b == '.':
+			if digits == 0 {
+				return 0, ErrEmptyOctet
+			}
+			octets++
+			if octets == 4 {
+				return 0, ErrOctetCount
+			}
+			ip = (ip << 8) | octet
+			octet, digits = 0, 0
+		default:
+			return 0, ErrInvalidCharacter
+		}
+	}
+	if digits == 0 {
+		return 0, ErrEmptyOctet
+	}
+	if octets != 3 {
+		return 0, ErrOctetCount
+	}
+	return (ip << 8) | octet, nil
+}
@@ -2,10 +2,17 @@ package main
 
 import (
 	"awesomeProject/ipcounter"
+	"encoding"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"io/fs"
 	"log"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -13,79 +20,553 @@ const (
 	hyperLogLogType     = "hyperloglog"
 	hyperLogLogPlusType = "hyperloglogplus"
 	bitmapType          = "bitmap"
+	roaringType         = "roaring"
 	setType             = "set"
+	frequencyType       = "frequency"
+	prefixType          = "prefix"
 )
 
+// stdinPath is the file path that reads IP addresses from stdin
+const stdinPath = "-"
+
+// stdoutPath is the -export path that writes the IPs to stdout
+const stdoutPath = "-"
+
+// stdinIsTerminal reports whether stdin is a terminal rather than a pipe or a file
+func stdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// fileList collects the values of a repeated flag
+type fileList []string
+
+func (f *fileList) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *fileList) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == compareCommand {
+		runCompare(os.Args[2:])
+		return
+	}
+
 	// Define command-line flags
-	filePath := flag.String("file", "", "Path to the file containing IP addresses")
-	counterType := flag.String("counter", hyperLogLogPlusType, "Type of counter to use (hyperloglogplus or bitmap)")
+	var filePaths fileList
+	flag.Var(&filePaths, "file", "Path, glob or directory with IP addresses, can be repeated, \"-\" reads from stdin (default \"-\" when stdin isn't a terminal)")
+	counterType := flag.String("counter", hyperLogLogPlusType, "Type of counter to use (hyperloglogplus, hyperloglog, bitmap, roaring, set, frequency or prefix)")
+	prefixLengths := flag.String("prefix-lengths", "8,16,24", "Comma separated prefix lengths broken down by the prefix counter, between 8 and 24")
+	recursive := flag.Bool("recursive", false, "Include files from subdirectories of directory inputs")
+	perFile := flag.Bool("per-file", false, "Print the number of new unique IPs contributed by each file")
+	printStats := flag.Bool("stats", false, "Print detailed ingestion statistics of each file")
+	savePath := flag.String("save", "", "Save the IPv4 counter to this file after counting, e.g. to merge daily sketches later")
+	var loadPaths fileList
+	flag.Var(&loadPaths, "load", "Merge a counter saved with -save before counting, can be repeated. The counter type must match")
+	countIPv6 := flag.Bool("ipv6", false, "Count IPv6 addresses separately from IPv4, IPv4-mapped addresses are counted as IPv4")
+	validation := flag.String("validate", "none", "Handling of invalid lines: none (no checks), fail, skip or collect (skip and report)")
+	hashName := flag.String("hash", "none", "Hash function applied to the IPs: none, fnv1a, fmix32 or murmur3. Sketches spread the IPs themselves, saved counters only merge with the same hash function")
+	hashSeed := flag.Uint("hash-seed", 0, "Seed of the murmur3 hash function")
+	topN := flag.Int("top", 10, "Number of most frequent IPs printed by the frequency counter and -heavy-hitters")
+	heavyHitters := flag.String("heavy-hitters", "none", "Also find the most frequent IPs in the same pass: none, exact, spacesaving or countmin")
+	exportPath := flag.String("export", "", "Write the unique IPs of an exact counter (bitmap, roaring, set, frequency or prefix) in sorted order to this file, \"-\" for stdout")
+	exportFormat := flag.String("export-format", "ips", "Format of -export: ips (one IP per line) or cidr (minimal covering CIDR prefixes)")
+	heavyHittersSize := flag.Int("heavy-hitters-size", 1000, "Number of IPs monitored by the spacesaving and countmin heavy hitters")
+	maxMemory := flag.String("max-memory", "", "Memory limit of the bitmap counter, e.g. 64MB. Below 512MB a single file is read once per partition of the address space")
 	flag.Parse()
 
-	// Check if the file path is provided
-	if *filePath == "" {
-		log.Println("Please provide a file path using the -file flag")
+	validationPolicy, err := ipcounter.ParseValidationPolicy(*validation)
+	if err != nil {
+		log.Fatal(err)
+	}
+	hasher, err := ipcounter.ParseHasher(*hashName, uint32(*hashSeed))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Files can be passed with -file flags and as positional arguments
+	inputs := append(filePaths, flag.Args()...)
+	if len(inputs) == 0 && len(loadPaths) == 0 {
+		// An interactive run without inputs would wait for IPs typed on the terminal
+		if stdinIsTerminal() {
+			log.Println("Please provide a file path using the -file flag or as an argument, or pipe the IPs to stdin")
+			flag.PrintDefaults()
+			os.Exit(1)
+		}
+		inputs = fileList{stdinPath}
+	}
+	files, err := expandInputs(inputs, *recursive)
+	if err != nil {
+		log.Println(err)
 		flag.PrintDefaults()
 		os.Exit(1)
 	}
 
-	// Create the counter based on the selected type
+	lengths, err := parsePrefixLengths(*prefixLengths)
+	if err != nil {
+		log.Fatal(err)
+	}
 	var counter *ipcounter.IPCounter
-	var err error
-	switch *counterType {
-	case hyperLogLogType:
-		counter, err = createHyperLogLogCounter()
-	case hyperLogLogPlusType:
-		counter, err = createHyperLogLogPlusCounter()
-	case bitmapType:
-		counter, err = createBitmapCounter()
-	case setType:
-		counter, err = createSetCounter()
-	default:
-		log.Fatalf("Invalid counter type: %s", *counterType)
+	if *maxMemory != "" {
+		counter, err = createMemoryBoundedCounter(*counterType, *maxMemory, hasher)
+	} else {
+		counter, err = createCounter(*counterType, hasher, lengths)
 	}
 	if err != nil {
 		log.Fatalf("Failed to create counter: %v", err)
 	}
+	if _, ok := counter.IPMap().(ipcounter.PartitionedIPMap); ok {
+		if len(files) != 1 || files[0] == stdinPath {
+			log.Fatalf("The file is read once per partition with -max-memory %s, a single file can be counted, not %v", *maxMemory, files)
+		}
+		if *savePath != "" || len(loadPaths) > 0 {
+			log.Fatalf("The counter only holds a partition of the IPs with -max-memory %s, it can't be saved or loaded", *maxMemory)
+		}
+	}
+	counter.SetValidationPolicy(validationPolicy)
+	if *exportPath != "" {
+		if _, ok := counter.IPMap().(ipcounter.SortedIPMap); !ok {
+			log.Fatalf("The %s counter can't export its IPs", *counterType)
+		}
+		if _, ok := hasher.(ipcounter.IdentityHasher); !ok {
+			log.Fatalf("Exported IPs must not be hashed, -export can't be used with -hash %s", *hashName)
+		}
+		if *exportFormat != "ips" && *exportFormat != "cidr" {
+			log.Fatalf("Invalid export format: %s", *exportFormat)
+		}
+	}
+	if *heavyHitters != "none" {
+		frequencyMap, err := createFrequencyMap(*heavyHitters, *heavyHittersSize)
+		if err != nil {
+			log.Fatalf("Failed to create heavy hitters counter: %v", err)
+		}
+		counter.SetFrequencyMap(frequencyMap)
+	}
+	if *countIPv6 {
+		ipv6Map, err := createIPv6Map(*counterType)
+		if err != nil {
+			log.Fatalf("Failed to create IPv6 counter: %v", err)
+		}
+		counter.SetIPv6Map(ipv6Map)
+	}
+
+	// The summary goes to stderr when the IPs are exported to stdout, so the exported stream only holds IPs
+	summary := io.Writer(os.Stdout)
+	if *exportPath == stdoutPath {
+		summary = os.Stderr
+	}
 
 	start := time.Now()
 
-	// Count IP addresses from the file
-	count, err := counter.CountIPFromFile(*filePath)
-	if err != nil {
-		log.Fatalf("Failed to count IP addresses from file %s: %v", *filePath, err)
+	// Saved counters are merged first, so -per-file only shows the IPs new to them
+	for _, loadPath := range loadPaths {
+		if err := loadCounter(counter, loadPath); err != nil {
+			log.Fatalf("Failed to load counter from %s: %v", loadPath, err)
+		}
+	}
+
+	// Count IP addresses from all files into the same counter
+	count := counter.IPMap().Count()
+	var result *ipcounter.Result
+	for _, filePath := range files {
+		previousCount := count
+		if filePath == stdinPath {
+			result, err = counter.CountIPFromReader(os.Stdin)
+		} else {
+			result, err = counter.CountIPFromFile(filePath)
+		}
+		var validationErr *ipcounter.ValidationError
+		if validationPolicy == ipcounter.ValidationCollect && errors.As(err, &validationErr) {
+			printInvalidLines(filePath, validationErr)
+		} else if err != nil {
+			log.Fatalf("Failed to count IP addresses from file %s: %v", filePath, err)
+		}
+		count = result.Count
+		if *printStats {
+			fmt.Fprintf(summary, "%s: %v\n", filePath, result)
+		}
+		if *perFile {
+			// Approximate counters may report a slightly lower count after adding a file
+			fmt.Fprintf(summary, "%s: %d new unique\n", filePath, int64(count)-int64(previousCount))
+		}
+	}
+
+	if *exportPath != "" {
+		if err := exportIPs(counter, *exportPath, *exportFormat); err != nil {
+			log.Fatalf("Failed to export IPs to %s: %v", *exportPath, err)
+		}
+	}
+
+	if *savePath != "" {
+		if err := saveCounter(counter, *savePath); err != nil {
+			log.Fatalf("Failed to save counter to %s: %v", *savePath, err)
+		}
 	}
 
 	elapsed := time.Since(start)
-	fmt.Printf("%s count: %d\n", *counterType, count)
-	fmt.Printf("Time elapsed: %v\n", elapsed)
+	fmt.Fprintf(summary, "%s count: %d\n", *counterType, count)
+	if *countIPv6 && result != nil {
+		fmt.Fprintf(summary, "IPv4 count: %d\n", result.CountIPv4)
+		fmt.Fprintf(summary, "IPv6 count: %d\n", result.CountIPv6)
+	}
+	if frequency, ok := counter.IPMap().(*ipcounter.IPFrequency); ok {
+		printFrequency(summary, frequency, *topN)
+	}
+	if frequencyMap := counter.FrequencyMap(); frequencyMap != nil {
+		printFrequency(summary, frequencyMap, *topN)
+	}
+	if prefixes, ok := counter.IPMap().(*ipcounter.IPPrefixes); ok {
+		printPrefixes(summary, prefixes, *topN)
+	}
+	fmt.Fprintf(summary, "Time elapsed: %v\n", elapsed)
+}
+
+// printFrequency prints the most frequent IPs, and the histogram of the number of occurrences of exact counters
+func printFrequency(w io.Writer, frequencyMap ipcounter.FrequencyMap, n int) {
+	fmt.Fprintf(w, "Top %d of %d IPs seen:\n", n, frequencyMap.Total())
+	for _, ipCount := range frequencyMap.Top(n) {
+		fmt.Fprintf(w, "  %v\n", ipCount)
+	}
+	frequency, ok := frequencyMap.(*ipcounter.IPFrequency)
+	if !ok {
+		return
+	}
+	fmt.Fprintln(w, "IPs by number of occurrences:")
+	for _, bucket := range frequency.Histogram() {
+		fmt.Fprintf(w, "  %-12v %d\n", bucket.String()+":", bucket.Keys)
+	}
 }
 
-func createHyperLogLogCounter() (*ipcounter.IPCounter, error) {
-	hyperloglog, err := ipcounter.NewHyperLogLog(14)
+// printPrefixes prints the number of distinct prefixes of each length and the prefixes with the most unique hosts
+func printPrefixes(w io.Writer, prefixes *ipcounter.IPPrefixes, n int) {
+	for _, length := range prefixes.Lengths() {
+		count, err := prefixes.Prefixes(length)
+		if err != nil {
+			log.Fatal(err)
+		}
+		top, err := prefixes.TopPrefixes(length, n)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Fprintf(w, "Distinct /%d prefixes: %d, top %d by unique hosts:\n", length, count, n)
+		for _, prefixCount := range top {
+			fmt.Fprintf(w, "  %v\n", prefixCount)
+		}
+	}
+}
+
+// parsePrefixLengths parses a comma separated list of prefix lengths, e.g. "8,16,24" or "/16,/24"
+func parsePrefixLengths(value string) ([]uint8, error) {
+	var lengths []uint8
+	for _, field := range strings.Split(value, ",") {
+		length, err := strconv.ParseUint(strings.TrimPrefix(strings.TrimSpace(field), "/"), 10, 8)
+		if err != nil {
+			return nil, fmt.Errorf("invalid prefix length %q: %w", field, err)
+		}
+		lengths = append(lengths, uint8(length))
+	}
+	return lengths, nil
+}
+
+// loadCounter merges a counter saved with saveCounter into the counter
+func loadCounter(counter *ipcounter.IPCounter, path string) error {
+	data, err := os.ReadFile(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create HyperLogLogPlus: %v", err)
+		return err
+	}
+	saved, err := ipcounter.UnmarshalIPMap(data)
+	if err != nil {
+		return err
+	}
+	return counter.Merge(saved)
+}
+
+// saveCounter writes the serialized IPv4 counter to the file
+func saveCounter(counter *ipcounter.IPCounter, path string) error {
+	marshaler, ok := counter.IPMap().(encoding.BinaryMarshaler)
+	if !ok {
+		return fmt.Errorf("%T can't be saved", counter.IPMap())
+	}
+	data, err := marshaler.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// exportIPs writes the sorted unique IPs of the counter, or their minimal CIDR prefixes, to the file or stdout
+func exportIPs(counter *ipcounter.IPCounter, path, format string) (err error) {
+	output := os.Stdout
+	if path != stdoutPath {
+		output, err = os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if closeErr := output.Close(); err == nil {
+				err = closeErr
+			}
+		}()
+	}
+
+	ips := counter.IPMap().(ipcounter.SortedIPMap).SortedIPs()
+	if format == "cidr" {
+		return ipcounter.WriteCIDRs(output, ips)
+	}
+	return ipcounter.WriteIPs(output, ips)
+}
+
+// printInvalidLines prints the summary of the invalid lines of a file to stderr
+func printInvalidLines(filePath string, validationErr *ipcounter.ValidationError) {
+	fmt.Fprintf(os.Stderr, "%s: %d invalid lines\n", filePath, validationErr.Count)
+	for _, line := range validationErr.Lines {
+		fmt.Fprintf(os.Stderr, "  %v\n", line)
+	}
+	if omitted := validationErr.Count - uint64(len(validationErr.Lines)); omitted > 0 {
+		fmt.Fprintf(os.Stderr, "  ... %d more\n", omitted)
+	}
+}
+
+// expandInputs resolves globs and directories to the list of files to process
+func expandInputs(inputs []string, recursive bool) ([]string, error) {
+	var files []string
+	for _, input := range inputs {
+		if input == stdinPath {
+			files = append(files, input)
+			continue
+		}
+
+		matches, err := filepath.Glob(input)
+		if err != nil {
+			return nil, fmt.Errorf("invalid file pattern %s: %w", input, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no files match %s", input)
+		}
+		for _, match := range matches {
+			info, err := os.Stat(match)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get file stat: %w", err)
+			}
+			if !info.IsDir() {
+				files = append(files, match)
+				continue
+			}
+			dirFiles, err := listDirectory(match, recursive)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list directory %s: %w", match, err)
+			}
+			files = append(files, dirFiles...)
+		}
+	}
+	return files, nil
+}
+
+// listDirectory returns the regular files of the directory, and of its subdirectories if recursive is set
+func listDirectory(dir string, recursive bool) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if path != dir && !recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.Type().IsRegular() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files, err
+}
+
+// createCounter creates the counter of the type, the frequency and prefix counters refuse hashed IPs.
+// The prefix counter breaks the IPs down by the prefix lengths.
+func createCounter(counterType string, hasher ipcounter.Hasher, prefixLengths []uint8) (*ipcounter.IPCounter, error) {
+	switch counterType {
+	case hyperLogLogType:
+		return createHyperLogLogCounter(hasher)
+	case hyperLogLogPlusType:
+		return createHyperLogLogPlusCounter(hasher)
+	case bitmapType:
+		if err := checkExactHasher(counterType, hasher); err != nil {
+			return nil, err
+		}
+		return createBitmapCounter(hasher)
+	case roaringType:
+		if err := checkExactHasher(counterType, hasher); err != nil {
+			return nil, err
+		}
+		return createRoaringCounter(hasher)
+	case setType:
+		if err := checkExactHasher(counterType, hasher); err != nil {
+			return nil, err
+		}
+		return createSetCounter(hasher)
+	case frequencyType:
+		if _, ok := hasher.(ipcounter.IdentityHasher); !ok {
+			return nil, fmt.Errorf("the frequency counter reports the IPs, it can't be used with -hash %v", hasher.HashFunc())
+		}
+		return createFrequencyCounter()
+	case prefixType:
+		if _, ok := hasher.(ipcounter.IdentityHasher); !ok {
+			return nil, fmt.Errorf("the prefix counter groups the IPs by network, it can't be used with -hash %v", hasher.HashFunc())
+		}
+		return createPrefixCounter(prefixLengths)
+	}
+	return nil, fmt.Errorf("invalid counter type: %s", counterType)
+}
+
+// checkExactHasher refuses the hash functions that aren't bijections for an exact counter,
+// distinct IPs with the same hash would be counted once
+func checkExactHasher(counterType string, hasher ipcounter.Hasher) error {
+	if !ipcounter.IsBijection(hasher) {
+		return fmt.Errorf("the %s counter is exact, it can only be used with -hash none or fmix32, not %v", counterType, hasher.HashFunc())
+	}
+	return nil
+}
+
+func createHyperLogLogCounter(hasher ipcounter.Hasher) (*ipcounter.IPCounter, error) {
+	hyperloglog, err := ipcounter.NewConcurrentHyperLogLog(14)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HyperLogLog: %v", err)
 	}
-	return ipcounter.NewIPCounter(hyperloglog, true, true), nil
+	return ipcounter.NewIPCounter(hyperloglog, true, hasher), nil
 }
 
-func createHyperLogLogPlusCounter() (*ipcounter.IPCounter, error) {
+func createHyperLogLogPlusCounter(hasher ipcounter.Hasher) (*ipcounter.IPCounter, error) {
 	hyperloglogplus, err := ipcounter.NewHyperLogLogPlusBitMap(14)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create HyperLogLogPlus: %v", err)
 	}
-	return ipcounter.NewIPCounter(hyperloglogplus, true, true), nil
+	return ipcounter.NewIPCounter(hyperloglogplus, true, hasher), nil
 }
 
-func createBitmapCounter() (*ipcounter.IPCounter, error) {
-	bitMap, err := ipcounter.NewIPBitMap()
+// createMemoryBoundedCounter creates a bitmap counter taking at most maxMemory, e.g. "64MB". When a bitmap of the
+// address space doesn't fit, the IPs are counted in partitions, one pass over the file each.
+func createMemoryBoundedCounter(counterType, maxMemory string, hasher ipcounter.Hasher) (*ipcounter.IPCounter, error) {
+	if counterType != bitmapType {
+		return nil, fmt.Errorf("-max-memory only applies to the bitmap counter, not %s", counterType)
+	}
+	if err := checkExactHasher(counterType, hasher); err != nil {
+		return nil, err
+	}
+	limit, err := parseMemorySize(maxMemory)
+	if err != nil {
+		return nil, err
+	}
+	partitions, err := ipcounter.PartitionsForMemory(limit)
+	if err != nil {
+		return nil, err
+	}
+	if partitions == 1 {
+		return createBitmapCounter(hasher)
+	}
+	partitioned, err := ipcounter.NewIPPartitionedBitMap(partitions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create PartitionedBitMap: %v", err)
+	}
+	return ipcounter.NewIPCounter(partitioned, true, hasher), nil
+}
+
+// parseMemorySize parses a number of bytes with an optional KB, MB or GB suffix of powers of 1024, e.g. "64MB"
+func parseMemorySize(value string) (int64, error) {
+	number := strings.ToUpper(strings.TrimSpace(value))
+	unit := int64(1)
+	for suffix, size := range map[string]int64{"KB": 1 << 10, "MB": 1 << 20, "GB": 1 << 30} {
+		if trimmed, ok := strings.CutSuffix(number, suffix); ok {
+			number, unit = trimmed, size
+			break
+		}
+	}
+	size, err := strconv.ParseInt(strings.TrimSuffix(number, "B"), 10, 64)
+	if err != nil || size <= 0 {
+		return 0, fmt.Errorf("invalid memory size %q, e.g. 64MB", value)
+	}
+	return size * unit, nil
+}
+
+func createBitmapCounter(hasher ipcounter.Hasher) (*ipcounter.IPCounter, error) {
+	bitMap, err := ipcounter.NewIPAtomicBitMap()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create BitmapCounter: %v", err)
 	}
-	return ipcounter.NewIPCounter(bitMap, true, false), nil
+	return ipcounter.NewIPCounter(bitMap, true, hasher), nil
+}
+
+func createRoaringCounter(hasher ipcounter.Hasher) (*ipcounter.IPCounter, error) {
+	roaring, err := ipcounter.NewIPRoaring()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create RoaringCounter: %v", err)
+	}
+	return ipcounter.NewIPCounter(roaring, true, hasher), nil
 }
 
-func createSetCounter() (*ipcounter.IPCounter, error) {
+func createFrequencyCounter() (*ipcounter.IPCounter, error) {
+	frequency, err := ipcounter.NewIPFrequency()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create FrequencyCounter: %v", err)
+	}
+	return ipcounter.NewIPCounter(frequency, true, nil), nil
+}
+
+func createPrefixCounter(lengths []uint8) (*ipcounter.IPCounter, error) {
+	prefixes, err := ipcounter.NewIPPrefixes(lengths...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create PrefixCounter: %v", err)
+	}
+	return ipcounter.NewIPCounter(prefixes, true, nil), nil
+}
+
+func createSetCounter(hasher ipcounter.Hasher) (*ipcounter.IPCounter, error) {
 	s, _ := ipcounter.NewSet()
-	return ipcounter.NewIPCounter(s, true, false), nil
+	return ipcounter.NewIPCounter(s, true, hasher), nil
+}
+
+// createFrequencyMap creates the heavy hitters counter, the approximate ones monitor size IPs.
+// Count-Min overestimates the counts by at most 1/size of all IPs with probability 99.9%.
+func createFrequencyMap(name string, size int) (ipcounter.FrequencyMap, error) {
+	switch name {
+	case "exact":
+		frequency, err := ipcounter.NewIPFrequency()
+		if err != nil {
+			return nil, err
+		}
+		return frequency.(ipcounter.FrequencyMap), nil
+	case "spacesaving":
+		return ipcounter.NewSpaceSaving(size)
+	case "countmin":
+		if size <= 0 {
+			return nil, fmt.Errorf("invalid heavy hitters size: %d", size)
+		}
+		return ipcounter.NewCountMin(1/float64(size), 0.001, size)
+	}
+	return nil, fmt.Errorf("invalid heavy hitters counter: %s", name)
+}
+
+// createIPv6Map creates the IPv6 counter matching the IPv4 counter type, exact counters use a set
+func createIPv6Map(counterType string) (ipcounter.IPv6Map, error) {
+	switch counterType {
+	case hyperLogLogType:
+		sketch, err := ipcounter.NewHyperLogLog(14)
+		if err != nil {
+			return nil, err
+		}
+		return ipcounter.NewIPv6Sketch(sketch), nil
+	case hyperLogLogPlusType:
+		sketch, err := ipcounter.NewHyperLogLogPlus(14)
+		if err != nil {
+			return nil, err
+		}
+		return ipcounter.NewIPv6Sketch(sketch), nil
+	default:
+		return ipcounter.NewIPv6Set(), nil
+	}
 }
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"net/netip"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// runMainEnv makes the test binary run the command instead of the tests
+const runMainEnv = "IPCOUNTER_RUN_MAIN"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(runMainEnv) == "1" {
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func TestExportToStdout(t *testing.T) {
+	testCases := []struct {
+		format        string
+		parse         func(string) error
+		expectedLines int
+	}{
+		{"ips", func(line string) error { _, err := netip.ParseAddr(line); return err }, 1000},
+		{"cidr", func(line string) error { _, err := netip.ParsePrefix(line); return err }, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.format, func(t *testing.T) {
+			// The summary, -stats, -per-file and the heavy hitters must not end up in the exported stream
+			cmd := exec.Command(os.Args[0], "-counter", setType, "-export", stdoutPath, "-export-format", tc.format,
+				"-stats", "-per-file", "-heavy-hitters", "exact", "ipcounter/ipsbig")
+			cmd.Env = append(os.Environ(), runMainEnv+"=1")
+			var stdout, stderr bytes.Buffer
+			cmd.Stdout, cmd.Stderr = &stdout, &stderr
+			if err := cmd.Run(); err != nil {
+				t.Fatalf("Unexpected error: %v\n%s", err, stderr.String())
+			}
+
+			lines := strings.Split(strings.TrimSuffix(stdout.String(), "\n"), "\n")
+			for _, line := range lines {
+				if err := tc.parse(line); err != nil {
+					t.Fatalf("Expected only %s in the exported stream, got %q", tc.format, line)
+				}
+			}
+			if tc.expectedLines != 0 && len(lines) != tc.expectedLines {
+				t.Errorf("Expected %d lines, got %d", tc.expectedLines, len(lines))
+			}
+			if !strings.Contains(stderr.String(), "set count: 1000\n") {
+				t.Errorf("Expected the summary on stderr, got %q", stderr.String())
+			}
+		})
+	}
+}